@@ -74,8 +74,14 @@ func getCheatCodeProviders() (*cheatCodeTracer, []*CheatCodeContract, error) {
 		return nil, nil, err
 	}
 
+	// Obtain the medusa fuzzer feedback pre-compile
+	medusaCheatCodeContract, err := getMedusaCheatCodeContract(tracer)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Return the tracer and precompiles
-	return tracer, []*CheatCodeContract{stdCheatCodeContract, consoleCheatCodeContract}, nil
+	return tracer, []*CheatCodeContract{stdCheatCodeContract, consoleCheatCodeContract, medusaCheatCodeContract}, nil
 }
 
 // newCheatCodeContract returns a new precompiledContract which uses the attached cheatCodeTracer for execution
@@ -75,6 +75,10 @@ type cheatCodeTracerCallFrame struct {
 type cheatCodeTracerResults struct {
 	// onChainRevertHooks describes hooks which are to be executed when the chain reverts.
 	onChainRevertHooks types.GenericHookFuncs
+
+	// fuzzerHints describes the feedback provided to the fuzzer through the medusa cheat code contract. This is nil
+	// if no feedback was provided.
+	fuzzerHints *FuzzerHintResults
 }
 
 // newCheatCodeTracer creates a cheatCodeTracer and returns it.
@@ -122,6 +126,14 @@ func (t *cheatCodeTracer) CurrentCallFrame() *cheatCodeTracerCallFrame {
 	return t.callFrames[t.callDepth]
 }
 
+// fuzzerHints returns the FuzzerHintResults for the current transaction, creating them if they do not yet exist.
+func (t *cheatCodeTracer) fuzzerHints() *FuzzerHintResults {
+	if t.results.fuzzerHints == nil {
+		t.results.fuzzerHints = &FuzzerHintResults{}
+	}
+	return t.results.fuzzerHints
+}
+
 // OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
 func (t *cheatCodeTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
 	// Reset our capture state
@@ -227,4 +239,9 @@ func (t *cheatCodeTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope t
 func (t *cheatCodeTracer) CaptureTxEndSetAdditionalResults(results *types.MessageResults) {
 	// Add our revert operations we collected for this transaction.
 	results.OnRevertHookFuncs = append(results.OnRevertHookFuncs, t.results.onChainRevertHooks...)
+
+	// Add any feedback the harness provided to the fuzzer.
+	if t.results.fuzzerHints != nil {
+		results.AdditionalResults[fuzzerHintResultsKey] = t.results.fuzzerHints
+	}
 }
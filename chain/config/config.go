@@ -1,6 +1,11 @@
 package config
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/vm"
 )
@@ -29,6 +34,77 @@ type CheatCodeConfig struct {
 	// EnableFFI describes whether the FFI cheat code should be enabled. Enablement allows for arbitrary code execution
 	// on the tester's machine
 	EnableFFI bool `json:"enableFFI"`
+
+	// FFIAllowedCommands describes the executable names or paths which the FFI cheat code is allowed to execute. Bare
+	// executable names must match the command exactly, while paths are compared to the resolved path of the command.
+	// If empty, any command may be executed.
+	FFIAllowedCommands []string `json:"ffiAllowedCommands"`
+
+	// FFITimeout describes the maximum amount of seconds a command executed by the FFI cheat code may run for before
+	// it is killed. If zero, commands are not timed out.
+	FFITimeout int `json:"ffiTimeout"`
+
+	// FFIWorkingDirectory describes the working directory commands executed by the FFI cheat code are run in. If
+	// empty, the current working directory is used.
+	FFIWorkingDirectory string `json:"ffiWorkingDirectory"`
+
+	// FFIEnvironmentWhitelist describes the names of environment variables which are passed to commands executed by
+	// the FFI cheat code. If empty, the full environment is passed.
+	FFIEnvironmentWhitelist []string `json:"ffiEnvironmentWhitelist"`
+}
+
+// IsFFICommandAllowed indicates whether the provided command may be executed by the FFI cheat code, given the
+// CheatCodeConfig.FFIAllowedCommands.
+// Returns a boolean indicating whether the command is allowed.
+func (c *CheatCodeConfig) IsFFICommandAllowed(command string) bool {
+	// If no allowlist was provided, all commands are allowed.
+	if len(c.FFIAllowedCommands) == 0 {
+		return true
+	}
+
+	// Resolve the path of the command, so it can be compared to allowed paths.
+	resolvedCommandPath := ""
+	if lookupPath, err := exec.LookPath(command); err == nil {
+		if absPath, err := filepath.Abs(lookupPath); err == nil {
+			resolvedCommandPath = absPath
+		}
+	}
+
+	// Check the command against each allowed entry.
+	for _, allowedCommand := range c.FFIAllowedCommands {
+		if !strings.ContainsAny(allowedCommand, `/\`) {
+			// Bare executable names must be an exact match, so a path cannot be used to shadow them.
+			if command == allowedCommand {
+				return true
+			}
+		} else if resolvedCommandPath != "" {
+			// Paths are compared against the resolved command path.
+			allowedPath, err := filepath.Abs(allowedCommand)
+			if err == nil && allowedPath == resolvedCommandPath {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FFIEnvironment obtains the environment which commands executed by the FFI cheat code should be run with, given
+// the CheatCodeConfig.FFIEnvironmentWhitelist.
+// Returns the environment as a list of "key=value" strings, or nil if the full environment should be inherited.
+func (c *CheatCodeConfig) FFIEnvironment() []string {
+	// If no whitelist was provided, the full environment is inherited.
+	if len(c.FFIEnvironmentWhitelist) == 0 {
+		return nil
+	}
+
+	// Otherwise only pass through whitelisted variables which are set.
+	env := make([]string, 0)
+	for _, name := range c.FFIEnvironmentWhitelist {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
 }
 
 // GetVMConfigExtensions derives a vm.ConfigExtensions from the provided TestChainConfig.
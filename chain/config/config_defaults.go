@@ -7,8 +7,12 @@ func DefaultTestChainConfig() (*TestChainConfig, error) {
 	config := &TestChainConfig{
 		CodeSizeCheckDisabled: true,
 		CheatCodeConfig: CheatCodeConfig{
-			CheatCodesEnabled: true,
-			EnableFFI:         false,
+			CheatCodesEnabled:       true,
+			EnableFFI:               false,
+			FFIAllowedCommands:      []string{},
+			FFITimeout:              0,
+			FFIWorkingDirectory:     "",
+			FFIEnvironmentWhitelist: []string{},
 		},
 		SkipAccountChecks: true,
 	}
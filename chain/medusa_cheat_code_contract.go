@@ -0,0 +1,99 @@
+package chain
+
+import (
+	"math/big"
+
+	"github.com/crytic/medusa/chain/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/slices"
+)
+
+// MedusaCheatCodeContractAddress is the address for the medusa cheat code contract, which provides harnesses with a
+// channel to send feedback to the fuzzer.
+var MedusaCheatCodeContractAddress = common.HexToAddress("0x000000000000006d65647573612e66757a7a6572")
+
+// fuzzerHintResultsKey describes the key to use when storing fuzzer hint results in call message results, or when
+// querying them.
+const fuzzerHintResultsKey = "FuzzerHintResults"
+
+// FuzzerHintResults describes the feedback a harness provided to the fuzzer through the medusa cheat code contract
+// during the execution of a single transaction.
+type FuzzerHintResults struct {
+	// DictionaryValues describes values which the harness requested be added to the fuzzer's value dictionary. Values
+	// are of type *big.Int, common.Address, []byte or string.
+	DictionaryValues []any
+
+	// InterestingIds describes the identifiers the harness flagged as interesting. Each previously unseen identifier
+	// is treated as new coverage by the fuzzer.
+	InterestingIds []*big.Int
+}
+
+// GetFuzzerHintResults obtains FuzzerHintResults stored by the cheat code tracer from message results. This is nil
+// if the harness did not provide any feedback during this message execution.
+func GetFuzzerHintResults(messageResults *types.MessageResults) *FuzzerHintResults {
+	// Try to obtain the results the tracer should've stored.
+	if genericResult, ok := messageResults.AdditionalResults[fuzzerHintResultsKey]; ok {
+		if castedResult, ok := genericResult.(*FuzzerHintResults); ok {
+			return castedResult
+		}
+	}
+
+	// If we could not obtain them, return nil.
+	return nil
+}
+
+// getMedusaCheatCodeContract obtains a CheatCodeContract which implements medusa-specific cheat codes, used by harnesses
+// to provide feedback to the fuzzer.
+// Returns the precompiled contract, or an error if one occurs.
+func getMedusaCheatCodeContract(tracer *cheatCodeTracer) (*CheatCodeContract, error) {
+	// Create a new precompile to add methods to.
+	contract := newCheatCodeContract(tracer, MedusaCheatCodeContractAddress, "MedusaCheats")
+
+	// Define some basic ABI argument types
+	typeAddress, err := abi.NewType("address", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	typeBytes, err := abi.NewType("bytes", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	typeUint256, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	typeString, err := abi.NewType("string", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// addToDictionary: Adds a value to the fuzzer's value dictionary. Each supported type shares the same handler, as
+	// the fuzzer worker resolves the value type when consuming the hint.
+	addToDictionaryHandler := func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
+		value := inputs[0]
+		if b, ok := value.([]byte); ok {
+			value = slices.Clone(b)
+		}
+		hints := tracer.fuzzerHints()
+		hints.DictionaryValues = append(hints.DictionaryValues, value)
+		return nil, nil
+	}
+	contract.addMethod("addToDictionary", abi.Arguments{{Type: typeUint256}}, abi.Arguments{}, addToDictionaryHandler)
+	contract.addMethod("addToDictionary", abi.Arguments{{Type: typeAddress}}, abi.Arguments{}, addToDictionaryHandler)
+	contract.addMethod("addToDictionary", abi.Arguments{{Type: typeBytes}}, abi.Arguments{}, addToDictionaryHandler)
+	contract.addMethod("addToDictionary", abi.Arguments{{Type: typeString}}, abi.Arguments{}, addToDictionaryHandler)
+
+	// interesting: Flags the current execution as interesting to the fuzzer, under a harness-defined identifier.
+	contract.addMethod(
+		"interesting", abi.Arguments{{Type: typeUint256}}, abi.Arguments{},
+		func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
+			hints := tracer.fuzzerHints()
+			hints.InterestingIds = append(hints.InterestingIds, new(big.Int).Set(inputs[0].(*big.Int)))
+			return nil, nil
+		},
+	)
+
+	// Return our precompile contract information.
+	return contract, nil
+}
@@ -1,14 +1,18 @@
 package chain
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
+	compilationTypes "github.com/crytic/medusa/compilation/types"
 	"github.com/crytic/medusa/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -24,6 +28,13 @@ var StandardCheatcodeContractAddress = common.HexToAddress("0x7109709ECfa91a8062
 // MaxUint64 holds the max value an uint64 can take
 var _, MaxUint64 = utils.GetIntegerConstraints(false, 64)
 
+// StorageLayoutCheatCodeSignatures describes the signatures of the standard cheat codes which rely on the compiler's
+// storage layout of the contract they target.
+var StorageLayoutCheatCodeSignatures = []string{
+	"readStorage(address,string)",
+	"writeStorage(address,string,bytes32)",
+}
+
 // getStandardCheatCodeContract obtains a CheatCodeContract which implements common cheat codes.
 // Returns the precompiled contract, or an error if one occurs.
 func getStandardCheatCodeContract(tracer *cheatCodeTracer) (*CheatCodeContract, error) {
@@ -179,6 +190,35 @@ func getStandardCheatCodeContract(tracer *cheatCodeTracer) (*CheatCodeContract,
 		},
 	)
 
+	// ReadStorage: Loads the value of a named storage variable from a given account.
+	contract.addMethod(
+		"readStorage", abi.Arguments{{Type: typeAddress}, {Type: typeString}}, abi.Arguments{{Type: typeBytes32}},
+		func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
+			account := inputs[0].(common.Address)
+			location, revertData := resolveStorageVariable(tracer, "readStorage", account, inputs[1].(string))
+			if revertData != nil {
+				return nil, revertData
+			}
+			value := location.ExtractValue(tracer.chain.State().GetState(account, location.Slot))
+			return []any{value}, nil
+		},
+	)
+
+	// WriteStorage: Sets the value of a named storage variable in a given account.
+	contract.addMethod(
+		"writeStorage", abi.Arguments{{Type: typeAddress}, {Type: typeString}, {Type: typeBytes32}}, abi.Arguments{},
+		func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
+			account := inputs[0].(common.Address)
+			location, revertData := resolveStorageVariable(tracer, "writeStorage", account, inputs[1].(string))
+			if revertData != nil {
+				return nil, revertData
+			}
+			word := location.InsertValue(tracer.chain.State().GetState(account, location.Slot), inputs[2].([32]byte))
+			tracer.chain.State().SetState(account, location.Slot, word)
+			return nil, nil
+		},
+	)
+
 	// Etch: Sets the code for a given account.
 	contract.addMethod(
 		"etch", abi.Arguments{{Type: typeAddress}, {Type: typeBytes}}, abi.Arguments{},
@@ -306,49 +346,32 @@ func getStandardCheatCodeContract(tracer *cheatCodeTracer) (*CheatCodeContract,
 	contract.addMethod(
 		"ffi", abi.Arguments{{Type: typeStringSlice}}, abi.Arguments{{Type: typeBytes}},
 		func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
-			// Ensure FFI is enabled (this allows arbitrary code execution, so we expect it to be explicitly enabled).
-			if !tracer.chain.testChainConfig.CheatCodeConfig.EnableFFI {
-				// Make sure there is at least a command to run
-				return nil, cheatCodeRevertData([]byte("ffi is not enabled in the chain configuration"))
-			}
-
-			// command is cmdAndInputs[0] and args are cmdAndInputs[1:]
-			cmdAndInputs := inputs[0].([]string)
-
-			var command string
-			var args []string
-
-			if len(cmdAndInputs) < 1 {
-				// Make sure there is at least a command to run
-				return nil, cheatCodeRevertData([]byte("ffi: no command was provided"))
-			} else if len(cmdAndInputs) == 1 {
-				// It is possible there are no arguments provided
-				command = cmdAndInputs[0]
-			} else {
-				// Both a command and arguments have been provided
-				command = cmdAndInputs[0]
-				args = cmdAndInputs[1:]
-			}
-
-			// Create our command
-			cmd := exec.Command(command, args...)
+			return runFFICheatCode(tracer, "ffi", inputs[0].([]string), nil)
+		},
+	)
 
-			// Execute it and grab the output
-			stdout, _, combined, err := utils.RunCommandWithOutputAndError(cmd)
-			if err != nil {
-				errorMsg := fmt.Sprintf("ffi: cmd failed with the following error: %v\nOutput: %v", err, string(combined))
-				return nil, cheatCodeRevertData([]byte(errorMsg))
-			}
+	// FFI: Run arbitrary command on base OS, providing the given input to it through stdin
+	contract.addMethod(
+		"ffi", abi.Arguments{{Type: typeStringSlice}, {Type: typeBytes}}, abi.Arguments{{Type: typeBytes}},
+		func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
+			return runFFICheatCode(tracer, "ffi", inputs[0].([]string), inputs[1].([]byte))
+		},
+	)
 
-			// Attempt to hex decode the output
-			hexOut, err := hex.DecodeString(strings.TrimPrefix(string(stdout), "0x"))
-			if err != nil {
-				// Return the byte array as itself if hex decoding does not work
-				return []any{stdout}, nil
-			}
+	// tryFfi: Run arbitrary command on base OS, returning its exit code and output rather than reverting on failure.
+	contract.addMethod(
+		"tryFfi", abi.Arguments{{Type: typeStringSlice}}, abi.Arguments{{Type: typeInt256}, {Type: typeBytes}, {Type: typeBytes}},
+		func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
+			return runTryFFICheatCode(tracer, "tryFfi", inputs[0].([]string), nil)
+		},
+	)
 
-			// Hex decoding worked, so return that
-			return []any{hexOut}, nil
+	// tryFfi: Run arbitrary command on base OS, providing the given input to it through stdin, and returning its exit
+	// code and output rather than reverting on failure.
+	contract.addMethod(
+		"tryFfi", abi.Arguments{{Type: typeStringSlice}, {Type: typeBytes}}, abi.Arguments{{Type: typeInt256}, {Type: typeBytes}, {Type: typeBytes}},
+		func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
+			return runTryFFICheatCode(tracer, "tryFfi", inputs[0].([]string), inputs[1].([]byte))
 		},
 	)
 
@@ -528,3 +551,142 @@ func getStandardCheatCodeContract(tracer *cheatCodeTracer) (*CheatCodeContract,
 	// Return our precompile contract information.
 	return contract, nil
 }
+
+// resolveStorageVariable resolves the storage location of a named storage variable in the contract deployed at the
+// given account, using the storage layout of the contract. The provided cheat code name is used to prefix revert
+// reasons.
+// Returns the resolved storage location, or revert data describing why it could not be resolved.
+func resolveStorageVariable(tracer *cheatCodeTracer, cheatCodeName string, account common.Address, path string) (*compilationTypes.StorageLocation, *cheatCodeRawReturnData) {
+	// Obtain the storage layout for the contract at the given account.
+	var storageLayout *compilationTypes.StorageLayout
+	if tracer.chain.StorageLayoutResolver != nil {
+		storageLayout = tracer.chain.StorageLayoutResolver(account, tracer.chain.State().GetCode(account))
+	}
+	if storageLayout == nil {
+		errorMsg := fmt.Sprintf("%v: no storage layout is known for the contract at address %v", cheatCodeName, account.String())
+		return nil, cheatCodeRevertData([]byte(errorMsg))
+	}
+
+	// Resolve the path to our variable, ensuring it refers to a value we can read or write in a single slot.
+	location, err := storageLayout.ResolvePath(path)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%v: could not resolve storage path '%v': %v", cheatCodeName, path, err)
+		return nil, cheatCodeRevertData([]byte(errorMsg))
+	}
+	if !location.IsValueType() {
+		errorMsg := fmt.Sprintf("%v: storage path '%v' refers to a variable of type '%v', which is not a value type", cheatCodeName, path, location.Type.Label)
+		return nil, cheatCodeRevertData([]byte(errorMsg))
+	}
+	return location, nil
+}
+
+// ffiCommandResult describes the result of a command run by an FFI cheat code.
+type ffiCommandResult struct {
+	// stdout describes the output the command wrote to stdout.
+	stdout []byte
+	// stderr describes the output the command wrote to stderr.
+	stderr []byte
+	// combined describes the output the command wrote to both stdout and stderr, in the order it was written.
+	combined []byte
+	// err describes the error returned when running the command, if any (e.g. a non-zero exit code).
+	err error
+}
+
+// runFFICheatCode runs a command provided to the ffi cheat code, with the provided stdin input (if non-nil). The
+// provided cheat code name is used to prefix revert reasons.
+// Returns the output of the command, hex decoded if possible, or revert data if the command was not permitted to
+// run, timed out, or failed.
+func runFFICheatCode(tracer *cheatCodeTracer, cheatCodeName string, cmdAndInputs []string, stdin []byte) ([]any, *cheatCodeRawReturnData) {
+	// Run the command, reverting if it was not permitted or could not complete.
+	result, revertData := runFFICommand(tracer, cheatCodeName, cmdAndInputs, stdin)
+	if revertData != nil {
+		return nil, revertData
+	}
+	if result.err != nil {
+		errorMsg := fmt.Sprintf("%v: cmd failed with the following error: %v\nOutput: %v", cheatCodeName, result.err, string(result.combined))
+		return nil, cheatCodeRevertData([]byte(errorMsg))
+	}
+
+	// Return the output, hex decoded if possible.
+	return []any{decodeFFIOutput(result.stdout)}, nil
+}
+
+// runTryFFICheatCode runs a command provided to the tryFfi cheat code, with the provided stdin input (if non-nil).
+// The provided cheat code name is used to prefix revert reasons.
+// Returns the exit code of the command, its stdout (hex decoded if possible) and its stderr, or revert data if the
+// command was not permitted to run, timed out, or could not be started.
+func runTryFFICheatCode(tracer *cheatCodeTracer, cheatCodeName string, cmdAndInputs []string, stdin []byte) ([]any, *cheatCodeRawReturnData) {
+	// Run the command, reverting only if it was not permitted or could not complete.
+	result, revertData := runFFICommand(tracer, cheatCodeName, cmdAndInputs, stdin)
+	if revertData != nil {
+		return nil, revertData
+	}
+
+	// If the process could not be started at all, we have no exit code to report, so we revert.
+	exitCode := utils.GetCommandExitCode(result.err)
+	if exitCode < 0 {
+		errorMsg := fmt.Sprintf("%v: cmd failed with the following error: %v", cheatCodeName, result.err)
+		return nil, cheatCodeRevertData([]byte(errorMsg))
+	}
+
+	// Return the exit code and output, with stdout hex decoded if possible.
+	return []any{big.NewInt(int64(exitCode)), decodeFFIOutput(result.stdout), result.stderr}, nil
+}
+
+// runFFICommand runs a command provided to an FFI cheat code, enforcing the FFI restrictions set in the chain's cheat
+// code configuration. If stdin is non-nil, it is provided to the command as its standard input. The provided cheat
+// code name is used to prefix revert reasons.
+// Returns the result of running the command, or revert data describing the reason if the command was not permitted
+// to run or timed out.
+func runFFICommand(tracer *cheatCodeTracer, cheatCodeName string, cmdAndInputs []string, stdin []byte) (*ffiCommandResult, *cheatCodeRawReturnData) {
+	// Ensure FFI is enabled (this allows arbitrary code execution, so we expect it to be explicitly enabled).
+	cheatCodeConfig := &tracer.chain.testChainConfig.CheatCodeConfig
+	if !cheatCodeConfig.EnableFFI {
+		return nil, cheatCodeRevertData([]byte("ffi is not enabled in the chain configuration"))
+	}
+
+	// Make sure there is at least a command to run. The command is cmdAndInputs[0] and args are cmdAndInputs[1:]
+	if len(cmdAndInputs) < 1 {
+		return nil, cheatCodeRevertData([]byte(cheatCodeName + ": no command was provided"))
+	}
+	command := cmdAndInputs[0]
+	args := cmdAndInputs[1:]
+
+	// Ensure the command is allowed to be executed.
+	if !cheatCodeConfig.IsFFICommandAllowed(command) {
+		errorMsg := fmt.Sprintf("%v: command '%v' is not in the list of allowed ffi commands", cheatCodeName, command)
+		return nil, cheatCodeRevertData([]byte(errorMsg))
+	}
+
+	// Create our command with the configured working directory and environment, and any input provided.
+	cmd := exec.Command(command, args...)
+	cmd.Dir = cheatCodeConfig.FFIWorkingDirectory
+	cmd.Env = cheatCodeConfig.FFIEnvironment()
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	// Execute it and grab the output, enforcing our timeout.
+	timeout := time.Duration(cheatCodeConfig.FFITimeout) * time.Second
+	result := &ffiCommandResult{}
+	result.stdout, result.stderr, result.combined, result.err = utils.RunCommandWithTimeout(cmd, timeout)
+	if errors.Is(result.err, utils.ErrCommandTimedOut) {
+		errorMsg := fmt.Sprintf("%v: command '%v' timed out after %v second(s)", cheatCodeName, command, cheatCodeConfig.FFITimeout)
+		return nil, cheatCodeRevertData([]byte(errorMsg))
+	}
+	return result, nil
+}
+
+// decodeFFIOutput attempts to hex decode the output of a command run by an FFI cheat code.
+// Returns the decoded bytes if the output was hex encoded, otherwise the output itself is returned.
+func decodeFFIOutput(output []byte) []byte {
+	// Attempt to hex decode the output
+	hexOut, err := hex.DecodeString(strings.TrimPrefix(string(output), "0x"))
+	if err != nil {
+		// Return the byte array as itself if hex decoding does not work
+		return output
+	}
+
+	// Hex decoding worked, so return that
+	return hexOut
+}
@@ -16,6 +16,7 @@ import (
 
 	chainTypes "github.com/crytic/medusa/chain/types"
 	"github.com/crytic/medusa/chain/vendored"
+	compilationTypes "github.com/crytic/medusa/compilation/types"
 	"github.com/crytic/medusa/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -85,6 +86,11 @@ type TestChain struct {
 
 	// Events defines the event system for the TestChain.
 	Events TestChainEvents
+
+	// StorageLayoutResolver resolves the compiler storage layout for a contract deployed at a given address with the
+	// given runtime bytecode. It is used by cheat codes which access storage variables by name, and should return nil
+	// if no storage layout is known for the contract. If nil, such cheat codes will revert.
+	StorageLayoutResolver func(address common.Address, runtimeBytecode []byte) *compilationTypes.StorageLayout
 }
 
 // NewTestChain creates a simulated Ethereum backend used for testing, or returns an error if one occurred.
@@ -223,7 +229,8 @@ func (t *TestChain) Close() {
 }
 
 // Clone recreates the current TestChain state into a new instance. This simply reconstructs the block/chain state
-// but does not perform any other API-related changes such as adding additional tracers the original had. Additionally,
+// but does not perform any other API-related changes such as adding additional tracers the original had, other than
+// carrying over the StorageLayoutResolver. Additionally,
 // this does not clone pending blocks. The provided method, if non-nil, is used as callback to provide an intermediate
 // step between chain creation, and copying of all blocks, allowing for tracers to be added.
 // Returns the new chain, or an error if one occurred.
@@ -234,6 +241,9 @@ func (t *TestChain) Clone(onCreateFunc func(chain *TestChain) error) (*TestChain
 		return nil, err
 	}
 
+	// Carry over our storage layout resolver, as it is not tied to the chain state.
+	targetChain.StorageLayoutResolver = t.StorageLayoutResolver
+
 	// If we have a provided function for our creation event, execute it now
 	if onCreateFunc != nil {
 		err = onCreateFunc(targetChain)
@@ -559,6 +569,13 @@ func (t *TestChain) RevertToBlockNumber(blockNumber uint64) error {
 // current pending state (or committed state if none is pending) will be used instead.
 // The state executed over may be a pending block state.
 func (t *TestChain) CallContract(msg *core.Message, state *state.StateDB, additionalTracers ...*TestChainTracer) (*core.ExecutionResult, error) {
+	return t.CallContractWithHeader(msg, state, t.Head().Header, additionalTracers...)
+}
+
+// CallContractWithHeader performs a message call in the same manner as CallContract, but executes it as if it were
+// included in a block with the provided header, rather than the current chain head. This allows a call to observe the
+// block number and timestamp of a block which has not yet been created.
+func (t *TestChain) CallContractWithHeader(msg *core.Message, state *state.StateDB, header *types.Header, additionalTracers ...*TestChainTracer) (*core.ExecutionResult, error) {
 	// If our provided state is nil, use our current chain state.
 	if state == nil {
 		state = t.state
@@ -572,7 +589,7 @@ func (t *TestChain) CallContract(msg *core.Message, state *state.StateDB, additi
 
 	// Create our transaction and block contexts for the vm
 	txContext := core.NewEVMTxContext(msg)
-	blockContext := newTestChainBlockContext(t, t.Head().Header)
+	blockContext := newTestChainBlockContext(t, header)
 
 	// Create a new call tracer router that incorporates any additional tracers provided just for this call, while
 	// still calling our internal tracers.
@@ -625,6 +642,67 @@ func (t *TestChain) CallContract(msg *core.Message, state *state.StateDB, additi
 	return msgResult, err
 }
 
+// ApplyMessage performs a message call over the provided state, as if it were included in a block with the provided
+// header, and obtains a core.ExecutionResult. Unlike CallContract, changes made by the call are kept in the provided
+// state, so a series of calls can be executed over a state which is detached from the chain (e.g. a copy of the state
+// after some block). Unlike PendingBlockAddTx, no block, receipt or chain events are produced.
+func (t *TestChain) ApplyMessage(msg *core.Message, state *state.StateDB, header *types.Header, additionalTracers ...*TestChainTracer) (*core.ExecutionResult, error) {
+	// Create our transaction and block contexts for the vm
+	txContext := core.NewEVMTxContext(msg)
+	blockContext := newTestChainBlockContext(t, header)
+
+	// Create a new call tracer router that incorporates any additional tracers provided just for this call, while
+	// still calling our internal tracers.
+	extendedTracerRouter := NewTestChainTracerRouter()
+	extendedTracerRouter.AddTracer(t.callTracerRouter.NativeTracer())
+	extendedTracerRouter.AddTracers(additionalTracers...)
+
+	// Create our EVM instance.
+	evm := vm.NewEVM(blockContext, txContext, state, t.chainConfig, vm.Config{
+		Tracer:           extendedTracerRouter.NativeTracer().Tracer.Hooks,
+		NoBaseFee:        true,
+		ConfigExtensions: t.vmConfigExtensions,
+	})
+	// Set our block context and chain config in order for cheatcodes to override what EVM interpreter sees.
+	t.pendingBlockContext = &evm.Context
+	t.pendingBlockChainConfig = evm.ChainConfig()
+
+	// Create a tx from our msg, for hashing/receipt purposes
+	tx := utils.MessageToTransaction(msg)
+
+	// Need to explicitly call OnTxStart hook
+	if evm.Config.Tracer != nil && evm.Config.Tracer.OnTxStart != nil {
+		evm.Config.Tracer.OnTxStart(evm.GetVMContext(), tx, msg.From)
+	}
+	// Fund the gas pool, so it can execute endlessly (no block gas limit).
+	gasPool := new(core.GasPool).AddGas(math.MaxUint64)
+
+	// Perform our state transition to obtain the result, and finalize it so the next call observes it as a separate
+	// transaction.
+	msgResult, err := core.ApplyMessage(evm, msg, gasPool)
+	if err != nil {
+		return nil, err
+	}
+	state.Finalise(true)
+
+	// Gather receipt for OnTxEnd
+	receipt := &types.Receipt{Type: tx.Type()}
+	if msgResult.Failed() {
+		receipt.Status = types.ReceiptStatusFailed
+	} else {
+		receipt.Status = types.ReceiptStatusSuccessful
+	}
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = msgResult.UsedGas
+
+	// Need to explicitly call OnTxEnd
+	if evm.Config.Tracer != nil && evm.Config.Tracer.OnTxEnd != nil {
+		evm.Config.Tracer.OnTxEnd(receipt, err)
+	}
+
+	return msgResult, nil
+}
+
 // PendingBlock describes the current pending block which is being constructed and awaiting commitment to the chain.
 // This may be nil if no pending block was created.
 func (t *TestChain) PendingBlock() *chainTypes.Block {
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crytic/medusa/cmd/exitcodes"
+	"github.com/crytic/medusa/fuzzing"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// corpusCmd represents the command provider for corpus management
+var corpusCmd = &cobra.Command{
+	Use:   "corpus",
+	Short: "Manages the corpus of a project",
+	Long:  `Manages the corpus of a project`,
+}
+
+// corpusMinimizeCmd represents the command provider for corpus minimization
+var corpusMinimizeCmd = &cobra.Command{
+	Use:               "minimize",
+	Short:             "Minimizes the corpus while preserving its coverage",
+	Long:              `Replays every call sequence in the corpus and keeps a minimal set which preserves the coverage achieved, preferring shorter call sequences. Call sequences which are not kept are moved to an archive directory.`,
+	Args:              cmdValidateCorpusMinimizeArgs,
+	ValidArgsFunction: cmdValidCorpusMinimizeArgs,
+	RunE:              cmdRunCorpusMinimize,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+}
+
+func init() {
+	// Add all the flags allowed for the corpus minimize command
+	err := addCorpusMinimizeFlags()
+	if err != nil {
+		cmdLogger.Panic("Failed to initialize the corpus minimize command", err)
+	}
+
+	// Add the corpus command and its sub-commands to the root command
+	corpusCmd.AddCommand(corpusMinimizeCmd)
+	rootCmd.AddCommand(corpusCmd)
+}
+
+// cmdValidCorpusMinimizeArgs will return which flags are valid for dynamic completion for the corpus minimize command
+func cmdValidCorpusMinimizeArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Gather a list of flags that are available to be used in the current command but have not been used yet
+	var unusedFlags []string
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed {
+			unusedFlags = append(unusedFlags, "--"+flag.Name)
+		}
+	})
+	return unusedFlags, cobra.ShellCompDirectiveNoFileComp
+}
+
+// cmdValidateCorpusMinimizeArgs makes sure that there are no positional arguments provided to the corpus minimize
+// command
+func cmdValidateCorpusMinimizeArgs(cmd *cobra.Command, args []string) error {
+	// Make sure we have no positional args
+	if err := cobra.NoArgs(cmd, args); err != nil {
+		err = fmt.Errorf("corpus minimize does not accept any positional arguments, only flags and their associated values")
+		cmdLogger.Error("Failed to validate args to the corpus minimize command", err)
+		return err
+	}
+	return nil
+}
+
+// cmdRunCorpusMinimize executes the CLI corpus minimize command. The project is compiled and deployed as it would be
+// for a fuzzing campaign, after which the corpus in the configured corpus directory is minimized.
+func cmdRunCorpusMinimize(cmd *cobra.Command, args []string) error {
+	// Read the project configuration
+	projectConfig, configPath, err := readProjectConfig(cmd, "corpus minimize")
+	if err != nil {
+		return err
+	}
+
+	// Update the project configuration given whatever flags were set using the CLI
+	err = updateProjectConfigWithCorpusMinimizeFlags(cmd, projectConfig)
+	if err != nil {
+		cmdLogger.Error("Failed to run the corpus minimize command", err)
+		return err
+	}
+
+	// We need a corpus directory to minimize.
+	if projectConfig.Fuzzing.CorpusDirectory == "" {
+		err = fmt.Errorf("a corpus directory must be provided through the project configuration or --corpus-dir")
+		cmdLogger.Error("Failed to run the corpus minimize command", err)
+		return err
+	}
+
+	// Determine the archive directory, defaulting to one within the corpus directory.
+	archiveDirectory, err := cmd.Flags().GetString("archive-dir")
+	if err != nil {
+		cmdLogger.Error("Failed to run the corpus minimize command", err)
+		return err
+	}
+	if archiveDirectory == "" {
+		archiveDirectory = filepath.Join(projectConfig.Fuzzing.CorpusDirectory, "archive", "call_sequences")
+	}
+
+	// Change our working directory to the parent directory of the project configuration file, as paths within the
+	// configuration are relative to it.
+	err = os.Chdir(filepath.Dir(configPath))
+	if err != nil {
+		cmdLogger.Error("Failed to run the corpus minimize command", err)
+		return err
+	}
+
+	// Create our fuzzer, which compiles the project.
+	fuzzer, err := fuzzing.NewFuzzer(*projectConfig)
+	if err != nil {
+		return exitcodes.NewErrorWithExitCode(err, exitcodes.ExitCodeHandledError)
+	}
+
+	// Minimize the corpus and report the outcome.
+	result, err := fuzzer.MinimizeCorpus(archiveDirectory)
+	if err != nil {
+		return exitcodes.NewErrorWithExitCode(err, exitcodes.ExitCodeHandledError)
+	}
+	cmdLogger.Info(
+		colors.Bold, "corpus: ", colors.Reset,
+		"kept ", colors.Bold, result.KeptSequences, colors.Reset, " of ", colors.Bold, result.TotalSequences, colors.Reset, " call sequences, ",
+		"archived ", colors.Bold, result.RedundantSequences, colors.Reset, " redundant and ", colors.Bold, result.InvalidSequences, colors.Reset, " invalid call sequences",
+	)
+	if result.RedundantSequences+result.InvalidSequences > 0 {
+		cmdLogger.Info("Archived call sequences were moved to: ", colors.Bold, result.ArchiveDirectory, colors.Reset)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/spf13/cobra"
+)
+
+// addCorpusMinimizeFlags adds the various flags for the corpus minimize command
+func addCorpusMinimizeFlags() error {
+	// Get the default project config and throw an error if we cant
+	defaultConfig, err := config.GetDefaultProjectConfig(DefaultCompilationPlatform)
+	if err != nil {
+		return err
+	}
+
+	// Prevent alphabetical sorting of usage message
+	corpusMinimizeCmd.Flags().SortFlags = false
+
+	// Config file
+	corpusMinimizeCmd.Flags().String("config", "", "path to config file")
+
+	// Compilation Target
+	corpusMinimizeCmd.Flags().String("compilation-target", "", TargetFlagDescription)
+
+	// Corpus directory
+	corpusMinimizeCmd.Flags().String("corpus-dir", "",
+		fmt.Sprintf("directory path for corpus items to minimize (unless a config file is provided, default is %q)", defaultConfig.Fuzzing.CorpusDirectory))
+
+	// Archive directory
+	corpusMinimizeCmd.Flags().String("archive-dir", "",
+		"directory path to move call sequences removed from the corpus to (default is \"archive/call_sequences\" within the corpus directory)")
+
+	return nil
+}
+
+// updateProjectConfigWithCorpusMinimizeFlags will update the given projectConfig with any CLI arguments that were
+// provided to the corpus minimize command
+func updateProjectConfigWithCorpusMinimizeFlags(cmd *cobra.Command, projectConfig *config.ProjectConfig) error {
+	var err error
+
+	// If --compilation-target was used
+	if cmd.Flags().Changed("compilation-target") {
+		// Get the new target
+		newTarget, err := cmd.Flags().GetString("compilation-target")
+		if err != nil {
+			return err
+		}
+
+		err = projectConfig.Compilation.SetTarget(newTarget)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update corpus directory
+	if cmd.Flags().Changed("corpus-dir") {
+		projectConfig.Fuzzing.CorpusDirectory, err = cmd.Flags().GetString("corpus-dir")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
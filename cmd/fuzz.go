@@ -7,10 +7,8 @@ import (
 	"path/filepath"
 
 	"github.com/crytic/medusa/cmd/exitcodes"
-	"github.com/crytic/medusa/logging/colors"
 
 	"github.com/crytic/medusa/fuzzing"
-	"github.com/crytic/medusa/fuzzing/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -70,65 +68,14 @@ func cmdValidateFuzzArgs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// cmdRunFuzz executes the CLI fuzz command and navigates through the following possibilities:
-// #1: We will search for either a custom config file (via --config) or the default (medusa.json).
-// If we find it, read it. If we can't read it, throw an error.
-// #2: If a custom file was provided (--config was used), and we can't find the file, throw an error.
-// #3: If medusa.json can't be found, use the default project configuration.
+// cmdRunFuzz executes the CLI fuzz command. The project configuration is resolved by readProjectConfig.
 func cmdRunFuzz(cmd *cobra.Command, args []string) error {
-	var projectConfig *config.ProjectConfig
-
-	// Check to see if --config flag was used and store the value of --config flag
-	configFlagUsed := cmd.Flags().Changed("config")
-	configPath, err := cmd.Flags().GetString("config")
+	// Read the project configuration
+	projectConfig, configPath, err := readProjectConfig(cmd, "fuzz")
 	if err != nil {
-		cmdLogger.Error("Failed to run the fuzz command", err)
 		return err
 	}
 
-	// If --config was not used, look for `medusa.json` in the current work directory
-	if !configFlagUsed {
-		workingDirectory, err := os.Getwd()
-		if err != nil {
-			cmdLogger.Error("Failed to run the fuzz command", err)
-			return err
-		}
-		configPath = filepath.Join(workingDirectory, DefaultProjectConfigFilename)
-	}
-
-	// Check to see if the file exists at configPath
-	_, existenceError := os.Stat(configPath)
-
-	// Possibility #1: File was found
-	if existenceError == nil {
-		// Try to read the configuration file and throw an error if something goes wrong
-		cmdLogger.Info("Reading the configuration file at: ", colors.Bold, configPath, colors.Reset)
-		// Use the default compilation platform if the config file doesn't specify one
-		projectConfig, err = config.ReadProjectConfigFromFile(configPath, DefaultCompilationPlatform)
-		if err != nil {
-			cmdLogger.Error("Failed to run the fuzz command", err)
-			return err
-		}
-	}
-
-	// Possibility #2: If the --config flag was used, and we couldn't find the file, we'll throw an error
-	if configFlagUsed && existenceError != nil {
-		cmdLogger.Error("Failed to run the fuzz command", err)
-		return existenceError
-	}
-
-	// Possibility #3: --config flag was not used and medusa.json was not found, so use the default project config
-	if !configFlagUsed && existenceError != nil {
-		cmdLogger.Warn(fmt.Sprintf("Unable to find the config file at %v, will use the default project configuration for the "+
-			"%v compilation platform instead", configPath, DefaultCompilationPlatform))
-
-		projectConfig, err = config.GetDefaultProjectConfig(DefaultCompilationPlatform)
-		if err != nil {
-			cmdLogger.Error("Failed to run the fuzz command", err)
-			return err
-		}
-	}
-
 	// Update the project configuration given whatever flags were set using the CLI
 	err = updateProjectConfigWithFuzzFlags(cmd, projectConfig)
 	if err != nil {
@@ -170,7 +117,8 @@ func cmdRunFuzz(cmd *cobra.Command, args []string) error {
 		return exitcodes.NewErrorWithExitCode(fuzzErr, exitcodes.ExitCodeHandledError)
 	}
 
-	// If we have no error and failed test cases, we'll want to return a special exit code
+	// If we have no error and failed test cases, we'll want to return a special exit code. Errored test cases, which
+	// could not be evaluated, and flaky test cases, whose failures did not reproduce, are not counted.
 	if fuzzErr == nil && len(fuzzer.TestCasesWithStatus(fuzzing.TestCaseStatusFailed)) > 0 {
 		return exitcodes.NewErrorWithExitCode(fuzzErr, exitcodes.ExitCodeTestFailed)
 	}
@@ -40,6 +40,10 @@ func addFuzzFlags() error {
 	fuzzCmd.Flags().Int("seq-len", 0,
 		fmt.Sprintf("maximum transactions to run in sequence (unless a config file is provided, default is %d)", defaultConfig.Fuzzing.CallSequenceLength))
 
+	// Seed
+	fuzzCmd.Flags().Int64("seed", 0,
+		"seed for every random provider used by the fuzzer, to reproduce a campaign (unless a config file is provided, default is 0). 0 means that a seed is chosen randomly")
+
 	// Target contracts
 	fuzzCmd.Flags().StringSlice("target-contracts", []string{},
 		fmt.Sprintf("target contracts for fuzz testing (unless a config file is provided, default is %v)", defaultConfig.Fuzzing.TargetContracts))
@@ -127,6 +131,14 @@ func updateProjectConfigWithFuzzFlags(cmd *cobra.Command, projectConfig *config.
 		}
 	}
 
+	// Update seed
+	if cmd.Flags().Changed("seed") {
+		projectConfig.Fuzzing.Seed, err = cmd.Flags().GetInt64("seed")
+		if err != nil {
+			return err
+		}
+	}
+
 	// Update target contracts
 	if cmd.Flags().Changed("target-contracts") {
 		projectConfig.Fuzzing.TargetContracts, err = cmd.Flags().GetStringSlice("target-contracts")
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/spf13/cobra"
+)
+
+// readProjectConfig reads the project configuration for a CLI command which supports the --config flag, navigating
+// through the following possibilities:
+// #1: We will search for either a custom config file (via --config) or the default (medusa.json).
+// If we find it, read it. If we can't read it, throw an error.
+// #2: If a custom file was provided (--config was used), and we can't find the file, throw an error.
+// #3: If medusa.json can't be found, use the default project configuration.
+// Returns the project configuration, the path of the configuration file, or an error if one occurred.
+func readProjectConfig(cmd *cobra.Command, commandName string) (*config.ProjectConfig, string, error) {
+	var projectConfig *config.ProjectConfig
+
+	// Check to see if --config flag was used and store the value of --config flag
+	configFlagUsed := cmd.Flags().Changed("config")
+	configPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		cmdLogger.Error(fmt.Sprintf("Failed to run the %s command", commandName), err)
+		return nil, "", err
+	}
+
+	// If --config was not used, look for `medusa.json` in the current work directory
+	if !configFlagUsed {
+		workingDirectory, err := os.Getwd()
+		if err != nil {
+			cmdLogger.Error(fmt.Sprintf("Failed to run the %s command", commandName), err)
+			return nil, "", err
+		}
+		configPath = filepath.Join(workingDirectory, DefaultProjectConfigFilename)
+	}
+
+	// Check to see if the file exists at configPath
+	_, existenceError := os.Stat(configPath)
+
+	// Possibility #1: File was found
+	if existenceError == nil {
+		// Try to read the configuration file and throw an error if something goes wrong
+		cmdLogger.Info("Reading the configuration file at: ", colors.Bold, configPath, colors.Reset)
+		// Use the default compilation platform if the config file doesn't specify one
+		projectConfig, err = config.ReadProjectConfigFromFile(configPath, DefaultCompilationPlatform)
+		if err != nil {
+			cmdLogger.Error(fmt.Sprintf("Failed to run the %s command", commandName), err)
+			return nil, "", err
+		}
+	}
+
+	// Possibility #2: If the --config flag was used, and we couldn't find the file, we'll throw an error
+	if configFlagUsed && existenceError != nil {
+		cmdLogger.Error(fmt.Sprintf("Failed to run the %s command", commandName), err)
+		return nil, "", existenceError
+	}
+
+	// Possibility #3: --config flag was not used and medusa.json was not found, so use the default project config
+	if !configFlagUsed && existenceError != nil {
+		cmdLogger.Warn(fmt.Sprintf("Unable to find the config file at %v, will use the default project configuration for the "+
+			"%v compilation platform instead", configPath, DefaultCompilationPlatform))
+
+		projectConfig, err = config.GetDefaultProjectConfig(DefaultCompilationPlatform)
+		if err != nil {
+			cmdLogger.Error(fmt.Sprintf("Failed to run the %s command", commandName), err)
+			return nil, "", err
+		}
+	}
+
+	return projectConfig, configPath, nil
+}
@@ -1,10 +1,29 @@
 package abiutils
 
 import (
+	"strings"
+
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	coreTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// GetSignatureHash obtains the Keccak256 hash of a canonical event or error signature (e.g. "AssertionFailed(string)").
+// Any whitespace in the signature is ignored. The full hash is used as the first topic of an event log, while its
+// leading four bytes are used as the selector of a custom error.
+func GetSignatureHash(signature string) common.Hash {
+	return crypto.Keccak256Hash([]byte(strings.Join(strings.Fields(signature), "")))
+}
+
+// IsValidSignature checks whether the provided string is formatted like an event or error signature, consisting of
+// an identifier followed by a parenthesized list of parameter types (e.g. "AssertionFailed(string)").
+func IsValidSignature(signature string) bool {
+	signature = strings.Join(strings.Fields(signature), "")
+	openIndex := strings.Index(signature, "(")
+	return openIndex > 0 && strings.HasSuffix(signature, ")")
+}
+
 // UnpackEventAndValues takes a given contract ABI, and an emitted event log from VM, and attempts to find an
 // event definition for the log, and unpack its input values.
 // Returns the event definition and unpacked event input values, or nil for both if an event definition could not
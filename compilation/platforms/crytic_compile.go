@@ -154,6 +154,7 @@ func (c *CryticCompilationConfig) Compile() ([]types.Compilation, string, error)
 		Abi           any    `json:"abi"`
 		Bin           string `json:"bin"`
 		BinRuntime    string `json:"bin-runtime"`
+		StorageLayout any    `json:"storage-layout"`
 	}
 	type solcExportData struct {
 		Sources   map[string]solcSourceUnit     `json:"sources"`
@@ -250,6 +251,19 @@ func (c *CryticCompilationConfig) Compile() ([]types.Compilation, string, error)
 				return nil, "", fmt.Errorf("unable to parse runtime bytecode for contract '%s'\n", contractName)
 			}
 
+			// Parse the storage layout, if one was provided. crytic-compile does not request the storage layout from
+			// the compiler, nor does its solc export format include one, so this is only populated by exports which
+			// were produced with one (e.g. by a future crytic-compile version). Until then, cheat codes which rely on
+			// the storage layout are only supported when compiling with the solc platform, and the fuzzer fails to
+			// start if a contract calls them without a storage layout.
+			var storageLayout *types.StorageLayout
+			if contract.StorageLayout != nil {
+				storageLayout, err = types.ParseStorageLayoutFromInterface(contract.StorageLayout)
+				if err != nil {
+					return nil, "", fmt.Errorf("unable to parse storage layout for contract '%s'\n", contractName)
+				}
+			}
+
 			// Add contract details
 			compilation.SourcePathToArtifact[sourcePath].Contracts[contractName] = types.CompiledContract{
 				Abi:             *contractAbi,
@@ -258,6 +272,7 @@ func (c *CryticCompilationConfig) Compile() ([]types.Compilation, string, error)
 				SrcMapsInit:     contract.SrcMap,
 				SrcMapsRuntime:  contract.SrcMapRuntime,
 				Kind:            contractKinds[contractName],
+				StorageLayout:   storageLayout,
 			}
 		}
 
@@ -67,14 +67,25 @@ func (s *SolcCompilationConfig) SetSolcOutputOptions(v *semver.Version) string {
 		(v.Major() == 0 && v.Minor() == 7 && v.Patch() <= 6) ||
 		(v.Major() == 0 && v.Minor() == 8 && v.Patch() <= 9)
 
+	// useStorageLayout will add the storage-layout output option if version is 0.5.13 or greater
+	useStorageLayout := v.Major() > 0 ||
+		(v.Minor() == 5 && v.Patch() >= 13) ||
+		v.Minor() > 5
+
 	// if version is 0.3.0-0.3.6 or 0.4.0-0.4.11 no 'hashes' outputOption
 	if (v.Major() == 0 && v.Minor() == 4 && v.Patch() <= 11) || (v.Major() == 0 && v.Minor() == 3 && v.Patch() <= 6) {
 		return "abi,ast,bin,bin-runtime,srcmap,srcmap-runtime,userdoc,devdoc"
 	} else if useCompactFormat {
 		// Both 'hashes' and 'compact-format' are allowed as outputOptions
+		if useStorageLayout {
+			return "abi,ast,bin,bin-runtime,srcmap,srcmap-runtime,userdoc,devdoc,hashes,compact-format,storage-layout"
+		}
 		return "abi,ast,bin,bin-runtime,srcmap,srcmap-runtime,userdoc,devdoc,hashes,compact-format"
 	} else {
 		// Can't use 'compact-format' but 'hashes' is allowed as outputOption
+		if useStorageLayout {
+			return "abi,ast,bin,bin-runtime,srcmap,srcmap-runtime,userdoc,devdoc,hashes,storage-layout"
+		}
 		return "abi,ast,bin,bin-runtime,srcmap,srcmap-runtime,userdoc,devdoc,hashes"
 	}
 }
@@ -165,6 +176,21 @@ func (s *SolcCompilationConfig) Compile() ([]types.Compilation, string, error) {
 		return nil, "", err
 	}
 
+	// Parse our contract storage layouts from solc output, as they are not parsed by go-ethereum
+	storageLayouts := make(map[string]*types.StorageLayout)
+	if contractResults, ok := results["contracts"].(map[string]any); ok {
+		for name, contract := range contractResults {
+			if contractDict, ok := contract.(map[string]any); ok {
+				if storageLayout, ok := contractDict["storage-layout"]; ok && storageLayout != nil {
+					storageLayouts[name], err = types.ParseStorageLayoutFromInterface(storageLayout)
+					if err != nil {
+						return nil, "", fmt.Errorf("unable to parse storage layout for contract '%s': %v\n", name, err)
+					}
+				}
+			}
+		}
+	}
+
 	for name, contract := range contracts {
 		// Split our name which should be of form "filename:contractname"
 		nameSplit := strings.Split(name, ":")
@@ -195,6 +221,7 @@ func (s *SolcCompilationConfig) Compile() ([]types.Compilation, string, error) {
 			SrcMapsInit:     contract.Info.SrcMap.(string),
 			SrcMapsRuntime:  contract.Info.SrcMapRuntime,
 			Kind:            contractKinds[contractName],
+			StorageLayout:   storageLayouts[name],
 		}
 	}
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 // ContractKind represents the kind of contract definition represented by an AST node
@@ -31,16 +32,67 @@ type FunctionDefinition struct {
 	// Src is the source file for this AST
 	Src  string `json:"src"`
 	Name string `json:"name,omitempty"`
+	// FunctionSelector is the hex-encoded selector of the function, if it is externally callable
+	FunctionSelector string `json:"functionSelector,omitempty"`
+	// Documentation is the NatSpec documentation of the function, if any was provided
+	Documentation *StructuredDocumentation `json:"documentation,omitempty"`
 }
 
 func (s FunctionDefinition) GetNodeType() string {
 	return s.NodeType
 }
 
+// StructuredDocumentation is the NatSpec documentation attached to a node
+type StructuredDocumentation struct {
+	// Text is the documentation text, without comment delimiters
+	Text string `json:"text"`
+}
+
+func (d *StructuredDocumentation) UnmarshalJSON(data []byte) error {
+	// Older compiler versions represent documentation as a plain string rather than a node
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		d.Text = text
+		return nil
+	}
+
+	type Alias StructuredDocumentation
+	return json.Unmarshal(data, (*Alias)(d))
+}
+
+// NatSpecCustomTag is a custom NatSpec tag of the form `@custom:<name> <value>`
+type NatSpecCustomTag struct {
+	// Name is the name of the tag, following the `@custom:` prefix
+	Name string
+	// Value is the text following the tag name, if any
+	Value string
+}
+
+// CustomTags returns the custom NatSpec tags in the documentation, in the order they were declared
+func (d *StructuredDocumentation) CustomTags() []NatSpecCustomTag {
+	re := regexp.MustCompile(`^@custom:([a-z][a-z0-9-]*)(?:\s+(.*))?$`)
+	tags := make([]NatSpecCustomTag, 0)
+	for _, line := range strings.Split(d.Text, "\n") {
+		// Strip any leading comment decorations from the line
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*/"))
+		if match := re.FindStringSubmatch(line); match != nil {
+			tags = append(tags, NatSpecCustomTag{Name: match[1], Value: strings.TrimSpace(match[2])})
+		}
+	}
+	return tags
+}
+
 // ContractDefinition is the contract definition node
 type ContractDefinition struct {
 	// NodeType represents the node type (currently we only evaluate source unit node types)
 	NodeType string `json:"nodeType"`
+	// ID is the unique identifier of the node within the compilation
+	ID int `json:"id"`
+	// Name is the name of the contract definition
+	Name string `json:"name,omitempty"`
+	// LinearizedBaseContracts are the IDs of the contract definitions this one inherits from, in order of
+	// linearization, beginning with this contract definition
+	LinearizedBaseContracts []int `json:"linearizedBaseContracts,omitempty"`
 	// Nodes is a list of Nodes within the AST
 	Nodes []Node `json:"nodes"`
 	// Src is the source file for this AST
@@ -159,6 +211,20 @@ func (a *AST) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ParseAST parses an abstract syntax tree artifact of a source file compilation into an AST
+func ParseAST(ast any) (*AST, error) {
+	b, err := json.Marshal(ast)
+	if err != nil {
+		return nil, err
+	}
+	var parsedAst AST
+	err = json.Unmarshal(b, &parsedAst)
+	if err != nil {
+		return nil, err
+	}
+	return &parsedAst, nil
+}
+
 // GetSrcMapSourceUnitID returns the source unit ID based on the source of the AST
 func GetSrcMapSourceUnitID(src string) int {
 	re := regexp.MustCompile(`[0-9]*:[0-9]*:([0-9]*)`)
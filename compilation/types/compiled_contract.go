@@ -32,6 +32,10 @@ type CompiledContract struct {
 
 	// Kind describes the kind of contract, i.e. contract, library, interface.
 	Kind ContractKind
+
+	// StorageLayout describes the layout of the contract's state variables in storage. This is nil if the compiler
+	// or compilation platform did not provide it.
+	StorageLayout *StorageLayout
 }
 
 // IsMatch returns a boolean indicating whether provided contract bytecode is a match to this compiled contract
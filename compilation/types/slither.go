@@ -6,7 +6,10 @@ import (
 	"github.com/crytic/medusa/logging"
 	"os"
 	"os/exec"
+	"sort"
 	"time"
+
+	"golang.org/x/exp/maps"
 )
 
 // SlitherConfig determines whether to run slither and whether and where to cache the results from slither
@@ -180,16 +183,17 @@ func (s *SlitherResults) UnmarshalJSON(d []byte) error {
 		return err
 	}
 
-	// Iterate across the constants in each contract
-	for _, constantsInContract := range constantsInContracts {
+	// Iterate across the constants in each contract, in sorted order so the constants are extracted deterministically
+	for _, contractName := range sortedKeys(constantsInContracts) {
 		// Capture all the constants in a given function
 		var constantsInFunctions map[string]json.RawMessage
-		if err := json.Unmarshal(constantsInContract, &constantsInFunctions); err != nil {
+		if err := json.Unmarshal(constantsInContracts[contractName], &constantsInFunctions); err != nil {
 			return err
 		}
 
 		// Iterate across each function
-		for _, constantsInFunction := range constantsInFunctions {
+		for _, functionName := range sortedKeys(constantsInFunctions) {
+			constantsInFunction := constantsInFunctions[functionName]
 			// Each constant is provided as its own list, so we need to create a matrix
 			var constants [][]Constant
 			if err := json.Unmarshal(constantsInFunction, &constants); err != nil {
@@ -205,3 +209,10 @@ func (s *SlitherResults) UnmarshalJSON(d []byte) error {
 
 	return nil
 }
+
+// sortedKeys returns the keys of the provided JSON object in sorted order.
+func sortedKeys(object map[string]json.RawMessage) []string {
+	keys := maps.Keys(object)
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,458 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// StorageLayout describes the storage layout of a contract, as output by the compiler's `storageLayout` artifact.
+type StorageLayout struct {
+	// Storage describes the state variables declared by the contract (including inherited ones), in declaration order.
+	Storage []StorageLayoutVariable `json:"storage"`
+
+	// Types describes a mapping of type identifiers referenced by variables to their type information.
+	Types map[string]StorageLayoutType `json:"types"`
+}
+
+// StorageLayoutVariable describes a single state variable (or struct member) within a StorageLayout.
+type StorageLayoutVariable struct {
+	// Label describes the name of the variable.
+	Label string `json:"label"`
+
+	// Offset describes the offset in bytes within the storage slot at which the variable begins, as variables may be
+	// packed into a single slot.
+	Offset int `json:"offset"`
+
+	// Slot describes the storage slot the variable begins at, as a base 10 string. For struct members, this is relative
+	// to the slot of the struct itself.
+	Slot string `json:"slot"`
+
+	// Type describes the type identifier of the variable, used to look up its StorageLayoutType.
+	Type string `json:"type"`
+}
+
+// StorageLayoutType describes a type referenced by variables in a StorageLayout.
+type StorageLayoutType struct {
+	// Encoding describes how the type is encoded in storage: "inplace", "mapping", "dynamic_array" or "bytes".
+	Encoding string `json:"encoding"`
+
+	// Label describes the canonical name of the type (e.g. "uint256", "mapping(address => uint256)").
+	Label string `json:"label"`
+
+	// NumberOfBytes describes the amount of bytes the type occupies in storage, as a base 10 string.
+	NumberOfBytes string `json:"numberOfBytes"`
+
+	// Key describes the type identifier of the key, if this is a mapping type.
+	Key string `json:"key,omitempty"`
+
+	// Value describes the type identifier of the value, if this is a mapping type.
+	Value string `json:"value,omitempty"`
+
+	// Base describes the type identifier of the elements, if this is an array type.
+	Base string `json:"base,omitempty"`
+
+	// Members describes the members of the type, if this is a struct type.
+	Members []StorageLayoutVariable `json:"members,omitempty"`
+}
+
+// StorageLocation describes the resolved location of a variable within contract storage.
+type StorageLocation struct {
+	// Slot describes the storage slot which the variable is stored in.
+	Slot common.Hash
+
+	// Offset describes the offset in bytes, from the lowest-order byte of the slot, at which the variable begins.
+	Offset int
+
+	// NumberOfBytes describes the amount of bytes the variable occupies within the slot. This is 32 for any variable
+	// which is not packed.
+	NumberOfBytes int
+
+	// Type describes the type of the variable stored at this location.
+	Type *StorageLayoutType
+}
+
+// IsValueType indicates whether the variable at this location is a value type which is fully contained in its slot,
+// as opposed to a mapping, struct, or array whose contents are spread across other slots. Dynamic arrays and
+// bytes/string variables are considered value types, as their slot holds their length (or short value).
+func (l *StorageLocation) IsValueType() bool {
+	switch {
+	case l.Type.Encoding == "dynamic_array" || l.Type.Encoding == "bytes":
+		return true
+	case l.Type.Encoding == "mapping" || l.Type.Base != "" || len(l.Type.Members) > 0:
+		return false
+	default:
+		return true
+	}
+}
+
+// ExtractValue extracts the value of the variable at this location from the provided word stored at its slot. Values
+// which occupy less than a full slot are returned right-aligned, with signed integers being sign-extended.
+func (l *StorageLocation) ExtractValue(word common.Hash) common.Hash {
+	// If the variable occupies the full slot, the value is the word itself.
+	if l.NumberOfBytes >= common.HashLength {
+		return word
+	}
+
+	// Otherwise copy the bytes the variable occupies into the lowest-order bytes of our value.
+	var value common.Hash
+	start := common.HashLength - l.Offset - l.NumberOfBytes
+	copy(value[common.HashLength-l.NumberOfBytes:], word[start:start+l.NumberOfBytes])
+
+	// Sign extend signed integers
+	if strings.HasPrefix(l.Type.Label, "int") && value[common.HashLength-l.NumberOfBytes]&0x80 != 0 {
+		for i := 0; i < common.HashLength-l.NumberOfBytes; i++ {
+			value[i] = 0xff
+		}
+	}
+	return value
+}
+
+// InsertValue inserts the provided value for the variable at this location into the provided word stored at its slot,
+// leaving any other variables packed into the same slot untouched. Values which occupy less than a full slot are
+// expected to be right-aligned, and are truncated to the size of the variable.
+// Returns the updated word to store at the slot.
+func (l *StorageLocation) InsertValue(word common.Hash, value common.Hash) common.Hash {
+	// If the variable occupies the full slot, the value replaces the word entirely.
+	if l.NumberOfBytes >= common.HashLength {
+		return value
+	}
+
+	// Otherwise copy the lowest-order bytes of our value into the bytes the variable occupies.
+	start := common.HashLength - l.Offset - l.NumberOfBytes
+	copy(word[start:start+l.NumberOfBytes], value[common.HashLength-l.NumberOfBytes:])
+	return word
+}
+
+// ParseStorageLayoutFromInterface parses a generic object into a StorageLayout and returns it, or an error if one
+// occurs. The object may either be a JSON string or an already decoded JSON object.
+func ParseStorageLayoutFromInterface(i any) (*StorageLayout, error) {
+	// If it's a string, just parse it. Otherwise, we assume it's an interface and serialize it into a string.
+	var (
+		b   []byte
+		err error
+	)
+	if s, ok := i.(string); ok {
+		b = []byte(s)
+	} else {
+		b, err = json.Marshal(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result StorageLayout
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ResolvePath resolves a path to a storage variable into its location in storage. The path starts with the name of a
+// state variable and may be followed by struct member accesses (e.g. `config.fee`), and mapping or array index accesses
+// (e.g. `balances[0xabc...]` or `values[3]`). Mapping keys are parsed according to the mapping's key type, where
+// strings may optionally be quoted and bytes are expected to be hex encoded.
+// Returns the resolved StorageLocation, or an error if the path could not be resolved.
+func (s *StorageLayout) ResolvePath(path string) (*StorageLocation, error) {
+	// Split our path into its individual components.
+	name, accessors, err := parseStorageVariablePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the root state variable. If a variable is shadowed, the most derived definition is last, so we search
+	// in reverse.
+	var location *StorageLocation
+	for i := len(s.Storage) - 1; i >= 0; i-- {
+		if s.Storage[i].Label == name {
+			location, err = s.memberLocation(common.Big0, &s.Storage[i])
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if location == nil {
+		return nil, fmt.Errorf("could not find a state variable named '%v'", name)
+	}
+
+	// Resolve each accessor in our path relative to the previously resolved location.
+	for _, accessor := range accessors {
+		if accessor.member != nil {
+			location, err = s.resolveMemberAccess(location, *accessor.member)
+		} else {
+			location, err = s.resolveIndexAccess(location, *accessor.index)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return location, nil
+}
+
+// memberLocation resolves the location of a variable declared relative to the provided base slot.
+// Returns the location of the variable, or an error if its type could not be found.
+func (s *StorageLayout) memberLocation(baseSlot *big.Int, variable *StorageLayoutVariable) (*StorageLocation, error) {
+	// Obtain the type of the variable
+	varType, err := s.lookupType(variable.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute the slot of the variable from the base slot
+	relativeSlot, ok := new(big.Int).SetString(variable.Slot, 10)
+	if !ok {
+		return nil, fmt.Errorf("could not parse slot '%v' for variable '%v'", variable.Slot, variable.Label)
+	}
+	slot := new(big.Int).Add(baseSlot, relativeSlot)
+
+	// Obtain the size of the variable. Anything that spans more than a slot is treated as occupying the full slot.
+	size, err := varType.numberOfBytes()
+	if err != nil {
+		return nil, err
+	}
+	if size > common.HashLength {
+		size = common.HashLength
+	}
+
+	return &StorageLocation{
+		Slot:          common.BigToHash(slot),
+		Offset:        variable.Offset,
+		NumberOfBytes: size,
+		Type:          varType,
+	}, nil
+}
+
+// resolveMemberAccess resolves the location of a struct member, relative to the location of the struct.
+// Returns the location of the member, or an error if one occurs.
+func (s *StorageLayout) resolveMemberAccess(location *StorageLocation, member string) (*StorageLocation, error) {
+	// Ensure our type has members to access
+	if len(location.Type.Members) == 0 {
+		return nil, fmt.Errorf("cannot access member '%v' of non-struct type '%v'", member, location.Type.Label)
+	}
+
+	// Find the member and resolve it relative to the slot of our struct.
+	for i := 0; i < len(location.Type.Members); i++ {
+		if location.Type.Members[i].Label == member {
+			return s.memberLocation(location.Slot.Big(), &location.Type.Members[i])
+		}
+	}
+	return nil, fmt.Errorf("type '%v' has no member named '%v'", location.Type.Label, member)
+}
+
+// resolveIndexAccess resolves the location of a mapping value or array element, relative to the location of the
+// mapping or array.
+// Returns the location of the value or element, or an error if one occurs.
+func (s *StorageLayout) resolveIndexAccess(location *StorageLocation, index string) (*StorageLocation, error) {
+	// If this is a mapping, the value's slot is derived from the hash of the key and the mapping slot.
+	if location.Type.Encoding == "mapping" {
+		keyType, err := s.lookupType(location.Type.Key)
+		if err != nil {
+			return nil, err
+		}
+		encodedKey, err := encodeStorageMappingKey(keyType, index)
+		if err != nil {
+			return nil, err
+		}
+		valueSlot := crypto.Keccak256Hash(encodedKey, location.Slot.Bytes())
+		return s.memberLocation(valueSlot.Big(), &StorageLayoutVariable{Slot: "0", Type: location.Type.Value})
+	}
+
+	// Otherwise, we expect an array.
+	if location.Type.Base == "" {
+		return nil, fmt.Errorf("cannot index into non-mapping, non-array type '%v'", location.Type.Label)
+	}
+	elementIndex, ok := new(big.Int).SetString(index, 0)
+	if !ok || elementIndex.Sign() < 0 {
+		return nil, fmt.Errorf("could not parse array index '%v'", index)
+	}
+
+	// Dynamic array elements are stored starting at the hash of the array slot, while static array elements are stored
+	// in place.
+	dataSlot := location.Slot.Big()
+	if location.Type.Encoding == "dynamic_array" {
+		dataSlot = crypto.Keccak256Hash(location.Slot.Bytes()).Big()
+	}
+
+	// Determine the size of each element to determine how elements are packed.
+	baseType, err := s.lookupType(location.Type.Base)
+	if err != nil {
+		return nil, err
+	}
+	elementSize, err := baseType.numberOfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Elements which fit in a slot with others are packed together, otherwise each element occupies whole slots.
+	elementSlot := new(big.Int)
+	elementOffset := 0
+	if elementSize <= common.HashLength/2 && elementSize > 0 {
+		elementsPerSlot := big.NewInt(int64(common.HashLength / elementSize))
+		slotIndex, slotOffset := new(big.Int).DivMod(elementIndex, elementsPerSlot, new(big.Int))
+		elementSlot.Add(dataSlot, slotIndex)
+		elementOffset = int(slotOffset.Int64()) * elementSize
+	} else {
+		slotsPerElement := big.NewInt(int64((elementSize + common.HashLength - 1) / common.HashLength))
+		elementSlot.Add(dataSlot, new(big.Int).Mul(elementIndex, slotsPerElement))
+	}
+	return s.memberLocation(elementSlot, &StorageLayoutVariable{Slot: "0", Offset: elementOffset, Type: location.Type.Base})
+}
+
+// lookupType obtains the type information for a given type identifier.
+// Returns the type, or an error if it could not be found.
+func (s *StorageLayout) lookupType(typeId string) (*StorageLayoutType, error) {
+	if t, ok := s.Types[typeId]; ok {
+		return &t, nil
+	}
+	return nil, fmt.Errorf("could not find type '%v' in storage layout", typeId)
+}
+
+// numberOfBytes parses the amount of bytes the type occupies in storage.
+// Returns the parsed amount, or an error if one occurs.
+func (t *StorageLayoutType) numberOfBytes() (int, error) {
+	size, err := strconv.Atoi(t.NumberOfBytes)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse size of type '%v': %v", t.Label, err)
+	}
+	return size, nil
+}
+
+// encodeStorageMappingKey encodes a mapping key provided as a string in the form it is hashed with when computing the
+// slot of a mapping value.
+// Returns the encoded key, or an error if the key could not be parsed as the provided type.
+func encodeStorageMappingKey(keyType *StorageLayoutType, key string) ([]byte, error) {
+	// Dynamically sized keys are hashed in their raw form, without any padding.
+	label := keyType.Label
+	if label == "string" {
+		if unquoted, err := strconv.Unquote(key); err == nil {
+			return []byte(unquoted), nil
+		}
+		return []byte(key), nil
+	} else if label == "bytes" {
+		b, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse mapping key '%v' as bytes: %v", key, err)
+		}
+		return b, nil
+	}
+
+	// Value types are hashed as a 32 byte word.
+	switch {
+	case label == "address" || strings.HasPrefix(label, "contract ") || strings.HasPrefix(label, "address "):
+		if !common.IsHexAddress(key) {
+			return nil, fmt.Errorf("could not parse mapping key '%v' as an address", key)
+		}
+		return common.LeftPadBytes(common.HexToAddress(key).Bytes(), common.HashLength), nil
+	case label == "bool":
+		b, err := strconv.ParseBool(key)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse mapping key '%v' as a bool", key)
+		}
+		if b {
+			return common.LeftPadBytes([]byte{1}, common.HashLength), nil
+		}
+		return make([]byte, common.HashLength), nil
+	case strings.HasPrefix(label, "uint") || strings.HasPrefix(label, "enum "):
+		value, ok := new(big.Int).SetString(key, 0)
+		if !ok || value.Sign() < 0 || value.BitLen() > 256 {
+			return nil, fmt.Errorf("could not parse mapping key '%v' as an unsigned integer", key)
+		}
+		return math.U256Bytes(value), nil
+	case strings.HasPrefix(label, "int"):
+		value, ok := new(big.Int).SetString(key, 0)
+		if !ok || value.BitLen() > 255 {
+			return nil, fmt.Errorf("could not parse mapping key '%v' as a signed integer", key)
+		}
+		return math.U256Bytes(value), nil
+	case strings.HasPrefix(label, "bytes"):
+		b, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+		if err != nil || len(b) > common.HashLength {
+			return nil, fmt.Errorf("could not parse mapping key '%v' as %v", key, label)
+		}
+		return common.RightPadBytes(b, common.HashLength), nil
+	default:
+		return nil, fmt.Errorf("unsupported mapping key type '%v'", label)
+	}
+}
+
+// storageVariablePathAccessor describes a single accessor within a storage variable path, either accessing a member
+// of a struct or an index of a mapping/array. Exactly one of the fields will be non-nil.
+type storageVariablePathAccessor struct {
+	// member describes the name of the struct member accessed.
+	member *string
+
+	// index describes the mapping key or array index accessed.
+	index *string
+}
+
+// parseStorageVariablePath splits a storage variable path into its root variable name and accessors.
+// Returns the root variable name and accessors, or an error if the path is malformed.
+func parseStorageVariablePath(path string) (string, []storageVariablePathAccessor, error) {
+	// Parse our root variable name.
+	path = strings.TrimSpace(path)
+	end := strings.IndexAny(path, ".[")
+	if end == -1 {
+		end = len(path)
+	}
+	name := path[:end]
+	if name == "" {
+		return "", nil, fmt.Errorf("storage path '%v' must begin with a variable name", path)
+	}
+
+	// Parse each accessor which follows it.
+	var accessors []storageVariablePathAccessor
+	for i := end; i < len(path); {
+		if path[i] == '.' {
+			// Member accesses continue until the next accessor.
+			end = strings.IndexAny(path[i+1:], ".[")
+			if end == -1 {
+				end = len(path)
+			} else {
+				end += i + 1
+			}
+			member := path[i+1 : end]
+			if member == "" {
+				return "", nil, fmt.Errorf("storage path '%v' contains an empty member access", path)
+			}
+			accessors = append(accessors, storageVariablePathAccessor{member: &member})
+			i = end
+		} else if path[i] == '[' {
+			// Index accesses continue until the closing bracket, which may be preceded by a quoted string key.
+			start := i + 1
+			end = start
+			if end < len(path) && path[end] == '"' {
+				end++
+				for end < len(path) && path[end] != '"' {
+					if path[end] == '\\' {
+						end++
+					}
+					end++
+				}
+				end++
+			}
+			closing := strings.IndexByte(path[min(end, len(path)):], ']')
+			if closing == -1 {
+				return "", nil, fmt.Errorf("storage path '%v' contains an unterminated index access", path)
+			}
+			end += closing
+			index := strings.TrimSpace(path[start:end])
+			if index == "" {
+				return "", nil, fmt.Errorf("storage path '%v' contains an empty index access", path)
+			}
+			accessors = append(accessors, storageVariablePathAccessor{index: &index})
+			i = end + 1
+		} else {
+			return "", nil, fmt.Errorf("storage path '%v' contains an unexpected character '%c'", path, path[i])
+		}
+	}
+	return name, accessors, nil
+}
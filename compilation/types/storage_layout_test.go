@@ -0,0 +1,188 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// testStorageLayout describes a storage layout, as output by the compiler, for the following contract:
+//
+//	contract TestContract {
+//	    struct Config { uint128 fee; uint128 cap; address admin; }
+//	    address owner;
+//	    bool flag;
+//	    int8 small;
+//	    mapping(address => uint256) balances;
+//	    uint256[] values;
+//	    uint64[] packed;
+//	    Config config;
+//	    mapping(string => uint256) names;
+//	}
+const testStorageLayout = `{
+	"storage": [
+		{"label": "owner", "offset": 0, "slot": "0", "type": "t_address"},
+		{"label": "flag", "offset": 20, "slot": "0", "type": "t_bool"},
+		{"label": "small", "offset": 21, "slot": "0", "type": "t_int8"},
+		{"label": "balances", "offset": 0, "slot": "1", "type": "t_mapping(t_address,t_uint256)"},
+		{"label": "values", "offset": 0, "slot": "2", "type": "t_array(t_uint256)dyn_storage"},
+		{"label": "packed", "offset": 0, "slot": "3", "type": "t_array(t_uint64)dyn_storage"},
+		{"label": "config", "offset": 0, "slot": "4", "type": "t_struct(Config)1_storage"},
+		{"label": "names", "offset": 0, "slot": "6", "type": "t_mapping(t_string_memory_ptr,t_uint256)"}
+	],
+	"types": {
+		"t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+		"t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+		"t_int8": {"encoding": "inplace", "label": "int8", "numberOfBytes": "1"},
+		"t_uint64": {"encoding": "inplace", "label": "uint64", "numberOfBytes": "8"},
+		"t_uint128": {"encoding": "inplace", "label": "uint128", "numberOfBytes": "16"},
+		"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+		"t_string_memory_ptr": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+		"t_mapping(t_address,t_uint256)": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+		"t_mapping(t_string_memory_ptr,t_uint256)": {"encoding": "mapping", "key": "t_string_memory_ptr", "label": "mapping(string => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+		"t_array(t_uint256)dyn_storage": {"base": "t_uint256", "encoding": "dynamic_array", "label": "uint256[]", "numberOfBytes": "32"},
+		"t_array(t_uint64)dyn_storage": {"base": "t_uint64", "encoding": "dynamic_array", "label": "uint64[]", "numberOfBytes": "32"},
+		"t_struct(Config)1_storage": {"encoding": "inplace", "label": "struct TestContract.Config", "numberOfBytes": "64", "members": [
+			{"label": "fee", "offset": 0, "slot": "0", "type": "t_uint128"},
+			{"label": "cap", "offset": 16, "slot": "0", "type": "t_uint128"},
+			{"label": "admin", "offset": 0, "slot": "1", "type": "t_address"}
+		]}
+	}
+}`
+
+// TestStorageLayoutResolvePath tests that paths to packed variables, struct members, mapping values and dynamic array
+// elements resolve to the slot, offset and size the compiler stores them at, and that invalid paths are rejected.
+func TestStorageLayoutResolvePath(t *testing.T) {
+	storageLayout, err := ParseStorageLayoutFromInterface(testStorageLayout)
+	assert.NoError(t, err)
+
+	// Compute the slots of our mapping values and dynamic array data.
+	slotWord := func(slot int64) []byte {
+		return common.BigToHash(big.NewInt(slot)).Bytes()
+	}
+	balanceSlot := crypto.Keccak256Hash(common.LeftPadBytes(common.HexToAddress("0x1234").Bytes(), 32), slotWord(1))
+	nameSlot := crypto.Keccak256Hash([]byte("alice"), slotWord(6))
+	valuesDataSlot := crypto.Keccak256Hash(slotWord(2)).Big()
+	packedDataSlot := crypto.Keccak256Hash(slotWord(3)).Big()
+
+	testCases := []struct {
+		path          string
+		slot          common.Hash
+		offset        int
+		numberOfBytes int
+		isValueType   bool
+		expectError   bool
+	}{
+		{path: "owner", slot: common.BigToHash(big.NewInt(0)), offset: 0, numberOfBytes: 20, isValueType: true},
+		{path: "flag", slot: common.BigToHash(big.NewInt(0)), offset: 20, numberOfBytes: 1, isValueType: true},
+		{path: "small", slot: common.BigToHash(big.NewInt(0)), offset: 21, numberOfBytes: 1, isValueType: true},
+		{path: "balances", slot: common.BigToHash(big.NewInt(1)), offset: 0, numberOfBytes: 32, isValueType: false},
+		{path: "balances[0x0000000000000000000000000000000000001234]", slot: balanceSlot, offset: 0, numberOfBytes: 32, isValueType: true},
+		{path: `names["alice"]`, slot: nameSlot, offset: 0, numberOfBytes: 32, isValueType: true},
+		{path: "names[alice]", slot: nameSlot, offset: 0, numberOfBytes: 32, isValueType: true},
+		{path: "values", slot: common.BigToHash(big.NewInt(2)), offset: 0, numberOfBytes: 32, isValueType: true},
+		{path: "values[0]", slot: common.BigToHash(valuesDataSlot), offset: 0, numberOfBytes: 32, isValueType: true},
+		{path: "values[2]", slot: common.BigToHash(new(big.Int).Add(valuesDataSlot, big.NewInt(2))), offset: 0, numberOfBytes: 32, isValueType: true},
+		{path: "packed[3]", slot: common.BigToHash(packedDataSlot), offset: 24, numberOfBytes: 8, isValueType: true},
+		{path: "packed[5]", slot: common.BigToHash(new(big.Int).Add(packedDataSlot, big.NewInt(1))), offset: 8, numberOfBytes: 8, isValueType: true},
+		{path: "config", slot: common.BigToHash(big.NewInt(4)), offset: 0, numberOfBytes: 32, isValueType: false},
+		{path: "config.fee", slot: common.BigToHash(big.NewInt(4)), offset: 0, numberOfBytes: 16, isValueType: true},
+		{path: "config.cap", slot: common.BigToHash(big.NewInt(4)), offset: 16, numberOfBytes: 16, isValueType: true},
+		{path: "config.admin", slot: common.BigToHash(big.NewInt(5)), offset: 0, numberOfBytes: 20, isValueType: true},
+		{path: "", expectError: true},
+		{path: "missing", expectError: true},
+		{path: "owner.member", expectError: true},
+		{path: "owner[0]", expectError: true},
+		{path: "config.missing", expectError: true},
+		{path: "config[0]", expectError: true},
+		{path: "balances[notanaddress]", expectError: true},
+		{path: "values[-1]", expectError: true},
+		{path: "values[1", expectError: true},
+		{path: "values[]", expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		location, err := storageLayout.ResolvePath(testCase.path)
+		if testCase.expectError {
+			assert.Error(t, err, "expected an error resolving path '%v'", testCase.path)
+			continue
+		}
+		if !assert.NoError(t, err, "unexpected error resolving path '%v'", testCase.path) {
+			continue
+		}
+		assert.EqualValues(t, testCase.slot, location.Slot, "unexpected slot for path '%v'", testCase.path)
+		assert.EqualValues(t, testCase.offset, location.Offset, "unexpected offset for path '%v'", testCase.path)
+		assert.EqualValues(t, testCase.numberOfBytes, location.NumberOfBytes, "unexpected size for path '%v'", testCase.path)
+		assert.EqualValues(t, testCase.isValueType, location.IsValueType(), "unexpected value type status for path '%v'", testCase.path)
+	}
+}
+
+// TestStorageLocationExtractInsertValue tests that values of variables packed into a single slot can be extracted and
+// inserted without affecting the other variables in the slot, and that signed integers are sign-extended.
+func TestStorageLocationExtractInsertValue(t *testing.T) {
+	storageLayout, err := ParseStorageLayoutFromInterface(testStorageLayout)
+	assert.NoError(t, err)
+
+	// Create a word packing owner (0x1234), flag (true) and small (-2) into slot 0.
+	packedWord := common.HexToHash("0x00000000000000000000fe010000000000000000000000000000000000001234")
+
+	testCases := []struct {
+		path         string
+		word         common.Hash
+		extracted    common.Hash
+		inserted     common.Hash
+		insertedWord common.Hash
+	}{
+		{
+			path:         "owner",
+			word:         packedWord,
+			extracted:    common.HexToHash("0x1234"),
+			inserted:     common.HexToHash("0xabcd"),
+			insertedWord: common.HexToHash("0x00000000000000000000fe01000000000000000000000000000000000000abcd"),
+		},
+		{
+			path:         "flag",
+			word:         packedWord,
+			extracted:    common.HexToHash("0x1"),
+			inserted:     common.HexToHash("0x0"),
+			insertedWord: common.HexToHash("0x00000000000000000000fe000000000000000000000000000000000000001234"),
+		},
+		{
+			path:         "small",
+			word:         packedWord,
+			extracted:    common.HexToHash("0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe"),
+			inserted:     common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff85"),
+			insertedWord: common.HexToHash("0x0000000000000000000085010000000000000000000000000000000000001234"),
+		},
+		{
+			path:         "config.cap",
+			word:         common.HexToHash("0x0000000000000000000000000000000500000000000000000000000000000007"),
+			extracted:    common.HexToHash("0x5"),
+			inserted:     common.HexToHash("0x9"),
+			insertedWord: common.HexToHash("0x0000000000000000000000000000000900000000000000000000000000000007"),
+		},
+		{
+			path:         "values[0]",
+			word:         common.HexToHash("0x1111"),
+			extracted:    common.HexToHash("0x1111"),
+			inserted:     common.HexToHash("0x2222"),
+			insertedWord: common.HexToHash("0x2222"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		location, err := storageLayout.ResolvePath(testCase.path)
+		if !assert.NoError(t, err, "unexpected error resolving path '%v'", testCase.path) {
+			continue
+		}
+
+		// Verify the extracted value, and that inserting a new value only modifies the bytes of this variable.
+		assert.EqualValues(t, testCase.extracted, location.ExtractValue(testCase.word), "unexpected value extracted for path '%v'", testCase.path)
+		insertedWord := location.InsertValue(testCase.word, testCase.inserted)
+		assert.EqualValues(t, testCase.insertedWord, insertedWord, "unexpected word after inserting for path '%v'", testCase.path)
+		assert.EqualValues(t, testCase.inserted, location.ExtractValue(insertedWord), "unexpected value extracted after inserting for path '%v'", testCase.path)
+	}
+}
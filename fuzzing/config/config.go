@@ -7,13 +7,20 @@ import (
 	"github.com/crytic/medusa/compilation/types"
 	"math/big"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/crytic/medusa/chain/config"
 	"github.com/crytic/medusa/compilation"
+	"github.com/crytic/medusa/compilation/abiutils"
+	"github.com/crytic/medusa/fuzzing/corpus"
+	"github.com/crytic/medusa/fuzzing/coverage"
 	"github.com/crytic/medusa/logging"
 	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/rs/zerolog"
+	"golang.org/x/exp/slices"
 )
 
 // The following directives will be picked up by the `go generate` command to generate JSON marshaling code from
@@ -55,6 +62,11 @@ type FuzzingConfig struct {
 	// ShrinkLimit describes a threshold for the iterations (call sequence tests) which shrinking should perform.
 	ShrinkLimit uint64 `json:"shrinkLimit"`
 
+	// Seed describes the seed used to derive every random provider used by the fuzzer. Campaigns with the same seed,
+	// configuration, and corpus generate the same call sequences when run with a single worker. A zero value indicates
+	// a seed should be chosen randomly.
+	Seed int64 `json:"seed"`
+
 	// CallSequenceLength describes the maximum length a transaction sequence can be generated as.
 	CallSequenceLength int `json:"callSequenceLength"`
 
@@ -65,6 +77,24 @@ type FuzzingConfig struct {
 	// CoverageEnabled describes whether to use coverage-guided fuzzing
 	CoverageEnabled bool `json:"coverageEnabled"`
 
+	// CoverageMode describes the units of execution tracked as coverage: "pc" (program counters), or "edge" (program
+	// counters, as well as transitions between jump destinations bucketed by hit count).
+	CoverageMode string `json:"coverageMode"`
+
+	// PowerSchedule describes the strategy used to weight corpus call sequences when choosing one to mutate: "none",
+	// "rare", or "fast".
+	PowerSchedule string `json:"powerSchedule"`
+
+	// StorageWriteCoverage describes how values written to storage are grouped when treating storage writes as
+	// coverage: "none" (disabled), "zero", "magnitude", or "exact". A call writing a value within a previously unseen
+	// group to a storage slot achieves new coverage.
+	StorageWriteCoverage string `json:"storageWriteCoverage"`
+
+	// ComparisonFeedbackEnabled describes whether the operands of comparisons and the inputs of hashes observed during
+	// execution should be added to the value set, and whether calls whose comparisons were close to being equal
+	// should be prioritized for mutation.
+	ComparisonFeedbackEnabled bool `json:"comparisonFeedbackEnabled"`
+
 	// CoverageFormats indicate which reports to generate: "lcov" and "html" are supported.
 	CoverageFormats []string `json:"coverageFormats"`
 
@@ -141,6 +171,11 @@ type TestingConfig struct {
 	// even if this option is not enabled.
 	TraceAll bool `json:"traceAll"`
 
+	// ReproducibilityChecks describes how many times a shrunken call sequence which failed a test should be replayed on
+	// a fresh clone of the base chain to verify the failure reproduces. Failures which do not reproduce are reported as
+	// flaky rather than failed. A value of 0 disables the check.
+	ReproducibilityChecks int `json:"reproducibilityChecks"`
+
 	// AssertionTesting describes the configuration used for assertion testing.
 	AssertionTesting AssertionTestingConfig `json:"assertionTesting"`
 
@@ -150,6 +185,21 @@ type TestingConfig struct {
 	// OptimizationTesting describes the configuration used for optimization testing.
 	OptimizationTesting OptimizationTestingConfig `json:"optimizationTesting"`
 
+	// FuzzTesting describes the configuration used for stateless fuzz testing.
+	FuzzTesting FuzzTestingConfig `json:"fuzzTesting"`
+
+	// DifferentialTesting describes the configuration used for differential testing.
+	DifferentialTesting DifferentialTestingConfig `json:"differentialTesting"`
+
+	// DrainTesting describes the configuration used for asset drain testing.
+	DrainTesting DrainTestingConfig `json:"drainTesting"`
+
+	// DetectorTesting describes the configuration used for trace-based vulnerability detectors.
+	DetectorTesting DetectorTestingConfig `json:"detectorTesting"`
+
+	// GasTesting describes the configuration used for gas exhaustion testing.
+	GasTesting GasTestingConfig `json:"gasTesting"`
+
 	// TargetFunctionSignatures is a list function signatures call the fuzzer should exclusively target by omitting calls to other signatures.
 	// The signatures should specify the contract name and signature in the ABI format like `Contract.func(uint256,bytes32)`.
 	TargetFunctionSignatures []string `json:"targetFunctionSignatures"`
@@ -174,21 +224,103 @@ func (testCfg *TestingConfig) Validate() error {
 		}
 	}
 
+	// Verify the number of reproducibility checks is valid.
+	if testCfg.ReproducibilityChecks < 0 {
+		return errors.New("project configuration must specify a non-negative number of reproducibility checks")
+	}
+
+	// Verify the property check frequency is valid.
+	if testCfg.PropertyTesting.Enabled && testCfg.PropertyTesting.CheckFrequency < 0 {
+		return errors.New("project configuration must specify a non-negative property check frequency")
+	}
+
 	if testCfg.OptimizationTesting.Enabled {
 		// Test prefixes must be supplied if optimization testing is enabled.
-		if len(testCfg.OptimizationTesting.TestPrefixes) == 0 {
+		if len(testCfg.OptimizationTesting.TestPrefixes) == 0 && len(testCfg.OptimizationTesting.MinimizeTestPrefixes) == 0 {
 			return errors.New("project configuration must specify test name prefixes if optimization testing is enabled")
 		}
+
+		// Verify that maximization and minimization prefixes are unique.
+		for _, prefix := range testCfg.OptimizationTesting.MinimizeTestPrefixes {
+			if slices.Contains(testCfg.OptimizationTesting.TestPrefixes, prefix) {
+				return errors.New("project configuration must specify unique test name prefixes for maximizing and minimizing optimization tests")
+			}
+		}
+	}
+
+	// Verify the event signatures which signal assertion failures.
+	for _, eventSignature := range testCfg.AssertionTesting.FailOnEvents {
+		if !abiutils.IsValidSignature(eventSignature) {
+			return fmt.Errorf("project configuration specifies an invalid event signature to fail on: '%s'", eventSignature)
+		}
+	}
+
+	// Verify the custom error signatures which signal assertion failures.
+	for _, errorSignature := range testCfg.AssertionTesting.FailOnCustomErrors {
+		if !abiutils.IsValidSignature(errorSignature) {
+			return fmt.Errorf("project configuration specifies an invalid custom error signature to fail on: '%s'", errorSignature)
+		}
+	}
+
+	// Verify the differential testing pairs.
+	if testCfg.DifferentialTesting.Enabled {
+		if len(testCfg.DifferentialTesting.Pairs) == 0 {
+			return errors.New("project configuration must specify contract or method pairs if differential testing is enabled")
+		}
+		for _, pair := range testCfg.DifferentialTesting.Pairs {
+			referenceContract, referenceMethod := pair.ReferenceContractAndMethod()
+			candidateContract, candidateMethod := pair.CandidateContractAndMethod()
+			if referenceContract == "" || candidateContract == "" {
+				return errors.New("project configuration must specify both a reference and candidate for each differential testing pair")
+			}
+			if (referenceMethod == "") != (candidateMethod == "") {
+				return fmt.Errorf("project configuration must specify either two contracts or two methods for differential testing pair '%s' and '%s'", pair.Reference, pair.Candidate)
+			}
+			if _, err := pair.StorageSlotKeys(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Verify the drain testing profit threshold.
+	if testCfg.DrainTesting.Enabled && (testCfg.DrainTesting.ProfitThreshold == nil || testCfg.DrainTesting.ProfitThreshold.Sign() < 0) {
+		return errors.New("project configuration must specify a non-negative profit threshold if drain testing is enabled")
+	}
+
+	// Verify the gas testing threshold can be parsed.
+	if testCfg.GasTesting.Enabled {
+		if _, err := testCfg.GasTesting.GasThresholdValue(0); err != nil {
+			return err
+		}
+	}
+
+	if testCfg.FuzzTesting.Enabled {
+		// Test prefixes must be supplied if fuzz testing is enabled.
+		if len(testCfg.FuzzTesting.TestPrefixes) == 0 {
+			return errors.New("project configuration must specify test name prefixes if fuzz testing is enabled")
+		}
 	}
 
 	// Validate that prefixes do not overlap
 	for _, prefix := range testCfg.PropertyTesting.TestPrefixes {
-		for _, prefix2 := range testCfg.OptimizationTesting.TestPrefixes {
+		for _, prefix2 := range testCfg.OptimizationTesting.AllTestPrefixes() {
 			if prefix == prefix2 {
 				return errors.New("project configuration must specify unique test name prefixes for property and optimization testing")
 			}
 		}
 	}
+	for _, prefix := range testCfg.PropertyTesting.RevertTestPrefixes {
+		if slices.Contains(testCfg.PropertyTesting.TestPrefixes, prefix) || slices.Contains(testCfg.OptimizationTesting.AllTestPrefixes(), prefix) {
+			return errors.New("project configuration must specify unique test name prefixes for revert property testing")
+		}
+	}
+	if testCfg.FuzzTesting.Enabled {
+		for _, prefix := range testCfg.FuzzTesting.TestPrefixes {
+			if slices.Contains(testCfg.PropertyTesting.TestPrefixes, prefix) || slices.Contains(testCfg.PropertyTesting.RevertTestPrefixes, prefix) || slices.Contains(testCfg.OptimizationTesting.AllTestPrefixes(), prefix) {
+				return errors.New("project configuration must specify unique test name prefixes for fuzz testing")
+			}
+		}
+	}
 
 	return nil
 }
@@ -203,6 +335,14 @@ type AssertionTestingConfig struct {
 
 	// PanicCodeConfig describes the various panic codes that can be enabled and be treated as a "failing case"
 	PanicCodeConfig PanicCodeConfig `json:"panicCodeConfig"`
+
+	// FailOnEvents describes a list of event signatures (e.g. "AssertionFailed(string)") which, if emitted during a
+	// call, should be treated as a failing case for the method called.
+	FailOnEvents []string `json:"failOnEvents"`
+
+	// FailOnCustomErrors describes a list of custom error signatures (e.g. "InvariantViolated(uint256)") which, if a
+	// call reverts with them, should be treated as a failing case for the method called.
+	FailOnCustomErrors []string `json:"failOnCustomErrors"`
 }
 
 // PanicCodeConfig describes the various panic codes that can be enabled and be treated as a failing assertion test
@@ -245,6 +385,19 @@ type PropertyTestingConfig struct {
 
 	// TestPrefixes dictates what method name prefixes will determine if a contract method is a property test.
 	TestPrefixes []string `json:"testPrefixes"`
+
+	// RevertTestPrefixes dictates what method name prefixes will determine if a contract method is a property test
+	// which is expected to revert. Such a property test only passes if calling it reverts.
+	RevertTestPrefixes []string `json:"revertTestPrefixes"`
+
+	// ErrorOnRevert describes whether a property test which reverts (and is not expected to) should be reported with
+	// an errored status, rather than as a failed test.
+	ErrorOnRevert bool `json:"errorOnRevert"`
+
+	// CheckFrequency describes how often property tests are checked while executing a call sequence. A value of 1
+	// checks them after every call, a value of N checks them after every N calls (and at the end of the sequence), and
+	// a value of 0 only checks them at the end of the sequence.
+	CheckFrequency int `json:"checkFrequency"`
 }
 
 // OptimizationTestingConfig describes the configuration options used for optimization testing
@@ -252,10 +405,172 @@ type OptimizationTestingConfig struct {
 	// Enabled describes whether testing is enabled.
 	Enabled bool `json:"enabled"`
 
-	// TestPrefixes dictates what method name prefixes will determine if a contract method is an optimization test.
+	// TestPrefixes dictates what method name prefixes will determine if a contract method is an optimization test
+	// whose return values should be maximized.
+	TestPrefixes []string `json:"testPrefixes"`
+
+	// MinimizeTestPrefixes dictates what method name prefixes will determine if a contract method is an optimization
+	// test whose return values should be minimized.
+	MinimizeTestPrefixes []string `json:"minimizeTestPrefixes"`
+}
+
+// AllTestPrefixes obtains the method name prefixes which determine if a contract method is an optimization test,
+// whether it is maximized or minimized.
+func (c *OptimizationTestingConfig) AllTestPrefixes() []string {
+	return append(slices.Clone(c.TestPrefixes), c.MinimizeTestPrefixes...)
+}
+
+// FuzzTestingConfig describes the configuration options used for stateless fuzz testing
+type FuzzTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// TestPrefixes dictates what method name prefixes will determine if a contract method is a stateless fuzz test.
+	// Fuzz tests take input arguments and pass unless they revert. They are called in isolation from the post-setup
+	// chain state and are never part of a stateful call sequence.
 	TestPrefixes []string `json:"testPrefixes"`
 }
 
+// DifferentialTestingConfig describes the configuration options used for differential testing
+type DifferentialTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// Pairs describes the pairs of contracts or methods whose behavior should be compared.
+	Pairs []DifferentialTestPairConfig `json:"pairs"`
+}
+
+// IsCandidateContract indicates whether differential testing is enabled and the contract with the provided name is the
+// candidate of any pair. Candidates are not called directly by the fuzzer, as calls are only mirrored onto them.
+func (c *DifferentialTestingConfig) IsCandidateContract(contractName string) bool {
+	if !c.Enabled {
+		return false
+	}
+	for _, pair := range c.Pairs {
+		if candidateContractName, _ := pair.CandidateContractAndMethod(); candidateContractName == contractName {
+			return true
+		}
+	}
+	return false
+}
+
+// DifferentialTestPairConfig describes a pair of contracts or methods whose behavior should be identical. Every call
+// made to the reference is also executed against the candidate, from identical state, and the results are compared.
+type DifferentialTestPairConfig struct {
+	// Reference describes the reference contract name (e.g. "MathLib"), or a method on it in the form
+	// "Contract.method(types)" (e.g. "MathLib.mulDiv(uint256,uint256,uint256)").
+	Reference string `json:"reference"`
+
+	// Candidate describes the candidate contract name, or a method on it, in the same form as Reference. If the
+	// Reference is a contract, calls are mapped to the candidate method with the same signature.
+	Candidate string `json:"candidate"`
+
+	// StorageSlots describes a list of hex-encoded storage slots whose values in the reference and candidate contracts
+	// should be identical after each compared call.
+	StorageSlots []string `json:"storageSlots"`
+}
+
+// ReferenceContractAndMethod splits the Reference into a contract name and method signature. The method signature is
+// empty if the Reference refers to an entire contract.
+func (c *DifferentialTestPairConfig) ReferenceContractAndMethod() (string, string) {
+	return splitContractAndMethod(c.Reference)
+}
+
+// CandidateContractAndMethod splits the Candidate into a contract name and method signature. The method signature is
+// empty if the Candidate refers to an entire contract.
+func (c *DifferentialTestPairConfig) CandidateContractAndMethod() (string, string) {
+	return splitContractAndMethod(c.Candidate)
+}
+
+// StorageSlotKeys parses the StorageSlots into storage keys.
+// Returns the storage keys, or an error if a storage slot could not be parsed.
+func (c *DifferentialTestPairConfig) StorageSlotKeys() ([]common.Hash, error) {
+	keys := make([]common.Hash, 0, len(c.StorageSlots))
+	for _, slot := range c.StorageSlots {
+		slotValue, success := new(big.Int).SetString(strings.TrimPrefix(slot, "0x"), 16)
+		if !strings.HasPrefix(slot, "0x") || !success || slotValue.Sign() < 0 || slotValue.BitLen() > 256 {
+			return nil, fmt.Errorf("invalid storage slot '%s' for differential testing pair '%s' and '%s'", slot, c.Reference, c.Candidate)
+		}
+		keys = append(keys, common.BigToHash(slotValue))
+	}
+	return keys, nil
+}
+
+// splitContractAndMethod splits a string in the form "Contract" or "Contract.method(types)" into its contract name
+// and method signature. The method signature is empty if none was provided.
+func splitContractAndMethod(value string) (string, string) {
+	if contractName, methodSignature, found := strings.Cut(value, "."); found && strings.Contains(methodSignature, "(") {
+		return contractName, methodSignature
+	}
+	return value, ""
+}
+
+// DrainTestingConfig describes the configuration options used for asset drain testing, which checks that no fuzzer
+// sender can gain more assets than they started with.
+type DrainTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// ProfitThreshold describes the amount of an asset (in wei for ETH, or in the smallest token unit for ERC20
+	// tokens) a sender may gain over the course of a call sequence before the test fails.
+	ProfitThreshold *big.Int `json:"profitThreshold"`
+
+	// TokenContracts describes the names of ERC20 token contracts whose balances should be tracked for each sender,
+	// in addition to their ETH balance.
+	TokenContracts []string `json:"tokenContracts"`
+}
+
+// DetectorTestingConfig describes the configuration options used for trace-based vulnerability detectors, which
+// inspect the execution trace of every call the fuzzer makes for vulnerable patterns.
+type DetectorTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// DetectReentrancy describes whether state writes made after an external call into a sender-controlled address
+	// should be reported.
+	DetectReentrancy bool `json:"detectReentrancy"`
+
+	// DetectArbitraryCall describes whether a call or delegatecall to an address taken from calldata should be
+	// reported.
+	DetectArbitraryCall bool `json:"detectArbitraryCall"`
+
+	// DetectSelfDestruct describes whether a reachable SELFDESTRUCT operation should be reported.
+	DetectSelfDestruct bool `json:"detectSelfDestruct"`
+}
+
+// GasTestingConfig describes the configuration options used for gas exhaustion testing, which tracks the maximum gas
+// used by calls to each state-changing method.
+type GasTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// GasThreshold describes the amount of gas a call to a state-changing method may use before the test for that
+	// method fails. It is either an absolute amount of gas (e.g. "1000000"), or a percentage of the block gas limit
+	// (e.g. "5%").
+	GasThreshold string `json:"gasThreshold"`
+}
+
+// GasThresholdValue obtains the amount of gas described by the GasThreshold, resolving any percentage against the
+// provided block gas limit.
+// Returns the amount of gas, or an error if the GasThreshold could not be parsed.
+func (c *GasTestingConfig) GasThresholdValue(blockGasLimit uint64) (uint64, error) {
+	// If this is a percentage, resolve it against the block gas limit.
+	if percentageString, isPercentage := strings.CutSuffix(c.GasThreshold, "%"); isPercentage {
+		percentage, err := strconv.ParseFloat(percentageString, 64)
+		if err != nil || percentage <= 0 || percentage > 100 {
+			return 0, fmt.Errorf("invalid gas threshold percentage '%s', expected a value greater than 0%% and at most 100%%", c.GasThreshold)
+		}
+		return uint64(float64(blockGasLimit) * percentage / 100), nil
+	}
+
+	// Otherwise, parse it as an absolute amount of gas.
+	gasThreshold, err := strconv.ParseUint(c.GasThreshold, 10, 64)
+	if err != nil || gasThreshold == 0 {
+		return 0, fmt.Errorf("invalid gas threshold '%s', expected a positive amount of gas or a percentage of the block gas limit", c.GasThreshold)
+	}
+	return gasThreshold, nil
+}
+
 // LoggingConfig describes the configuration options for logging to console and file
 type LoggingConfig struct {
 	// Level describes whether logs of certain severity levels (eg info, warning, etc.) will be emitted or discarded.
@@ -361,6 +676,11 @@ func (p *ProjectConfig) Validate() error {
 		return errors.New("project configuration must specify a positive number for the timeout")
 	}
 
+	// Verify the ffi timeout
+	if p.Fuzzing.TestChainConfig.CheatCodeConfig.FFITimeout < 0 {
+		return errors.New("project configuration must specify a non-negative number for the ffi timeout")
+	}
+
 	// Verify gas limits are appropriate
 	if p.Fuzzing.BlockGasLimit < p.Fuzzing.TransactionGasLimit {
 		return errors.New("project configuration must specify a block gas limit which is not less than the transaction gas limit")
@@ -399,6 +719,21 @@ func (p *ProjectConfig) Validate() error {
 		}
 	}
 
+	// The power schedule must be a supported one
+	if !corpus.PowerSchedule(p.Fuzzing.PowerSchedule).IsValid() {
+		return fmt.Errorf("project configuration must specify a valid power schedule (none, rare, fast): %s", p.Fuzzing.PowerSchedule)
+	}
+
+	// The coverage mode must be a supported one
+	if !coverage.CoverageMode(p.Fuzzing.CoverageMode).IsValid() {
+		return fmt.Errorf("project configuration must specify a valid coverage mode (pc, edge): %s", p.Fuzzing.CoverageMode)
+	}
+
+	// The storage write coverage bucketing must be a supported one
+	if !coverage.StorageWriteBucketing(p.Fuzzing.StorageWriteCoverage).IsValid() {
+		return fmt.Errorf("project configuration must specify a valid storage write coverage bucketing (none, zero, magnitude, exact): %s", p.Fuzzing.StorageWriteCoverage)
+	}
+
 	// The coverage report format must be either "lcov" or "html"
 	if p.Fuzzing.CoverageFormats != nil {
 		for _, report := range p.Fuzzing.CoverageFormats {
@@ -41,19 +41,23 @@ func GetDefaultProjectConfig(platform string) (*ProjectConfig, error) {
 	// Create a project configuration
 	projectConfig := &ProjectConfig{
 		Fuzzing: FuzzingConfig{
-			Workers:                 10,
-			WorkerResetLimit:        50,
-			Timeout:                 0,
-			TestLimit:               0,
-			ShrinkLimit:             5_000,
-			CallSequenceLength:      100,
-			TargetContracts:         []string{},
-			TargetContractsBalances: []*big.Int{},
-			PredeployedContracts:    map[string]string{},
-			ConstructorArgs:         map[string]map[string]any{},
-			CorpusDirectory:         "",
-			CoverageEnabled:         true,
-			CoverageFormats:         []string{"html", "lcov"},
+			Workers:                   10,
+			WorkerResetLimit:          50,
+			Timeout:                   0,
+			TestLimit:                 0,
+			ShrinkLimit:               5_000,
+			CallSequenceLength:        100,
+			TargetContracts:           []string{},
+			TargetContractsBalances:   []*big.Int{},
+			PredeployedContracts:      map[string]string{},
+			ConstructorArgs:           map[string]map[string]any{},
+			CorpusDirectory:           "",
+			CoverageEnabled:           true,
+			CoverageMode:              "pc",
+			PowerSchedule:             "none",
+			StorageWriteCoverage:      "none",
+			ComparisonFeedbackEnabled: false,
+			CoverageFormats:           []string{"html", "lcov"},
 			SenderAddresses: []string{
 				"0x10000",
 				"0x20000",
@@ -70,6 +74,7 @@ func GetDefaultProjectConfig(platform string) (*ProjectConfig, error) {
 				StopOnNoTests:                true,
 				TestAllContracts:             false,
 				TraceAll:                     false,
+				ReproducibilityChecks:        1,
 				TargetFunctionSignatures:     []string{},
 				ExcludeFunctionSignatures:    []string{},
 				AssertionTesting: AssertionTestingConfig{
@@ -78,18 +83,51 @@ func GetDefaultProjectConfig(platform string) (*ProjectConfig, error) {
 					PanicCodeConfig: PanicCodeConfig{
 						FailOnAssertion: true,
 					},
+					FailOnEvents:       []string{},
+					FailOnCustomErrors: []string{},
 				},
 				PropertyTesting: PropertyTestingConfig{
 					Enabled: true,
 					TestPrefixes: []string{
 						"property_",
 					},
+					RevertTestPrefixes: []string{},
+					ErrorOnRevert:      false,
+					CheckFrequency:     1,
 				},
 				OptimizationTesting: OptimizationTestingConfig{
 					Enabled: true,
 					TestPrefixes: []string{
 						"optimize_",
 					},
+					MinimizeTestPrefixes: []string{
+						"minimize_",
+					},
+				},
+				FuzzTesting: FuzzTestingConfig{
+					Enabled: false,
+					TestPrefixes: []string{
+						"testFuzz_",
+					},
+				},
+				DifferentialTesting: DifferentialTestingConfig{
+					Enabled: false,
+					Pairs:   []DifferentialTestPairConfig{},
+				},
+				DrainTesting: DrainTestingConfig{
+					Enabled:         false,
+					ProfitThreshold: big.NewInt(0),
+					TokenContracts:  []string{},
+				},
+				DetectorTesting: DetectorTestingConfig{
+					Enabled:             false,
+					DetectReentrancy:    true,
+					DetectArbitraryCall: true,
+					DetectSelfDestruct:  true,
+				},
+				GasTesting: GasTestingConfig{
+					Enabled:      false,
+					GasThreshold: "5%",
 				},
 			},
 			TestChainConfig: *chainConfig,
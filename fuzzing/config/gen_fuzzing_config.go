@@ -15,27 +15,32 @@ var _ = (*fuzzingConfigMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (f FuzzingConfig) MarshalJSON() ([]byte, error) {
 	type FuzzingConfig struct {
-		Workers                 int                       `json:"workers"`
-		WorkerResetLimit        int                       `json:"workerResetLimit"`
-		Timeout                 int                       `json:"timeout"`
-		TestLimit               uint64                    `json:"testLimit"`
-		ShrinkLimit             uint64                    `json:"shrinkLimit"`
-		CallSequenceLength      int                       `json:"callSequenceLength"`
-		CorpusDirectory         string                    `json:"corpusDirectory"`
-		CoverageEnabled         bool                      `json:"coverageEnabled"`
-		CoverageFormats         []string                  `json:"coverageFormats"`
-		TargetContracts         []string                  `json:"targetContracts"`
-		PredeployedContracts    map[string]string         `json:"predeployedContracts"`
-		TargetContractsBalances []*hexutil.Big            `json:"targetContractsBalances"`
-		ConstructorArgs         map[string]map[string]any `json:"constructorArgs"`
-		DeployerAddress         string                    `json:"deployerAddress"`
-		SenderAddresses         []string                  `json:"senderAddresses"`
-		MaxBlockNumberDelay     uint64                    `json:"blockNumberDelayMax"`
-		MaxBlockTimestampDelay  uint64                    `json:"blockTimestampDelayMax"`
-		BlockGasLimit           uint64                    `json:"blockGasLimit"`
-		TransactionGasLimit     uint64                    `json:"transactionGasLimit"`
-		Testing                 TestingConfig             `json:"testing"`
-		TestChainConfig         config.TestChainConfig    `json:"chainConfig"`
+		Workers                   int                       `json:"workers"`
+		WorkerResetLimit          int                       `json:"workerResetLimit"`
+		Timeout                   int                       `json:"timeout"`
+		TestLimit                 uint64                    `json:"testLimit"`
+		ShrinkLimit               uint64                    `json:"shrinkLimit"`
+		Seed                      int64                     `json:"seed"`
+		CallSequenceLength        int                       `json:"callSequenceLength"`
+		CorpusDirectory           string                    `json:"corpusDirectory"`
+		CoverageEnabled           bool                      `json:"coverageEnabled"`
+		CoverageMode              string                    `json:"coverageMode"`
+		PowerSchedule             string                    `json:"powerSchedule"`
+		StorageWriteCoverage      string                    `json:"storageWriteCoverage"`
+		ComparisonFeedbackEnabled bool                      `json:"comparisonFeedbackEnabled"`
+		CoverageFormats           []string                  `json:"coverageFormats"`
+		TargetContracts           []string                  `json:"targetContracts"`
+		PredeployedContracts      map[string]string         `json:"predeployedContracts"`
+		TargetContractsBalances   []*hexutil.Big            `json:"targetContractsBalances"`
+		ConstructorArgs           map[string]map[string]any `json:"constructorArgs"`
+		DeployerAddress           string                    `json:"deployerAddress"`
+		SenderAddresses           []string                  `json:"senderAddresses"`
+		MaxBlockNumberDelay       uint64                    `json:"blockNumberDelayMax"`
+		MaxBlockTimestampDelay    uint64                    `json:"blockTimestampDelayMax"`
+		BlockGasLimit             uint64                    `json:"blockGasLimit"`
+		TransactionGasLimit       uint64                    `json:"transactionGasLimit"`
+		Testing                   TestingConfig             `json:"testing"`
+		TestChainConfig           config.TestChainConfig    `json:"chainConfig"`
 	}
 	var enc FuzzingConfig
 	enc.Workers = f.Workers
@@ -43,9 +48,14 @@ func (f FuzzingConfig) MarshalJSON() ([]byte, error) {
 	enc.Timeout = f.Timeout
 	enc.TestLimit = f.TestLimit
 	enc.ShrinkLimit = f.ShrinkLimit
+	enc.Seed = f.Seed
 	enc.CallSequenceLength = f.CallSequenceLength
 	enc.CorpusDirectory = f.CorpusDirectory
 	enc.CoverageEnabled = f.CoverageEnabled
+	enc.CoverageMode = f.CoverageMode
+	enc.PowerSchedule = f.PowerSchedule
+	enc.StorageWriteCoverage = f.StorageWriteCoverage
+	enc.ComparisonFeedbackEnabled = f.ComparisonFeedbackEnabled
 	enc.CoverageFormats = f.CoverageFormats
 	enc.TargetContracts = f.TargetContracts
 	enc.PredeployedContracts = f.PredeployedContracts
@@ -70,27 +80,32 @@ func (f FuzzingConfig) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshals from JSON.
 func (f *FuzzingConfig) UnmarshalJSON(input []byte) error {
 	type FuzzingConfig struct {
-		Workers                 *int                      `json:"workers"`
-		WorkerResetLimit        *int                      `json:"workerResetLimit"`
-		Timeout                 *int                      `json:"timeout"`
-		TestLimit               *uint64                   `json:"testLimit"`
-		ShrinkLimit             *uint64                   `json:"shrinkLimit"`
-		CallSequenceLength      *int                      `json:"callSequenceLength"`
-		CorpusDirectory         *string                   `json:"corpusDirectory"`
-		CoverageEnabled         *bool                     `json:"coverageEnabled"`
-		CoverageFormats         []string                  `json:"coverageFormats"`
-		TargetContracts         []string                  `json:"targetContracts"`
-		PredeployedContracts    map[string]string         `json:"predeployedContracts"`
-		TargetContractsBalances []*hexutil.Big            `json:"targetContractsBalances"`
-		ConstructorArgs         map[string]map[string]any `json:"constructorArgs"`
-		DeployerAddress         *string                   `json:"deployerAddress"`
-		SenderAddresses         []string                  `json:"senderAddresses"`
-		MaxBlockNumberDelay     *uint64                   `json:"blockNumberDelayMax"`
-		MaxBlockTimestampDelay  *uint64                   `json:"blockTimestampDelayMax"`
-		BlockGasLimit           *uint64                   `json:"blockGasLimit"`
-		TransactionGasLimit     *uint64                   `json:"transactionGasLimit"`
-		Testing                 *TestingConfig            `json:"testing"`
-		TestChainConfig         *config.TestChainConfig   `json:"chainConfig"`
+		Workers                   *int                      `json:"workers"`
+		WorkerResetLimit          *int                      `json:"workerResetLimit"`
+		Timeout                   *int                      `json:"timeout"`
+		TestLimit                 *uint64                   `json:"testLimit"`
+		ShrinkLimit               *uint64                   `json:"shrinkLimit"`
+		Seed                      *int64                    `json:"seed"`
+		CallSequenceLength        *int                      `json:"callSequenceLength"`
+		CorpusDirectory           *string                   `json:"corpusDirectory"`
+		CoverageEnabled           *bool                     `json:"coverageEnabled"`
+		CoverageMode              *string                   `json:"coverageMode"`
+		PowerSchedule             *string                   `json:"powerSchedule"`
+		StorageWriteCoverage      *string                   `json:"storageWriteCoverage"`
+		ComparisonFeedbackEnabled *bool                     `json:"comparisonFeedbackEnabled"`
+		CoverageFormats           []string                  `json:"coverageFormats"`
+		TargetContracts           []string                  `json:"targetContracts"`
+		PredeployedContracts      map[string]string         `json:"predeployedContracts"`
+		TargetContractsBalances   []*hexutil.Big            `json:"targetContractsBalances"`
+		ConstructorArgs           map[string]map[string]any `json:"constructorArgs"`
+		DeployerAddress           *string                   `json:"deployerAddress"`
+		SenderAddresses           []string                  `json:"senderAddresses"`
+		MaxBlockNumberDelay       *uint64                   `json:"blockNumberDelayMax"`
+		MaxBlockTimestampDelay    *uint64                   `json:"blockTimestampDelayMax"`
+		BlockGasLimit             *uint64                   `json:"blockGasLimit"`
+		TransactionGasLimit       *uint64                   `json:"transactionGasLimit"`
+		Testing                   *TestingConfig            `json:"testing"`
+		TestChainConfig           *config.TestChainConfig   `json:"chainConfig"`
 	}
 	var dec FuzzingConfig
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -111,6 +126,9 @@ func (f *FuzzingConfig) UnmarshalJSON(input []byte) error {
 	if dec.ShrinkLimit != nil {
 		f.ShrinkLimit = *dec.ShrinkLimit
 	}
+	if dec.Seed != nil {
+		f.Seed = *dec.Seed
+	}
 	if dec.CallSequenceLength != nil {
 		f.CallSequenceLength = *dec.CallSequenceLength
 	}
@@ -120,6 +138,18 @@ func (f *FuzzingConfig) UnmarshalJSON(input []byte) error {
 	if dec.CoverageEnabled != nil {
 		f.CoverageEnabled = *dec.CoverageEnabled
 	}
+	if dec.CoverageMode != nil {
+		f.CoverageMode = *dec.CoverageMode
+	}
+	if dec.PowerSchedule != nil {
+		f.PowerSchedule = *dec.PowerSchedule
+	}
+	if dec.StorageWriteCoverage != nil {
+		f.StorageWriteCoverage = *dec.StorageWriteCoverage
+	}
+	if dec.ComparisonFeedbackEnabled != nil {
+		f.ComparisonFeedbackEnabled = *dec.ComparisonFeedbackEnabled
+	}
 	if dec.CoverageFormats != nil {
 		f.CoverageFormats = dec.CoverageFormats
 	}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/crytic/medusa/compilation/types"
+	fuzzingutils "github.com/crytic/medusa/fuzzing/utils"
+	"github.com/crytic/medusa/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 )
 
@@ -46,10 +48,22 @@ type Contract struct {
 	// OptimizationTestMethods are the methods that are optimization tests.
 	OptimizationTestMethods []abi.Method
 
+	// FuzzTestMethods are the methods that are stateless fuzz tests. These are never called as part of a stateful
+	// call sequence.
+	FuzzTestMethods []abi.Method
+
 	// AssertionTestMethods are ALL other methods that are not property or optimization tests by default.
 	// If configured, the methods will be targeted or excluded based on the targetFunctionSignatures
 	// and excludedFunctionSignatures, respectively.
 	AssertionTestMethods []abi.Method
+
+	// preconditionMethods describes the precondition methods which must return true for a handler method to be
+	// called, keyed by the name of the handler method.
+	preconditionMethods map[string]abi.Method
+
+	// annotations describes the per-method test configuration provided through NatSpec custom tags, keyed by method
+	// signature.
+	annotations map[string]*MethodAnnotations
 }
 
 // NewContract returns a new Contract instance with the provided information.
@@ -88,6 +102,62 @@ func (c *Contract) WithExcludedAssertionMethods(excludedMethods []string) *Contr
 	return c
 }
 
+// WithPreconditionMethods pairs handler methods with the precondition methods named after them, and removes the
+// precondition methods from the assertion test methods, so they are never called as part of a call sequence.
+func (c *Contract) WithPreconditionMethods() *Contract {
+	c.preconditionMethods = make(map[string]abi.Method)
+	preconditionSigs := make(map[string]bool)
+	for _, method := range c.compiledContract.Abi.Methods {
+		handlerName, ok := fuzzingutils.IsPreconditionMethod(method)
+		if !ok {
+			continue
+		}
+
+		// Only pair preconditions with handler methods which exist. Overloaded handlers share a precondition.
+		for _, handler := range c.compiledContract.Abi.Methods {
+			if handler.RawName == handlerName {
+				c.preconditionMethods[handlerName] = method
+				preconditionSigs[method.Sig] = true
+				break
+			}
+		}
+	}
+	c.AssertionTestMethods = utils.SliceWhere(c.AssertionTestMethods, func(method abi.Method) bool {
+		return !preconditionSigs[method.Sig]
+	})
+	return c
+}
+
+// PreconditionMethod returns the precondition method which must return true for the provided handler method to be
+// called, or nil if it has none.
+func (c *Contract) PreconditionMethod(method *abi.Method) *abi.Method {
+	if preconditionMethod, ok := c.preconditionMethods[method.RawName]; ok {
+		return &preconditionMethod
+	}
+	return nil
+}
+
+// WithAnnotations records the provided method annotations, keyed by method signature, and removes any methods
+// annotated to be skipped from the test methods.
+func (c *Contract) WithAnnotations(annotations map[string]*MethodAnnotations) *Contract {
+	c.annotations = annotations
+	isNotSkipped := func(method abi.Method) bool {
+		methodAnnotations, ok := c.annotations[method.Sig]
+		return !ok || !methodAnnotations.Skip
+	}
+	c.AssertionTestMethods = utils.SliceWhere(c.AssertionTestMethods, isNotSkipped)
+	c.PropertyTestMethods = utils.SliceWhere(c.PropertyTestMethods, isNotSkipped)
+	c.OptimizationTestMethods = utils.SliceWhere(c.OptimizationTestMethods, isNotSkipped)
+	c.FuzzTestMethods = utils.SliceWhere(c.FuzzTestMethods, isNotSkipped)
+	return c
+}
+
+// MethodAnnotations returns the annotations provided for the given method through NatSpec custom tags, or nil if it
+// was not annotated.
+func (c *Contract) MethodAnnotations(method *abi.Method) *MethodAnnotations {
+	return c.annotations[method.Sig]
+}
+
 // Name returns the name of the contract.
 func (c *Contract) Name() string {
 	return c.name
@@ -0,0 +1,224 @@
+package contracts
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// AnnotationSkip is the NatSpec custom tag which disables a test or handler function.
+	AnnotationSkip = "medusa-skip"
+	// AnnotationExpectFail is the NatSpec custom tag which marks a test or handler function as expected to fail.
+	AnnotationExpectFail = "medusa-expect-fail"
+	// AnnotationSequenceLength is the NatSpec custom tag which overrides the maximum call sequence length a test or
+	// handler function is evaluated against.
+	AnnotationSequenceLength = "medusa-seq-len"
+	// AnnotationExclude is the NatSpec custom tag which excludes call sequences calling the given contract method
+	// (e.g. "Contract.func" or "Contract.func(uint256)") from being evaluated against a test or handler function.
+	AnnotationExclude = "medusa-exclude"
+)
+
+// MethodAnnotations describes the per-method test configuration provided through NatSpec custom tags.
+type MethodAnnotations struct {
+	// Skip indicates the method should not be tested or called.
+	Skip bool
+	// ExpectFail indicates the method's test is expected to fail, such that a failure is reported as a pass and vice
+	// versa.
+	ExpectFail bool
+	// SequenceLength describes the maximum call sequence length the method's test is evaluated against, overriding
+	// the configured call sequence length. Zero indicates it was not provided.
+	SequenceLength int
+	// Exclude describes contract methods which, if called in a call sequence, prevent the method's test from being
+	// evaluated against it.
+	Exclude []string
+}
+
+// ParseMethodAnnotations parses the medusa NatSpec custom tags in the provided documentation.
+// Returns the MethodAnnotations parsed, nil if no medusa tags were provided, or an error if a tag was invalid.
+func ParseMethodAnnotations(documentation *types.StructuredDocumentation) (*MethodAnnotations, error) {
+	if documentation == nil {
+		return nil, nil
+	}
+
+	var annotations *MethodAnnotations
+	for _, tag := range documentation.CustomTags() {
+		// Ignore custom tags that are not meant for us.
+		if !strings.HasPrefix(tag.Name, "medusa-") {
+			continue
+		}
+		if annotations == nil {
+			annotations = &MethodAnnotations{}
+		}
+
+		switch tag.Name {
+		case AnnotationSkip:
+			annotations.Skip = true
+		case AnnotationExpectFail:
+			annotations.ExpectFail = true
+		case AnnotationSequenceLength:
+			sequenceLength, err := strconv.Atoi(tag.Value)
+			if err != nil || sequenceLength <= 0 {
+				return nil, fmt.Errorf("@custom:%s expects a positive integer, got '%s'", tag.Name, tag.Value)
+			}
+			annotations.SequenceLength = sequenceLength
+		case AnnotationExclude:
+			excluded := strings.Fields(tag.Value)
+			if len(excluded) == 0 {
+				return nil, fmt.Errorf("@custom:%s expects one or more contract methods (e.g. Contract.func)", tag.Name)
+			}
+			for _, method := range excluded {
+				if !strings.Contains(method, ".") {
+					return nil, fmt.Errorf("@custom:%s expects contract methods of the form Contract.func, got '%s'", tag.Name, method)
+				}
+			}
+			annotations.Exclude = append(annotations.Exclude, excluded...)
+		default:
+			return nil, fmt.Errorf("unknown NatSpec tag @custom:%s", tag.Name)
+		}
+	}
+	return annotations, nil
+}
+
+// Excludes indicates whether the provided contract method was excluded by the annotations. Excluded methods may be
+// referenced either by name or by signature.
+func (a *MethodAnnotations) Excludes(contract *Contract, method *abi.Method) bool {
+	return slices.Contains(a.Exclude, contract.Name()+"."+method.Name) || slices.Contains(a.Exclude, contract.Name()+"."+method.Sig)
+}
+
+// UnresolvedExclude obtains the first contract method excluded by the annotations which does not refer to a method of
+// any of the provided contracts.
+// Returns the unresolved contract method, or an empty string if every excluded contract method was resolved.
+func (a *MethodAnnotations) UnresolvedExclude(contracts Contracts) string {
+	for _, excluded := range a.Exclude {
+		if !excludedMethodExists(excluded, contracts) {
+			return excluded
+		}
+	}
+	return ""
+}
+
+// excludedMethodExists indicates whether the provided excluded contract method, referenced either by name or by
+// signature, refers to a method of any of the provided contracts.
+func excludedMethodExists(excluded string, contracts Contracts) bool {
+	for _, contract := range contracts {
+		for _, method := range contract.CompiledContract().Abi.Methods {
+			if excluded == contract.Name()+"."+method.Name || excluded == contract.Name()+"."+method.Sig {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String returns the NatSpec custom tags the annotations were parsed from.
+func (a *MethodAnnotations) String() string {
+	tags := make([]string, 0)
+	if a.Skip {
+		tags = append(tags, "@custom:"+AnnotationSkip)
+	}
+	if a.ExpectFail {
+		tags = append(tags, "@custom:"+AnnotationExpectFail)
+	}
+	if a.SequenceLength > 0 {
+		tags = append(tags, fmt.Sprintf("@custom:%s %d", AnnotationSequenceLength, a.SequenceLength))
+	}
+	if len(a.Exclude) > 0 {
+		tags = append(tags, fmt.Sprintf("@custom:%s %s", AnnotationExclude, strings.Join(a.Exclude, " ")))
+	}
+	return strings.Join(tags, ", ")
+}
+
+// AnnotationResolver resolves the MethodAnnotations of contracts within a compilation, accounting for the NatSpec
+// documentation inherited from base contracts.
+type AnnotationResolver struct {
+	// contractDefinitions maps AST node IDs to their contract definitions across every source in the compilation.
+	contractDefinitions map[int]types.ContractDefinition
+
+	// sourceContractDefinitions maps source paths to the contract definitions declared in them, by name.
+	sourceContractDefinitions map[string]map[string]types.ContractDefinition
+}
+
+// NewAnnotationResolver parses the ASTs of every source in the provided compilation and returns an
+// AnnotationResolver for its contracts, or an error if one occurred.
+func NewAnnotationResolver(compilation *types.Compilation) (*AnnotationResolver, error) {
+	r := &AnnotationResolver{
+		contractDefinitions:       make(map[int]types.ContractDefinition),
+		sourceContractDefinitions: make(map[string]map[string]types.ContractDefinition),
+	}
+	for sourcePath, source := range compilation.SourcePathToArtifact {
+		ast, err := types.ParseAST(source.Ast)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse AST for '%v': %v", sourcePath, err)
+		}
+
+		r.sourceContractDefinitions[sourcePath] = make(map[string]types.ContractDefinition)
+		for _, node := range ast.Nodes {
+			if contractDefinition, ok := node.(types.ContractDefinition); ok {
+				r.contractDefinitions[contractDefinition.ID] = contractDefinition
+				r.sourceContractDefinitions[sourcePath][contractDefinition.Name] = contractDefinition
+			}
+		}
+	}
+	return r, nil
+}
+
+// Resolve obtains the MethodAnnotations for each method of the provided contract, keyed by method signature. If a
+// method is not documented in the contract itself, the documentation of the most derived base contract defining it
+// is used.
+// Returns the annotations for each annotated method, or an error if an annotation was invalid.
+func (r *AnnotationResolver) Resolve(contract *Contract) (map[string]*MethodAnnotations, error) {
+	annotations := make(map[string]*MethodAnnotations)
+	contractDefinition, ok := r.sourceContractDefinitions[contract.SourcePath()][contract.Name()]
+	if !ok {
+		return annotations, nil
+	}
+
+	for _, method := range contract.CompiledContract().Abi.Methods {
+		selector := hex.EncodeToString(method.ID)
+		for _, baseId := range contractDefinition.LinearizedBaseContracts {
+			// Find the documented function definition for this method in the base contract, if any.
+			functionDefinition := r.findFunctionDefinition(baseId, method.Name, selector)
+			if functionDefinition == nil || functionDefinition.Documentation == nil {
+				continue
+			}
+
+			// Solidity only inherits documentation if none is provided, so we stop at the first one we find.
+			methodAnnotations, err := ParseMethodAnnotations(functionDefinition.Documentation)
+			if err != nil {
+				return nil, fmt.Errorf("invalid annotation for method %s.%s: %v", contract.Name(), method.Sig, err)
+			}
+			if methodAnnotations != nil {
+				annotations[method.Sig] = methodAnnotations
+			}
+			break
+		}
+	}
+	return annotations, nil
+}
+
+// findFunctionDefinition obtains the function definition in the contract definition with the given ID which matches
+// the provided method name and hex-encoded selector, or nil if none could be found.
+func (r *AnnotationResolver) findFunctionDefinition(contractId int, name string, selector string) *types.FunctionDefinition {
+	contractDefinition, ok := r.contractDefinitions[contractId]
+	if !ok {
+		return nil
+	}
+	for _, node := range contractDefinition.Nodes {
+		functionDefinition, ok := node.(types.FunctionDefinition)
+		if !ok || functionDefinition.Name != name {
+			continue
+		}
+
+		// Older compiler versions do not provide selectors, in which case we match by name alone.
+		if functionDefinition.FunctionSelector == "" || functionDefinition.FunctionSelector == selector {
+			return &functionDefinition
+		}
+	}
+	return nil
+}
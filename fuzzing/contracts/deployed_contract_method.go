@@ -15,4 +15,7 @@ type DeployedContractMethod struct {
 
 	// Method describes the method which is available through the deployed contract.
 	Method abi.Method
+
+	// Precondition describes the method which must return true for the method to be called, or nil if it has none.
+	Precondition *abi.Method
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -14,6 +15,7 @@ import (
 	"github.com/crytic/medusa/chain"
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
 	"github.com/crytic/medusa/logging"
 	"github.com/crytic/medusa/logging/colors"
 	"github.com/crytic/medusa/utils"
@@ -34,6 +36,14 @@ type Corpus struct {
 	// coverageMaps describes the total code coverage known to be achieved across all corpus call sequences.
 	coverageMaps *coverage.CoverageMaps
 
+	// interestingIds describes the identifiers flagged as interesting by harnesses (through the medusa cheat code
+	// contract) across all corpus call sequences. Each newly seen identifier is treated as new coverage.
+	interestingIds map[string]struct{}
+
+	// nearMissDistances describes the smallest distance between unequal comparison operands achieved at each
+	// comparison site across all corpus call sequences. Each improvement is treated as new coverage.
+	nearMissDistances map[valuegeneration.ComparisonSite]uint
+
 	// callSequenceFiles represents a corpus directory with files that should be used for mutations.
 	callSequenceFiles *corpusDirectory[calls.CallSequence]
 
@@ -48,7 +58,23 @@ type Corpus struct {
 
 	// mutationTargetSequenceChooser is a provider that allows for weighted random selection of callSequences. If a
 	// call sequence was not found to be compatible with this run, it is not added to the chooser.
-	mutationTargetSequenceChooser *randomutils.WeightedRandomChooser[calls.CallSequence]
+	mutationTargetSequenceChooser *randomutils.WeightedRandomChooser[*mutationTarget]
+
+	// powerSchedule describes the PowerSchedule used to compute the weights of mutationTargetSequenceChooser.
+	powerSchedule PowerSchedule
+
+	// coverageMode describes the units of execution tracked as coverage.
+	coverageMode coverage.CoverageMode
+
+	// storageWriteBucketing describes how values written to storage are grouped when recording storage write coverage.
+	storageWriteBucketing coverage.StorageWriteBucketing
+
+	// comparisonFeedbackEnabled describes whether comparisons are traced when call sequences are replayed, so the
+	// near misses they achieve are recorded.
+	comparisonFeedbackEnabled bool
+
+	// powerScheduler computes the weights of mutationTargetSequenceChooser according to powerSchedule.
+	powerScheduler *powerScheduler
 
 	// callSequencesLock provides thread synchronization to prevent concurrent access errors into
 	// callSequences.
@@ -58,18 +84,53 @@ type Corpus struct {
 	logger *logging.Logger
 }
 
-// NewCorpus initializes a new Corpus object, reading artifacts from the provided directory. If the directory refers
-// to an empty path, artifacts will not be persistently stored.
-func NewCorpus(corpusDirectory string) (*Corpus, error) {
+// interestingSequenceWeightMultiplier describes the factor by which the mutation chooser weight of a call sequence is
+// multiplied when it is added to the corpus due to a harness flagging it as interesting.
+const interestingSequenceWeightMultiplier = 10
+
+// nearMissSequenceWeightMultiplier describes the factor by which the mutation chooser weight of a call sequence is
+// multiplied when its last call brought the operands of a comparison closer to being equal than any prior call.
+const nearMissSequenceWeightMultiplier = 4
+
+// CorpusConfig describes the options a Corpus is created with.
+type CorpusConfig struct {
+	// Directory describes the directory to read artifacts from and store them within. If it refers to an empty path,
+	// artifacts will not be persistently stored.
+	Directory string
+
+	// PowerSchedule determines how call sequences are weighted when choosing mutation targets.
+	PowerSchedule PowerSchedule
+
+	// CoverageMode determines the granularity of the coverage recorded when call sequences are replayed.
+	CoverageMode coverage.CoverageMode
+
+	// StorageWriteBucketing determines how storage writes are bucketed into coverage when call sequences are
+	// replayed.
+	StorageWriteBucketing coverage.StorageWriteBucketing
+
+	// ComparisonFeedbackEnabled determines whether the near misses of comparisons are recorded when call sequences
+	// are replayed.
+	ComparisonFeedbackEnabled bool
+}
+
+// NewCorpus initializes a new Corpus object with the provided CorpusConfig, reading artifacts from its directory.
+func NewCorpus(config CorpusConfig) (*Corpus, error) {
 	var err error
 	corpus := &Corpus{
-		storageDirectory:        corpusDirectory,
-		coverageMaps:            coverage.NewCoverageMaps(),
-		callSequenceFiles:       newCorpusDirectory[calls.CallSequence](""),
-		testResultSequenceFiles: newCorpusDirectory[calls.CallSequence](""),
-		unexecutedCallSequences: make([]calls.CallSequence, 0),
-		logger:                  logging.GlobalLogger.NewSubLogger("module", "corpus"),
+		storageDirectory:          config.Directory,
+		powerSchedule:             config.PowerSchedule,
+		coverageMode:              config.CoverageMode,
+		storageWriteBucketing:     config.StorageWriteBucketing,
+		comparisonFeedbackEnabled: config.ComparisonFeedbackEnabled,
+		coverageMaps:              coverage.NewCoverageMaps(),
+		interestingIds:            make(map[string]struct{}),
+		nearMissDistances:         make(map[valuegeneration.ComparisonSite]uint),
+		callSequenceFiles:         newCorpusDirectory[calls.CallSequence](""),
+		testResultSequenceFiles:   newCorpusDirectory[calls.CallSequence](""),
+		unexecutedCallSequences:   make([]calls.CallSequence, 0),
+		logger:                    logging.GlobalLogger.NewSubLogger("module", "corpus"),
 	}
+	corpus.powerScheduler = newPowerScheduler(config.PowerSchedule, corpus.logger)
 
 	// If we have a corpus directory set, parse our call sequences.
 	if corpus.storageDirectory != "" {
@@ -194,19 +255,109 @@ func (c *Corpus) ActiveMutableSequenceCount() int {
 	return c.mutationTargetSequenceChooser.ChoiceCount()
 }
 
-// RandomMutationTargetSequence returns a weighted random call sequence from the Corpus, or an error if one occurs.
-func (c *Corpus) RandomMutationTargetSequence() (calls.CallSequence, error) {
+// InterestingIdCount returns the count of unique identifiers flagged as interesting by harnesses, which caused call
+// sequences to be added to the corpus.
+func (c *Corpus) InterestingIdCount() int {
+	c.callSequencesLock.Lock()
+	defer c.callSequencesLock.Unlock()
+	return len(c.interestingIds)
+}
+
+// RandomMutationTargetSequence returns a weighted random call sequence from the Corpus, along with the file name of
+// the corpus item it was obtained from, or an error if one occurs. The file name should be provided to
+// CheckSequenceCoverageAndUpdate when checking call sequences mutated from it, so the power schedule can account for
+// whether its mutations achieve new coverage.
+func (c *Corpus) RandomMutationTargetSequence() (calls.CallSequence, string, error) {
 	// If we didn't initialize a chooser, return an error
 	if c.mutationTargetSequenceChooser == nil {
-		return nil, fmt.Errorf("corpus could not return a random call sequence because the corpus was not initialized")
+		return nil, "", fmt.Errorf("corpus could not return a random call sequence because the corpus was not initialized")
+	}
+
+	// Pick a random call sequence.
+	target, err := c.mutationTargetSequenceChooser.Choose()
+	if target == nil || err != nil {
+		return nil, "", err
+	}
+
+	// Record the selection, recomputing the weight of every call sequence periodically.
+	if c.powerScheduler.targetSelected(*target) {
+		c.mutationTargetSequenceChooser.UpdateWeights(c.powerScheduler.updateEnergy)
+	}
+
+	// Clone the call sequence before returning it, so the original is untainted.
+	seq, err := (*target).sequence.Clone()
+	if err != nil {
+		return nil, "", err
+	}
+	return seq, (*target).fileName, nil
+}
+
+// replayCallSequence is a helper method which replays a call sequence on a given chain, using the map of deployed
+// contracts to resolve the contracts it calls (e.g. to check for non-existent methods called, due to code changes).
+// The provided callback is invoked with the coverage maps recorded for each call executed. Chain state is reverted
+// to its starting point once the call sequence has been replayed.
+// Returns an error describing why the call sequence is no longer valid if it could not be replayed, or nil if it
+// could. Also returns an error if an unexpected error occurred.
+func replayCallSequence(sequence calls.CallSequence, testChain *chain.TestChain, deployedContracts map[common.Address]*contracts.Contract, onCallExecuted func(element *calls.CallSequenceElement, coverageMaps *coverage.CoverageMaps) error) (error, error) {
+	// Cache current HeadBlockNumber so that you can reset back to it after the sequence
+	baseBlockNumber := testChain.HeadBlockNumber()
+
+	// Define a variable to track whether we should disable this sequence (if it is no longer applicable in some way).
+	sequenceInvalidError := error(nil)
+	fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
+		// If we are at the end of our sequence, return nil indicating we should stop executing.
+		if currentIndex >= len(sequence) {
+			return nil, nil
+		}
+
+		// If we are deploying a contract and not targeting one with this call, there should be no work to do.
+		currentSequenceElement := sequence[currentIndex]
+		if currentSequenceElement.Call.To == nil {
+			return currentSequenceElement, nil
+		}
+
+		// We are calling a contract with this call, ensure we can resolve the contract call is targeting.
+		resolvedContract, resolvedContractExists := deployedContracts[*currentSequenceElement.Call.To]
+		if !resolvedContractExists {
+			sequenceInvalidError = fmt.Errorf("contract at address '%v' could not be resolved", currentSequenceElement.Call.To.String())
+			return nil, nil
+		}
+		currentSequenceElement.Contract = resolvedContract
+
+		// Next, if our sequence element uses ABI values to produce call data, our deserialized data is not yet
+		// sufficient for runtime use, until we use it to resolve runtime references.
+		callAbiValues := currentSequenceElement.Call.DataAbiValues
+		if callAbiValues != nil {
+			sequenceInvalidError = callAbiValues.Resolve(currentSequenceElement.Contract.CompiledContract().Abi)
+			if sequenceInvalidError != nil {
+				sequenceInvalidError = fmt.Errorf("error resolving method in contract '%v': %v", currentSequenceElement.Contract.Name(), sequenceInvalidError)
+				return nil, nil
+			}
+		}
+		return currentSequenceElement, nil
+	}
+
+	// Define actions to perform after executing each call in the sequence.
+	executionCheckFunc := func(currentlyExecutedSequence calls.CallSequence) (bool, error) {
+		lastExecutedSequenceElement := currentlyExecutedSequence[len(currentlyExecutedSequence)-1]
+		covMaps := coverage.GetCoverageTracerResults(lastExecutedSequenceElement.ChainReference.MessageResults())
+		err := onCallExecuted(lastExecutedSequenceElement, covMaps)
+		return err != nil, err
+	}
+
+	// Execute the call sequence, populating runtime data and collecting coverage data along the way.
+	_, err := calls.ExecuteCallSequenceIteratively(testChain, fetchElementFunc, executionCheckFunc)
+
+	// If we failed to replay a sequence and measure coverage due to an unexpected error, report it.
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize coverage maps from corpus, encountered an error while executing call sequence: %v\n", err)
 	}
 
-	// Pick a random call sequence, then clone it before returning it, so the original is untainted.
-	seq, err := c.mutationTargetSequenceChooser.Choose()
-	if seq == nil || err != nil {
-		return nil, err
+	// Revert chain state to our starting point to test the next sequence.
+	if err := testChain.RevertToBlockNumber(baseBlockNumber); err != nil {
+		return nil, fmt.Errorf("failed to reset the chain while seeding coverage: %v\n", err)
 	}
-	return seq.Clone()
+	return sequenceInvalidError, nil
 }
 
 // initializeSequences is a helper method for Initialize. It validates a list of call sequence files on a given
@@ -215,69 +366,32 @@ func (c *Corpus) RandomMutationTargetSequence() (calls.CallSequence, error) {
 // If this sequence list being initialized is for use with mutations, it is added to the mutationTargetSequenceChooser.
 // Returns an error if one occurs.
 func (c *Corpus) initializeSequences(sequenceFiles *corpusDirectory[calls.CallSequence], testChain *chain.TestChain, deployedContracts map[common.Address]*contracts.Contract, useInMutations bool) error {
-	// Cache current HeadBlockNumber so that you can reset back to it after every sequence
-	baseBlockNumber := testChain.HeadBlockNumber()
-
 	// Loop for each sequence
-	var err error
 	for _, sequenceFileData := range sequenceFiles.files {
 		// Unwrap the underlying sequence.
 		sequence := sequenceFileData.data
 
-		// Define a variable to track whether we should disable this sequence (if it is no longer applicable in some
-		// way).
-		sequenceInvalidError := error(nil)
-		fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
-			// If we are at the end of our sequence, return nil indicating we should stop executing.
-			if currentIndex >= len(sequence) {
-				return nil, nil
-			}
-
-			// If we are deploying a contract and not targeting one with this call, there should be no work to do.
-			currentSequenceElement := sequence[currentIndex]
-			if currentSequenceElement.Call.To == nil {
-				return currentSequenceElement, nil
-			}
+		// Define a variable to track the program counters covered by this sequence, for use by the power schedule.
+		sequenceCoveredPCs := make([]coveredPC, 0)
 
-			// We are calling a contract with this call, ensure we can resolve the contract call is targeting.
-			resolvedContract, resolvedContractExists := deployedContracts[*currentSequenceElement.Call.To]
-			if !resolvedContractExists {
-				sequenceInvalidError = fmt.Errorf("contract at address '%v' could not be resolved", currentSequenceElement.Call.To.String())
-				return nil, nil
-			}
-			currentSequenceElement.Contract = resolvedContract
-
-			// Next, if our sequence element uses ABI values to produce call data, our deserialized data is not yet
-			// sufficient for runtime use, until we use it to resolve runtime references.
-			callAbiValues := currentSequenceElement.Call.DataAbiValues
-			if callAbiValues != nil {
-				sequenceInvalidError = callAbiValues.Resolve(currentSequenceElement.Contract.CompiledContract().Abi)
-				if sequenceInvalidError != nil {
-					sequenceInvalidError = fmt.Errorf("error resolving method in contract '%v': %v", currentSequenceElement.Contract.Name(), sequenceInvalidError)
-					return nil, nil
-				}
-			}
-			return currentSequenceElement, nil
-		}
-
-		// Define actions to perform after executing each call in the sequence.
-		executionCheckFunc := func(currentlyExecutedSequence calls.CallSequence) (bool, error) {
-			// Update our coverage maps for each call executed in our sequence.
-			lastExecutedSequenceElement := currentlyExecutedSequence[len(currentlyExecutedSequence)-1]
-			covMaps := coverage.GetCoverageTracerResults(lastExecutedSequenceElement.ChainReference.MessageResults())
+		// Replay the sequence, updating our coverage maps for each call executed in it.
+		sequenceInvalidError, err := replayCallSequence(sequence, testChain, deployedContracts, func(element *calls.CallSequenceElement, covMaps *coverage.CoverageMaps) error {
+			sequenceCoveredPCs = append(sequenceCoveredPCs, c.powerScheduler.recordCoverage(covMaps)...)
 			_, _, covErr := c.coverageMaps.Update(covMaps)
 			if covErr != nil {
-				return true, covErr
+				return covErr
 			}
-			return false, nil
-		}
 
-		// Execute each call sequence, populating runtime data and collecting coverage data along the way.
-		_, err = calls.ExecuteCallSequenceIteratively(testChain, fetchElementFunc, executionCheckFunc)
+			// Record any identifiers the harness flagged as interesting, so they are not treated as new later.
+			c.updateInterestingIds(chain.GetFuzzerHintResults(element.ChainReference.MessageResults()))
+			c.updateNearMissDistances(valuegeneration.GetComparisonTracerResults(element.ChainReference.MessageResults()))
 
-		// If we failed to replay a sequence and measure coverage due to an unexpected error, report it.
+			// Memory optimization: Remove the comparison results now that we consumed them.
+			valuegeneration.RemoveComparisonTracerResults(element.ChainReference.MessageResults())
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("failed to initialize coverage maps from corpus, encountered an error while executing call sequence: %v\n", err)
+			return err
 		}
 
 		// If the sequence was replayed successfully, we add it. If it was not, we exclude it with a warning.
@@ -298,37 +412,31 @@ func (c *Corpus) initializeSequences(sequenceFiles *corpusDirectory[calls.CallSe
 				if weight == nil {
 					weight = big.NewInt(1)
 				}
-				c.mutationTargetSequenceChooser.AddChoices(randomutils.NewWeightedRandomChoice[calls.CallSequence](sequence, weight))
+				c.addMutationTarget(&mutationTarget{
+					fileName:   sequenceFileData.fileName,
+					sequence:   sequence,
+					baseWeight: weight,
+					coveredPCs: sequenceCoveredPCs,
+				})
 			}
 			c.unexecutedCallSequences = append(c.unexecutedCallSequences, sequence)
 		} else {
 			c.logger.Debug("Corpus item ", colors.Bold, sequenceFileData.fileName, colors.Reset, " disabled due to error when replaying it", sequenceInvalidError)
 		}
-
-		// Revert chain state to our starting point to test the next sequence.
-		if err := testChain.RevertToBlockNumber(baseBlockNumber); err != nil {
-			return fmt.Errorf("failed to reset the chain while seeding coverage: %v\n", err)
-		}
 	}
 	return nil
 }
 
-// Initialize initializes any runtime data needed for a Corpus on startup. Call sequences are replayed on the post-setup
-// (deployment) test chain to calculate coverage, while resolving references to compiled contracts.
-// Returns the active number of corpus items, total number of corpus items, or an error if one occurred. If an error
-// is returned, then the corpus counts returned will always be zero.
-func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions contracts.Contracts) (int, int, error) {
-	// Acquire our call sequences lock during the duration of this method.
-	c.callSequencesLock.Lock()
-	defer c.callSequencesLock.Unlock()
-
-	// Initialize our call sequence structures.
-	c.mutationTargetSequenceChooser = randomutils.NewWeightedRandomChooser[calls.CallSequence]()
-	c.unexecutedCallSequences = make([]calls.CallSequence, 0)
-
+// cloneTestChainForReplay is a helper method which clones the provided test chain from genesis for call sequences to be
+// replayed on it. A coverage tracer is attached to the clone, recording coverage according to the provided
+// coverage.CoverageMode and coverage.StorageWriteBucketing, alongside a comparison tracer if comparisonFeedbackEnabled
+// is set. Contract deployments on the clone are matched against the provided contract definitions, so the contracts
+// called by call sequences can be resolved.
+// Returns the cloned chain, the map of its deployed contracts (kept up to date as contracts are deployed or removed),
+// or an error if one occurred.
+func cloneTestChainForReplay(baseTestChain *chain.TestChain, contractDefinitions contracts.Contracts, coverageMode coverage.CoverageMode, storageWriteBucketing coverage.StorageWriteBucketing, comparisonFeedbackEnabled bool) (*chain.TestChain, map[common.Address]*contracts.Contract, error) {
 	// Create a coverage tracer to track coverage across all blocks.
-	c.coverageMaps = coverage.NewCoverageMaps()
-	coverageTracer := coverage.NewCoverageTracer()
+	coverageTracer := coverage.NewCoverageTracer(coverageMode, storageWriteBucketing)
 
 	// Create our structure and event listeners to track deployed contracts
 	deployedContracts := make(map[common.Address]*contracts.Contract, 0)
@@ -338,6 +446,12 @@ func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions
 		// After genesis, prior to adding other blocks, we attach our coverage tracer
 		newChain.AddTracer(coverageTracer.NativeTracer(), true, false)
 
+		// If comparison feedback is enabled, we also attach a comparison tracer to track how close comparisons came to
+		// being equal across all blocks.
+		if comparisonFeedbackEnabled {
+			newChain.AddTracer(valuegeneration.NewComparisonTracer().NativeTracer(), true, false)
+		}
+
 		// We also track any contract deployments, so we can resolve contract/method definitions for corpus call
 		// sequences.
 		newChain.Events.ContractDeploymentAddedEventEmitter.Subscribe(func(event chain.ContractDeploymentsAddedEvent) error {
@@ -354,7 +468,35 @@ func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions
 		return nil
 	})
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to initialize coverage maps, base test chain cloning encountered error: %v", err)
+		return nil, nil, fmt.Errorf("failed to initialize coverage maps, base test chain cloning encountered error: %v", err)
+	}
+	return testChain, deployedContracts, nil
+}
+
+// Initialize initializes any runtime data needed for a Corpus on startup. Call sequences are replayed on the post-setup
+// (deployment) test chain to calculate coverage, while resolving references to compiled contracts. The provided random
+// provider is used to choose call sequences to mutate.
+// Returns the active number of corpus items, total number of corpus items, or an error if one occurred. If an error
+// is returned, then the corpus counts returned will always be zero.
+func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions contracts.Contracts, randomProvider *rand.Rand) (int, int, error) {
+	// Acquire our call sequences lock during the duration of this method.
+	c.callSequencesLock.Lock()
+	defer c.callSequencesLock.Unlock()
+
+	// Initialize our call sequence structures.
+	c.mutationTargetSequenceChooser = randomutils.NewWeightedRandomChooserWithRand[*mutationTarget](randomProvider, &sync.Mutex{})
+	c.powerScheduler = newPowerScheduler(c.powerSchedule, c.logger)
+	c.unexecutedCallSequences = make([]calls.CallSequence, 0)
+
+	// Reset our coverage maps, interesting identifiers, and near miss distances.
+	c.coverageMaps = coverage.NewCoverageMaps()
+	c.interestingIds = make(map[string]struct{})
+	c.nearMissDistances = make(map[valuegeneration.ComparisonSite]uint)
+
+	// Clone our test chain, tracking coverage and contract deployments from genesis.
+	testChain, deployedContracts, err := cloneTestChainForReplay(baseTestChain, contractDefinitions, c.coverageMode, c.storageWriteBucketing, c.comparisonFeedbackEnabled)
+	if err != nil {
+		return 0, 0, err
 	}
 
 	// Set our coverage maps to those collected when replaying all blocks when cloning.
@@ -392,9 +534,17 @@ func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions
 	return corpusSequencesActive, corpusSequencesTotal, nil
 }
 
-// addCallSequence adds a call sequence to the corpus in a given corpus directory.
+// addMutationTarget adds the provided mutation target to the mutationTargetSequenceChooser, with the energy computed
+// by the power schedule as its weight.
+func (c *Corpus) addMutationTarget(target *mutationTarget) {
+	c.powerScheduler.addTarget(target)
+	c.mutationTargetSequenceChooser.AddChoices(randomutils.NewWeightedRandomChoice(target, target.energy))
+}
+
+// addCallSequence adds a call sequence to the corpus in a given corpus directory. The provided program counters
+// covered by the call sequence are used by the power schedule if it is used in mutations.
 // Returns an error, if one occurs.
-func (c *Corpus) addCallSequence(sequenceFiles *corpusDirectory[calls.CallSequence], sequence calls.CallSequence, useInMutations bool, mutationChooserWeight *big.Int, flushImmediately bool) error {
+func (c *Corpus) addCallSequence(sequenceFiles *corpusDirectory[calls.CallSequence], sequence calls.CallSequence, useInMutations bool, mutationChooserWeight *big.Int, coveredPCs []coveredPC, flushImmediately bool) error {
 	// Acquire a thread lock during modification of call sequence lists.
 	c.callSequencesLock.Lock()
 
@@ -432,7 +582,12 @@ func (c *Corpus) addCallSequence(sequenceFiles *corpusDirectory[calls.CallSequen
 		if mutationChooserWeight == nil {
 			mutationChooserWeight = big.NewInt(1)
 		}
-		c.mutationTargetSequenceChooser.AddChoices(randomutils.NewWeightedRandomChoice[calls.CallSequence](sequence, mutationChooserWeight))
+		c.addMutationTarget(&mutationTarget{
+			fileName:   fileName,
+			sequence:   sequence,
+			baseWeight: mutationChooserWeight,
+			coveredPCs: coveredPCs,
+		})
 	}
 
 	// Unlock now, as flushing will lock on its own.
@@ -450,14 +605,15 @@ func (c *Corpus) addCallSequence(sequenceFiles *corpusDirectory[calls.CallSequen
 // recorded.
 // Returns an error, if one occurs.
 func (c *Corpus) AddTestResultCallSequence(callSequence calls.CallSequence, mutationChooserWeight *big.Int, flushImmediately bool) error {
-	return c.addCallSequence(c.testResultSequenceFiles, callSequence, false, mutationChooserWeight, flushImmediately)
+	return c.addCallSequence(c.testResultSequenceFiles, callSequence, false, mutationChooserWeight, nil, flushImmediately)
 }
 
 // CheckSequenceCoverageAndUpdate checks if the most recent call executed in the provided call sequence achieved
 // coverage the Corpus did not with any of its call sequences. If it did, the call sequence is added to the corpus
-// and the Corpus coverage maps are updated accordingly.
+// and the Corpus coverage maps are updated accordingly. The provided mutation targets describe the file names of the
+// corpus items the call sequence was mutated from, if any, as returned by RandomMutationTargetSequence.
 // Returns an error if one occurs.
-func (c *Corpus) CheckSequenceCoverageAndUpdate(callSequence calls.CallSequence, mutationChooserWeight *big.Int, flushImmediately bool) error {
+func (c *Corpus) CheckSequenceCoverageAndUpdate(callSequence calls.CallSequence, mutationChooserWeight *big.Int, mutationTargets []string, flushImmediately bool) error {
 	// If we have coverage-guided fuzzing disabled or no calls in our sequence, there is nothing to do.
 	if len(callSequence) == 0 {
 		return nil
@@ -477,23 +633,106 @@ func (c *Corpus) CheckSequenceCoverageAndUpdate(callSequence calls.CallSequence,
 	// Memory optimization: Remove them from the results now that we obtained them, to free memory later.
 	coverage.RemoveCoverageTracerResults(lastMessageResult)
 
+	// Record the program counters hit by this call for the power schedule, before they are merged into our total
+	// coverage maps.
+	lastMessageCoveredPCs := c.powerScheduler.recordCoverage(lastMessageCoverageMaps)
+
 	// Merge the coverage maps into our total coverage maps and check if we had an update.
 	coverageUpdated, revertedCoverageUpdated, err := c.coverageMaps.Update(lastMessageCoverageMaps)
 	if err != nil {
 		return err
 	}
 
-	// If we had an increase in non-reverted or reverted coverage, we save the sequence.
-	if coverageUpdated || revertedCoverageUpdated {
+	// Record any feedback provided by the harness or comparisons, weighting the sequence accordingly.
+	mutationChooserWeight, feedbackUpdated := c.updateFeedback(mutationChooserWeight, chain.GetFuzzerHintResults(lastMessageResult), valuegeneration.GetComparisonTracerResults(lastMessageResult))
+
+	// Memory optimization: Remove the comparison results now that we consumed them.
+	valuegeneration.RemoveComparisonTracerResults(lastMessageResult)
+
+	// If we had an increase in non-reverted or reverted coverage, new interesting identifiers, or closer comparisons,
+	// we save the sequence.
+	if coverageUpdated || revertedCoverageUpdated || feedbackUpdated {
 		// If we achieved new coverage, save this sequence for mutation purposes.
-		err = c.addCallSequence(c.callSequenceFiles, callSequence, true, mutationChooserWeight, flushImmediately)
+		err = c.addCallSequence(c.callSequenceFiles, callSequence, true, mutationChooserWeight, lastMessageCoveredPCs, flushImmediately)
 		if err != nil {
 			return err
 		}
+
+		// Credit the corpus items this sequence was mutated from for achieving new coverage.
+		c.powerScheduler.targetsAchievedNewCoverage(mutationTargets)
 	}
 	return nil
 }
 
+// updateFeedback records the interesting identifiers and comparison near misses provided in the given fuzzer hint
+// and comparison results. If the harness flagged a previously unseen identifier as interesting, the provided mutation
+// chooser weight is multiplied by interestingSequenceWeightMultiplier. Similarly, if a comparison was brought closer
+// to being equal than before, it is multiplied by nearMissSequenceWeightMultiplier, so mutations of the sequence may
+// make its operands equal. A nil weight is treated as a weight of one.
+// Returns the resulting mutation chooser weight, and a boolean indicating whether any feedback was new.
+func (c *Corpus) updateFeedback(mutationChooserWeight *big.Int, hints *chain.FuzzerHintResults, comparisonResults *valuegeneration.ComparisonResults) (*big.Int, bool) {
+	c.callSequencesLock.Lock()
+	interestingIdsUpdated := c.updateInterestingIds(hints)
+	nearMissesUpdated := c.updateNearMissDistances(comparisonResults)
+	c.callSequencesLock.Unlock()
+	if !interestingIdsUpdated && !nearMissesUpdated {
+		return mutationChooserWeight, false
+	}
+
+	// Weight the sequence according to the feedback that was new.
+	if mutationChooserWeight == nil {
+		mutationChooserWeight = big.NewInt(1)
+	}
+	if interestingIdsUpdated {
+		mutationChooserWeight = new(big.Int).Mul(mutationChooserWeight, big.NewInt(interestingSequenceWeightMultiplier))
+	}
+	if nearMissesUpdated {
+		mutationChooserWeight = new(big.Int).Mul(mutationChooserWeight, big.NewInt(nearMissSequenceWeightMultiplier))
+	}
+	return mutationChooserWeight, true
+}
+
+// updateInterestingIds records the interesting identifiers provided in the given fuzzer hint results. The caller is
+// responsible for acquiring callSequencesLock if the corpus may be accessed concurrently.
+// Returns a boolean indicating whether any identifier was not previously recorded.
+func (c *Corpus) updateInterestingIds(hints *chain.FuzzerHintResults) bool {
+	// If no hints were provided, there is nothing to record.
+	if hints == nil {
+		return false
+	}
+
+	// Record each identifier, tracking whether any of them were new.
+	updated := false
+	for _, id := range hints.InterestingIds {
+		key := id.String()
+		if _, exists := c.interestingIds[key]; !exists {
+			c.interestingIds[key] = struct{}{}
+			updated = true
+		}
+	}
+	return updated
+}
+
+// updateNearMissDistances records the near miss distances of comparison sites provided in the given comparison
+// results. The caller is responsible for acquiring callSequencesLock if the corpus may be accessed concurrently.
+// Returns a boolean indicating whether any comparison site achieved a smaller distance than previously recorded.
+func (c *Corpus) updateNearMissDistances(results *valuegeneration.ComparisonResults) bool {
+	// If no results were provided, there is nothing to record.
+	if results == nil {
+		return false
+	}
+
+	// Record each distance, tracking whether any of them improved.
+	updated := false
+	for site, distance := range results.NearMisses {
+		if existingDistance, exists := c.nearMissDistances[site]; !exists || distance < existingDistance {
+			c.nearMissDistances[site] = distance
+			updated = true
+		}
+	}
+	return updated
+}
+
 // UnexecutedCallSequence returns a call sequence loaded from disk which has not yet been returned by this method.
 // It is intended to be used by the fuzzer to run all un-executed call sequences (without mutations) to check for test
 // failures. If a call sequence is returned, it will not be returned by this method again.
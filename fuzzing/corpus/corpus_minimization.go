@@ -0,0 +1,442 @@
+package corpus
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/crytic/medusa/chain"
+	chainTypes "github.com/crytic/medusa/chain/types"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MinimizationResult describes the outcome of minimizing a Corpus with Corpus.Minimize.
+type MinimizationResult struct {
+	// TotalSequences describes the amount of call sequences in the corpus prior to minimization.
+	TotalSequences int
+
+	// KeptSequences describes the amount of call sequences kept in the corpus.
+	KeptSequences int
+
+	// RedundantSequences describes the amount of call sequences archived because the coverage they achieved was
+	// preserved by the call sequences kept.
+	RedundantSequences int
+
+	// InvalidSequences describes the amount of call sequences archived because they could no longer be replayed
+	// (e.g. due to code changes).
+	InvalidSequences int
+
+	// ArchiveDirectory describes the directory archived call sequences were moved to.
+	ArchiveDirectory string
+}
+
+// coverageFeatureKind describes the kind of coverage a coverageFeature represents.
+type coverageFeatureKind int
+
+const (
+	// coverageFeatureKindPC describes a program counter within the code with a given code hash, executed either
+	// successfully or in a reverted call.
+	coverageFeatureKindPC coverageFeatureKind = iota
+
+	// coverageFeatureKindInterestingId describes an identifier flagged as interesting by a harness.
+	coverageFeatureKindInterestingId
+
+	// coverageFeatureKindNearMiss describes the closest a comparison at a given program counter within the code with a
+	// given code hash came to being equal.
+	coverageFeatureKindNearMiss
+
+	// coverageFeatureKindEdge describes an edge within the code with a given code hash, taken a number of times within
+	// a given hit count bucket, either successfully or in a reverted call.
+	coverageFeatureKindEdge
+
+	// coverageFeatureKindStorageWrite describes a write of a value within a given bucket to a storage slot of a given
+	// contract.
+	coverageFeatureKindStorageWrite
+)
+
+// coverageFeature describes a unit of coverage preserved by corpus minimization. Only the fields relevant to its kind
+// are set.
+type coverageFeature struct {
+	// kind describes the kind of coverage the feature represents.
+	kind coverageFeatureKind
+	// codeHash describes the lookup hash of the code the program counter belongs to.
+	codeHash common.Hash
+	// pc describes the program counter.
+	pc int
+	// edgeKey describes the key of the edge, as obtained by the coverage package.
+	edgeKey uint64
+	// edgeBucket describes the hit count bucket (as a bit flag) the edge was taken within.
+	edgeBucket uint8
+	// reverted indicates whether the program counter was executed, or the edge taken, in a reverted call.
+	reverted bool
+	// interestingId describes the identifier flagged as interesting.
+	interestingId string
+	// nearMissDistance describes how far the comparison at the program counter was from being equal.
+	nearMissDistance uint
+	// storageWrite describes the storage write recorded.
+	storageWrite coverage.StorageWrite
+}
+
+// compareCoverageFeatures compares two coverage features, so they can be ordered deterministically.
+// Returns a negative number if a is ordered before b, a positive number if it is ordered after b, or zero if they are
+// equal.
+func compareCoverageFeatures(a coverageFeature, b coverageFeature) int {
+	if c := cmp.Compare(a.kind, b.kind); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(a.codeHash[:], b.codeHash[:]); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.pc, b.pc); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.edgeKey, b.edgeKey); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.edgeBucket, b.edgeBucket); c != 0 {
+		return c
+	}
+	if a.reverted != b.reverted {
+		if a.reverted {
+			return 1
+		}
+		return -1
+	}
+	if c := cmp.Compare(a.interestingId, b.interestingId); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.nearMissDistance, b.nearMissDistance); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(a.storageWrite.Address[:], b.storageWrite.Address[:]); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(a.storageWrite.Slot[:], b.storageWrite.Slot[:]); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.storageWrite.Bucket[:], b.storageWrite.Bucket[:])
+}
+
+// minimizationCoverage describes the coverage achieved by replaying call sequences during corpus minimization.
+type minimizationCoverage struct {
+	// coverageMaps describes the code coverage achieved.
+	coverageMaps *coverage.CoverageMaps
+	// interestingIds describes the identifiers flagged as interesting by harnesses.
+	interestingIds map[string]struct{}
+	// nearMisses describes the closest distance from being equal achieved by comparisons at each comparison site.
+	nearMisses map[valuegeneration.ComparisonSite]uint
+}
+
+// newMinimizationCoverage creates a new minimizationCoverage with no coverage achieved.
+func newMinimizationCoverage() *minimizationCoverage {
+	return &minimizationCoverage{
+		coverageMaps:   coverage.NewCoverageMaps(),
+		interestingIds: make(map[string]struct{}),
+		nearMisses:     make(map[valuegeneration.ComparisonSite]uint),
+	}
+}
+
+// update records the coverage achieved by a message, given its execution results.
+// Returns an error if one occurred.
+func (m *minimizationCoverage) update(messageResults *chainTypes.MessageResults) error {
+	_, _, err := m.coverageMaps.Update(coverage.GetCoverageTracerResults(messageResults))
+	if err != nil {
+		return err
+	}
+	if hints := chain.GetFuzzerHintResults(messageResults); hints != nil {
+		for _, id := range hints.InterestingIds {
+			m.interestingIds[id.String()] = struct{}{}
+		}
+	}
+	if comparisonResults := valuegeneration.GetComparisonTracerResults(messageResults); comparisonResults != nil {
+		for site, distance := range comparisonResults.NearMisses {
+			if existingDistance, exists := m.nearMisses[site]; !exists || distance < existingDistance {
+				m.nearMisses[site] = distance
+			}
+		}
+
+		// Memory optimization: Remove the comparison results now that we consumed them.
+		valuegeneration.RemoveComparisonTracerResults(messageResults)
+	}
+	return nil
+}
+
+// minimizationCandidate describes a call sequence considered for corpus minimization.
+type minimizationCandidate struct {
+	// fileName describes the name of the corpus file the call sequence is stored in.
+	fileName string
+	// length describes the amount of calls in the call sequence.
+	length int
+	// features describes the coverage achieved by the call sequence.
+	features []coverageFeature
+}
+
+// getCoverageFeatures obtains the coverage features described by the provided minimization coverage, excluding any
+// contained in the provided set of features to exclude.
+func getCoverageFeatures(minimizationCoverage *minimizationCoverage, excluded map[coverageFeature]struct{}) []coverageFeature {
+	features := make([]coverageFeature, 0)
+	addFeature := func(feature coverageFeature) {
+		if _, ok := excluded[feature]; !ok {
+			features = append(features, feature)
+		}
+	}
+
+	// Add every program counter executed, successfully or in a reverted call.
+	for _, reverted := range []bool{false, true} {
+		pcsByCodeHash := minimizationCoverage.coverageMaps.SuccessfulPCs()
+		if reverted {
+			pcsByCodeHash = minimizationCoverage.coverageMaps.RevertedPCs()
+		}
+		for codeHash, pcs := range pcsByCodeHash {
+			for _, pc := range pcs {
+				addFeature(coverageFeature{kind: coverageFeatureKindPC, codeHash: codeHash, pc: pc, reverted: reverted})
+			}
+		}
+	}
+
+	// Add every hit count bucket each edge was taken within, successfully or in a reverted call.
+	for _, reverted := range []bool{false, true} {
+		edgesByCodeHash := minimizationCoverage.coverageMaps.SuccessfulEdges()
+		if reverted {
+			edgesByCodeHash = minimizationCoverage.coverageMaps.RevertedEdges()
+		}
+		for codeHash, bucketsByEdgeKey := range edgesByCodeHash {
+			for edgeKey, buckets := range bucketsByEdgeKey {
+				for bucket := uint8(1); bucket != 0; bucket <<= 1 {
+					if buckets&bucket != 0 {
+						addFeature(coverageFeature{kind: coverageFeatureKindEdge, codeHash: codeHash, edgeKey: edgeKey, edgeBucket: bucket, reverted: reverted})
+					}
+				}
+			}
+		}
+	}
+
+	// Add every storage write recorded.
+	for _, storageWrite := range minimizationCoverage.coverageMaps.StorageWrites() {
+		addFeature(coverageFeature{kind: coverageFeatureKindStorageWrite, storageWrite: storageWrite})
+	}
+
+	// Add every identifier flagged as interesting.
+	for id := range minimizationCoverage.interestingIds {
+		addFeature(coverageFeature{kind: coverageFeatureKindInterestingId, interestingId: id})
+	}
+
+	// Add the closest distance achieved at every comparison site.
+	for site, distance := range minimizationCoverage.nearMisses {
+		addFeature(coverageFeature{kind: coverageFeatureKindNearMiss, codeHash: site.CodeHash, pc: int(site.PC), nearMissDistance: distance})
+	}
+	return features
+}
+
+// retainClosestNearMisses removes near miss features from the provided candidates unless they describe the closest
+// distance achieved at their comparison site by any candidate, and the provided setup near misses did not achieve a
+// distance at least as close. This ensures only the call sequences achieving the closest near misses are preserved.
+func retainClosestNearMisses(candidates []*minimizationCandidate, setupNearMisses map[valuegeneration.ComparisonSite]uint) {
+	// Determine the closest distance achieved by any candidate at each comparison site.
+	closestDistances := make(map[valuegeneration.ComparisonSite]uint)
+	for _, candidate := range candidates {
+		for _, feature := range candidate.features {
+			if feature.kind != coverageFeatureKindNearMiss {
+				continue
+			}
+			site := valuegeneration.ComparisonSite{CodeHash: feature.codeHash, PC: uint64(feature.pc)}
+			if existingDistance, exists := closestDistances[site]; !exists || feature.nearMissDistance < existingDistance {
+				closestDistances[site] = feature.nearMissDistance
+			}
+		}
+	}
+
+	// Filter each candidate's near miss features to the closest ones not already achieved by setup.
+	for _, candidate := range candidates {
+		features := make([]coverageFeature, 0, len(candidate.features))
+		for _, feature := range candidate.features {
+			if feature.kind == coverageFeatureKindNearMiss {
+				site := valuegeneration.ComparisonSite{CodeHash: feature.codeHash, PC: uint64(feature.pc)}
+				if feature.nearMissDistance != closestDistances[site] {
+					continue
+				}
+				if setupDistance, exists := setupNearMisses[site]; exists && setupDistance <= feature.nearMissDistance {
+					continue
+				}
+			}
+			features = append(features, feature)
+		}
+		candidate.features = features
+	}
+}
+
+// selectMinimalCoverageSet greedily selects a subset of the provided candidates which achieves every coverage feature
+// achieved by any of them. Features achieved by the fewest candidates are considered first, and each feature not yet
+// achieved by the selection is covered by selecting the shortest candidate achieving it.
+// Returns the file names of the selected candidates.
+func selectMinimalCoverageSet(candidates []*minimizationCandidate) map[string]struct{} {
+	// Order our candidates by length, so the first candidate achieving a feature is the shortest one.
+	sortedCandidates := append([]*minimizationCandidate{}, candidates...)
+	sort.SliceStable(sortedCandidates, func(i, j int) bool {
+		if sortedCandidates[i].length != sortedCandidates[j].length {
+			return sortedCandidates[i].length < sortedCandidates[j].length
+		}
+		return sortedCandidates[i].fileName < sortedCandidates[j].fileName
+	})
+
+	// Index the candidates achieving each feature.
+	featureCandidates := make(map[coverageFeature][]*minimizationCandidate)
+	for _, candidate := range sortedCandidates {
+		for _, feature := range candidate.features {
+			featureCandidates[feature] = append(featureCandidates[feature], candidate)
+		}
+	}
+
+	// Order the features so the rarest ones are considered first.
+	features := make([]coverageFeature, 0, len(featureCandidates))
+	for feature := range featureCandidates {
+		features = append(features, feature)
+	}
+	sort.Slice(features, func(i, j int) bool {
+		if len(featureCandidates[features[i]]) != len(featureCandidates[features[j]]) {
+			return len(featureCandidates[features[i]]) < len(featureCandidates[features[j]])
+		}
+		return compareCoverageFeatures(features[i], features[j]) < 0
+	})
+
+	// For every feature not yet achieved by our selection, select the shortest candidate achieving it.
+	selected := make(map[string]struct{})
+	achieved := make(map[coverageFeature]struct{})
+	for _, feature := range features {
+		if _, ok := achieved[feature]; ok {
+			continue
+		}
+		candidate := featureCandidates[feature][0]
+		selected[candidate.fileName] = struct{}{}
+		for _, candidateFeature := range candidate.features {
+			achieved[candidateFeature] = struct{}{}
+		}
+	}
+	return selected
+}
+
+// Minimize replays every call sequence in the corpus on the post-setup (deployment) test chain to measure the
+// coverage each achieves, and keeps a minimal set of call sequences which preserves the total coverage achieved,
+// preferring shorter call sequences. Call sequences which are not kept, or which can no longer be replayed, are moved
+// to the provided archive directory rather than deleted. Call sequences recorded for test results are not affected.
+// Returns a MinimizationResult describing the outcome, or an error if one occurred.
+func (c *Corpus) Minimize(baseTestChain *chain.TestChain, contractDefinitions contracts.Contracts, archiveDirectory string) (*MinimizationResult, error) {
+	// If we have no corpus directory, there is nothing to minimize.
+	if c.storageDirectory == "" {
+		return nil, fmt.Errorf("corpus could not be minimized because no corpus directory was provided")
+	}
+
+	// Acquire our call sequences lock during the duration of this method.
+	c.callSequencesLock.Lock()
+	defer c.callSequencesLock.Unlock()
+
+	// Clone our test chain, tracking coverage and contract deployments from genesis.
+	testChain, deployedContracts, err := cloneTestChainForReplay(baseTestChain, contractDefinitions, c.coverageMode, c.storageWriteBucketing, c.comparisonFeedbackEnabled)
+	if err != nil {
+		return nil, err
+	}
+	defer testChain.Close()
+
+	// Measure the coverage achieved when deploying contracts, as it is achieved regardless of which call sequences
+	// are kept.
+	setupCoverage := newMinimizationCoverage()
+	for _, block := range testChain.CommittedBlocks() {
+		for _, messageResults := range block.MessageResults {
+			err = setupCoverage.update(messageResults)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	setupFeatures := make(map[coverageFeature]struct{})
+	for _, feature := range getCoverageFeatures(setupCoverage, nil) {
+		setupFeatures[feature] = struct{}{}
+	}
+
+	// Replay every call sequence, measuring the coverage it achieves.
+	candidates := make([]*minimizationCandidate, 0)
+	invalidFileNames := make(map[string]struct{})
+	for _, sequenceFileData := range c.callSequenceFiles.files {
+		sequenceCoverage := newMinimizationCoverage()
+		sequenceInvalidError, err := replayCallSequence(sequenceFileData.data, testChain, deployedContracts, func(element *calls.CallSequenceElement, covMaps *coverage.CoverageMaps) error {
+			return sequenceCoverage.update(element.ChainReference.MessageResults())
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// If the sequence could not be replayed, it will be archived.
+		if sequenceInvalidError != nil {
+			c.logger.Debug("Corpus item ", colors.Bold, sequenceFileData.fileName, colors.Reset, " will be archived due to error when replaying it", sequenceInvalidError)
+			invalidFileNames[sequenceFileData.fileName] = struct{}{}
+			continue
+		}
+		candidates = append(candidates, &minimizationCandidate{
+			fileName: sequenceFileData.fileName,
+			length:   len(sequenceFileData.data),
+			features: getCoverageFeatures(sequenceCoverage, setupFeatures),
+		})
+	}
+
+	// Only preserve the closest near misses achieved by call sequences.
+	retainClosestNearMisses(candidates, setupCoverage.nearMisses)
+
+	// Select the call sequences to keep.
+	selected := selectMinimalCoverageSet(candidates)
+	result := &MinimizationResult{
+		TotalSequences:     len(c.callSequenceFiles.files),
+		KeptSequences:      len(selected),
+		RedundantSequences: len(candidates) - len(selected),
+		InvalidSequences:   len(invalidFileNames),
+		ArchiveDirectory:   archiveDirectory,
+	}
+
+	// Move every other call sequence to the archive directory.
+	if result.RedundantSequences+result.InvalidSequences > 0 {
+		err = utils.MakeDirectory(archiveDirectory)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, sequenceFileData := range append([]*corpusFile[calls.CallSequence]{}, c.callSequenceFiles.files...) {
+		if _, ok := selected[sequenceFileData.fileName]; ok {
+			continue
+		}
+		archivePath, err := getArchivePath(archiveDirectory, sequenceFileData.fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive corpus item '%v': %v", sequenceFileData.fileName, err)
+		}
+		err = utils.MoveFile(filepath.Join(c.callSequenceFiles.path, sequenceFileData.fileName), archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive corpus item '%v': %v", sequenceFileData.fileName, err)
+		}
+		c.callSequenceFiles.removeFile(sequenceFileData.fileName)
+	}
+	return result, nil
+}
+
+// getArchivePath obtains the path within the archive directory to move the call sequence file with the given name to.
+// If a file with the same name was already archived (e.g. by a prior minimization), a numeric suffix is added to the
+// name, so the archived file is not overwritten.
+// Returns the path to archive the file to, or an error if one occurred.
+func getArchivePath(archiveDirectory string, fileName string) (string, error) {
+	archivePath := filepath.Join(archiveDirectory, fileName)
+	for i := 1; ; i++ {
+		_, err := os.Lstat(archivePath)
+		if os.IsNotExist(err) {
+			return archivePath, nil
+		} else if err != nil {
+			return "", err
+		}
+		archivePath = filepath.Join(archiveDirectory, fmt.Sprintf("%v_%d%v", utils.GetFileNameWithoutExtension(fileName), i, filepath.Ext(fileName)))
+	}
+}
@@ -0,0 +1,240 @@
+package corpus
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PowerSchedule describes a strategy used to assign energy to corpus call sequences. The energy of a call sequence
+// determines how likely it is to be chosen as a mutation target.
+type PowerSchedule string
+
+const (
+	// PowerScheduleNone assigns each call sequence a static energy when it is added to the corpus. Call sequences added
+	// later in a fuzzing campaign are assigned a higher energy.
+	PowerScheduleNone PowerSchedule = "none"
+
+	// PowerScheduleRare extends PowerScheduleNone, multiplying the energy of call sequences which cover program counters
+	// rarely hit by the fuzzer.
+	PowerScheduleRare PowerSchedule = "rare"
+
+	// PowerScheduleFast extends PowerScheduleRare, dividing the energy of call sequences which were chosen as mutation
+	// targets many times without the mutated call sequences achieving new coverage.
+	PowerScheduleFast PowerSchedule = "fast"
+)
+
+const (
+	// powerScheduleMaxFactor describes the maximum factor by which a power schedule multiplies or divides the energy
+	// of a call sequence.
+	powerScheduleMaxFactor = 64
+
+	// powerScheduleDecayInterval describes the amount of times a call sequence can be chosen as a mutation target
+	// without achieving new coverage before PowerScheduleFast further divides its energy.
+	powerScheduleDecayInterval = 32
+
+	// powerScheduleUpdateInterval describes the amount of mutation targets chosen between each recomputation of the
+	// energy of every call sequence.
+	powerScheduleUpdateInterval = 1_000
+)
+
+// IsValid indicates whether the PowerSchedule is a supported power schedule.
+func (s PowerSchedule) IsValid() bool {
+	return s == PowerScheduleNone || s == PowerScheduleRare || s == PowerScheduleFast
+}
+
+// coveredPC describes a program counter within the code with a given code hash.
+type coveredPC struct {
+	// codeHash describes the lookup hash of the code the program counter belongs to.
+	codeHash common.Hash
+	// pc describes the program counter.
+	pc int
+}
+
+// mutationTarget describes a corpus call sequence which may be chosen for mutation, alongside the statistics used by a
+// PowerSchedule to compute its energy.
+type mutationTarget struct {
+	// fileName describes the name of the corpus file the call sequence is stored in, which uniquely identifies it.
+	fileName string
+
+	// sequence describes the call sequence to mutate.
+	sequence calls.CallSequence
+
+	// baseWeight describes the static energy the call sequence was assigned when it was added to the corpus.
+	baseWeight *big.Int
+
+	// coveredPCs describes the program counters covered by the call sequence when it was added to the corpus.
+	coveredPCs []coveredPC
+
+	// selections describes the amount of times the call sequence was chosen as a mutation target.
+	selections uint64
+
+	// selectionsWithoutNewCoverage describes the amount of times the call sequence was chosen as a mutation target
+	// since a call sequence mutated from it last achieved new coverage.
+	selectionsWithoutNewCoverage uint64
+
+	// energy describes the energy last computed for the call sequence.
+	energy *big.Int
+}
+
+// powerScheduler computes the energy of corpus call sequences according to a PowerSchedule. It tracks how often each
+// program counter is hit by the fuzzer, and how often each call sequence is chosen as a mutation target.
+type powerScheduler struct {
+	// schedule describes the PowerSchedule used to compute energy.
+	schedule PowerSchedule
+
+	// pcHitCounts describes the amount of calls which hit each program counter.
+	pcHitCounts map[coveredPC]uint64
+
+	// callsRecorded describes the amount of calls whose coverage was recorded in pcHitCounts.
+	callsRecorded uint64
+
+	// targets describes the mutation targets known to the scheduler, by file name.
+	targets map[string]*mutationTarget
+
+	// selectionsSinceUpdate describes the amount of mutation targets chosen since energy was last recomputed.
+	selectionsSinceUpdate uint64
+
+	// lock provides thread synchronization to prevent concurrent access errors.
+	lock sync.Mutex
+
+	// logger describes the logger used to report the energy of each call sequence.
+	logger *logging.Logger
+}
+
+// newPowerScheduler creates a powerScheduler which computes energy according to the provided PowerSchedule.
+func newPowerScheduler(schedule PowerSchedule, logger *logging.Logger) *powerScheduler {
+	return &powerScheduler{
+		schedule:    schedule,
+		pcHitCounts: make(map[coveredPC]uint64),
+		targets:     make(map[string]*mutationTarget),
+		logger:      logger,
+	}
+}
+
+// tracksCoverage indicates whether the schedule requires coverage to be recorded for every call.
+func (s *powerScheduler) tracksCoverage() bool {
+	return s.schedule == PowerScheduleRare || s.schedule == PowerScheduleFast
+}
+
+// recordCoverage records a call which achieved the provided coverage.
+// Returns the program counters hit, or nil if the schedule does not track coverage.
+func (s *powerScheduler) recordCoverage(coverageMaps *coverage.CoverageMaps) []coveredPC {
+	if !s.tracksCoverage() || coverageMaps == nil {
+		return nil
+	}
+	coveredPCsByCodeHash := coverageMaps.CoveredPCs()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	pcs := make([]coveredPC, 0)
+	for codeHash, codePCs := range coveredPCsByCodeHash {
+		for _, pc := range codePCs {
+			key := coveredPC{codeHash: codeHash, pc: pc}
+			s.pcHitCounts[key]++
+			pcs = append(pcs, key)
+		}
+	}
+	s.callsRecorded++
+	return pcs
+}
+
+// addTarget adds a mutation target to the scheduler, computing its initial energy.
+func (s *powerScheduler) addTarget(target *mutationTarget) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	target.energy = s.computeEnergy(target)
+	s.targets[target.fileName] = target
+	s.logEnergy(target)
+}
+
+// targetSelected records that the provided mutation target was chosen for mutation.
+// Returns a boolean indicating whether the energy of every mutation target should be recomputed.
+func (s *powerScheduler) targetSelected(target *mutationTarget) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	target.selections++
+	target.selectionsWithoutNewCoverage++
+	s.selectionsSinceUpdate++
+	return s.schedule != PowerScheduleNone && s.selectionsSinceUpdate >= powerScheduleUpdateInterval
+}
+
+// targetsAchievedNewCoverage records that a call sequence mutated from the mutation targets with the provided file
+// names achieved new coverage.
+func (s *powerScheduler) targetsAchievedNewCoverage(fileNames []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, fileName := range fileNames {
+		if target, ok := s.targets[fileName]; ok {
+			target.selectionsWithoutNewCoverage = 0
+		}
+	}
+}
+
+// updateEnergy recomputes the energy of the provided mutation target and returns it. This is intended to be used with
+// randomutils.WeightedRandomChooser.UpdateWeights.
+func (s *powerScheduler) updateEnergy(target *mutationTarget) *big.Int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.selectionsSinceUpdate = 0
+	target.energy = s.computeEnergy(target)
+	s.logEnergy(target)
+	return target.energy
+}
+
+// logEnergy reports the energy of the provided mutation target in the debug logs.
+func (s *powerScheduler) logEnergy(target *mutationTarget) {
+	s.logger.Debug("Corpus item ", colors.Bold, target.fileName, colors.Reset, " has energy ", target.energy.String(),
+		" (schedule: ", string(s.schedule), ", selections: ", target.selections, ", selections without new coverage: ", target.selectionsWithoutNewCoverage, ")")
+}
+
+// computeEnergy computes the energy of the provided mutation target according to the schedule. The caller is
+// responsible for acquiring the lock.
+func (s *powerScheduler) computeEnergy(target *mutationTarget) *big.Int {
+	energy := new(big.Int).Set(target.baseWeight)
+	if s.schedule == PowerScheduleNone {
+		return energy
+	}
+
+	// Multiply the energy by how rarely the rarest program counter the call sequence covers is hit, relative to the
+	// amount of calls recorded.
+	if rarestHitCount := s.rarestHitCount(target); rarestHitCount > 0 {
+		rarityFactor := utils.Min(utils.Max(s.callsRecorded/rarestHitCount, 1), powerScheduleMaxFactor)
+		energy.Mul(energy, new(big.Int).SetUint64(rarityFactor))
+	}
+
+	// Divide the energy by how many times the call sequence was mutated without achieving new coverage.
+	if s.schedule == PowerScheduleFast {
+		decayFactor := utils.Min(1+target.selectionsWithoutNewCoverage/powerScheduleDecayInterval, powerScheduleMaxFactor)
+		energy.Div(energy, new(big.Int).SetUint64(decayFactor))
+	}
+
+	// Ensure the energy is non-zero, so every call sequence can still be chosen.
+	if energy.Sign() <= 0 {
+		energy.SetUint64(1)
+	}
+	return energy
+}
+
+// rarestHitCount obtains the hit count of the program counter covered by the provided mutation target which was hit
+// the fewest times, or zero if it covers no program counters. The caller is responsible for acquiring the lock.
+func (s *powerScheduler) rarestHitCount(target *mutationTarget) uint64 {
+	rarestHitCount := uint64(0)
+	for _, pc := range target.coveredPCs {
+		if hitCount := s.pcHitCounts[pc]; hitCount > 0 && (rarestHitCount == 0 || hitCount < rarestHitCount) {
+			rarestHitCount = hitCount
+		}
+	}
+	return rarestHitCount
+}
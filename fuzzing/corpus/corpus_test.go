@@ -2,12 +2,19 @@ package corpus
 
 import (
 	"encoding/json"
+	"github.com/crytic/medusa/chain"
 	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/crytic/medusa/logging"
 	"github.com/crytic/medusa/utils/testutils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
 	"math/big"
 	"math/rand"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -15,7 +22,7 @@ import (
 // getMockSimpleCorpus creates a mock corpus with numEntries callSequencesByFilePath for testing
 func getMockSimpleCorpus(minSequences int, maxSequences, minBlocks int, maxBlocks int) (*Corpus, error) {
 	// Create a new corpus
-	corpus, err := NewCorpus("corpus")
+	corpus, err := NewCorpus(CorpusConfig{Directory: "corpus", PowerSchedule: PowerScheduleNone, CoverageMode: coverage.CoverageModePC, StorageWriteBucketing: coverage.StorageWriteBucketingNone})
 	if err != nil {
 		return nil, err
 	}
@@ -23,7 +30,7 @@ func getMockSimpleCorpus(minSequences int, maxSequences, minBlocks int, maxBlock
 	// Add the requested number of entries.
 	numSequences := minSequences + (rand.Int() % (maxSequences - minSequences))
 	for i := 0; i < numSequences; i++ {
-		err := corpus.addCallSequence(corpus.callSequenceFiles, getMockCallSequence(minBlocks+(rand.Int()%(maxBlocks-minBlocks))), true, nil, false)
+		err := corpus.addCallSequence(corpus.callSequenceFiles, getMockCallSequence(minBlocks+(rand.Int()%(maxBlocks-minBlocks))), true, nil, nil, false)
 		if err != nil {
 			return nil, err
 		}
@@ -105,11 +112,11 @@ func TestCorpusReadWrite(t *testing.T) {
 		assert.EqualValues(t, len(corpus.callSequenceFiles.files), len(matches))
 
 		// Wipe corpus clean so that you can now read it in from disk
-		corpus, err = NewCorpus("corpus")
+		corpus, err = NewCorpus(CorpusConfig{Directory: "corpus", PowerSchedule: PowerScheduleNone, CoverageMode: coverage.CoverageModePC, StorageWriteBucketing: coverage.StorageWriteBucketingNone})
 		assert.NoError(t, err)
 
 		// Create a new corpus object and read our previously read artifacts.
-		corpus, err = NewCorpus(corpus.storageDirectory)
+		corpus, err = NewCorpus(CorpusConfig{Directory: corpus.storageDirectory, PowerSchedule: PowerScheduleNone, CoverageMode: coverage.CoverageModePC, StorageWriteBucketing: coverage.StorageWriteBucketingNone})
 		assert.NoError(t, err)
 	})
 }
@@ -145,3 +152,306 @@ func TestCorpusCallSequenceMarshaling(t *testing.T) {
 		assert.Empty(t, corpus.callSequenceFiles.files)
 	})
 }
+
+// TestPowerScheduleEnergy ensures that each PowerSchedule assigns energy to mutation targets as expected, given the
+// program counters they cover and how often they were mutated without achieving new coverage.
+func TestPowerScheduleEnergy(t *testing.T) {
+	// Create mutation targets covering a rarely hit and a commonly hit program counter respectively.
+	rarePC := coveredPC{codeHash: common.HexToHash("0x1"), pc: 1}
+	commonPC := coveredPC{codeHash: common.HexToHash("0x1"), pc: 2}
+	newTargets := func() (*mutationTarget, *mutationTarget) {
+		rareTarget := &mutationTarget{fileName: "rare.json", baseWeight: big.NewInt(10), coveredPCs: []coveredPC{commonPC, rarePC}}
+		commonTarget := &mutationTarget{fileName: "common.json", baseWeight: big.NewInt(10), coveredPCs: []coveredPC{commonPC}}
+		return rareTarget, commonTarget
+	}
+	newScheduler := func(schedule PowerSchedule) *powerScheduler {
+		scheduler := newPowerScheduler(schedule, logging.GlobalLogger)
+		scheduler.pcHitCounts[rarePC] = 1
+		scheduler.pcHitCounts[commonPC] = 100
+		scheduler.callsRecorded = 100
+		return scheduler
+	}
+
+	// Without a power schedule, energy should remain the base weight.
+	scheduler := newScheduler(PowerScheduleNone)
+	rareTarget, commonTarget := newTargets()
+	scheduler.addTarget(rareTarget)
+	scheduler.addTarget(commonTarget)
+	assert.EqualValues(t, 10, rareTarget.energy.Int64())
+	assert.EqualValues(t, 10, commonTarget.energy.Int64())
+
+	// With the rare schedule, the target covering the rarely hit program counter should receive the most energy.
+	scheduler = newScheduler(PowerScheduleRare)
+	rareTarget, commonTarget = newTargets()
+	scheduler.addTarget(rareTarget)
+	scheduler.addTarget(commonTarget)
+	assert.EqualValues(t, 10*powerScheduleMaxFactor, rareTarget.energy.Int64())
+	assert.EqualValues(t, 10, commonTarget.energy.Int64())
+
+	// With the fast schedule, energy should decay as the target is mutated without achieving new coverage, and be
+	// restored once it does.
+	scheduler = newScheduler(PowerScheduleFast)
+	rareTarget, _ = newTargets()
+	scheduler.addTarget(rareTarget)
+	initialEnergy := rareTarget.energy.Int64()
+	for i := 0; i < powerScheduleDecayInterval; i++ {
+		scheduler.targetSelected(rareTarget)
+	}
+	assert.EqualValues(t, initialEnergy/2, scheduler.updateEnergy(rareTarget).Int64())
+	scheduler.targetsAchievedNewCoverage([]string{rareTarget.fileName})
+	assert.EqualValues(t, initialEnergy, scheduler.updateEnergy(rareTarget).Int64())
+}
+
+// TestSelectMinimalCoverageSet ensures that corpus minimization selects a set of call sequences which preserves every
+// coverage feature, preferring shorter call sequences.
+func TestSelectMinimalCoverageSet(t *testing.T) {
+	// Create features and candidates achieving them.
+	feature := func(pc int) coverageFeature {
+		return coverageFeature{kind: coverageFeatureKindPC, codeHash: common.HexToHash("0x1"), pc: pc}
+	}
+	candidates := []*minimizationCandidate{
+		// A long call sequence achieving every feature except a reverted one.
+		{fileName: "long.json", length: 10, features: []coverageFeature{feature(1), feature(2), feature(3)}},
+		// Short call sequences which together achieve the same features.
+		{fileName: "short-a.json", length: 2, features: []coverageFeature{feature(1), feature(2)}},
+		{fileName: "short-b.json", length: 1, features: []coverageFeature{feature(3)}},
+		// A call sequence whose features are all achieved by others.
+		{fileName: "redundant.json", length: 3, features: []coverageFeature{feature(2)}},
+		// A call sequence which is the only one to achieve a feature.
+		{fileName: "unique.json", length: 20, features: []coverageFeature{{kind: coverageFeatureKindPC, codeHash: common.HexToHash("0x1"), pc: 1, reverted: true}}},
+		// A call sequence achieving no features.
+		{fileName: "empty.json", length: 1, features: []coverageFeature{}},
+	}
+
+	// Select our minimal set and check that the shorter call sequences were kept over the longer one.
+	selected := selectMinimalCoverageSet(candidates)
+	assert.Len(t, selected, 3)
+	for _, fileName := range []string{"short-a.json", "short-b.json", "unique.json"} {
+		assert.Contains(t, selected, fileName)
+	}
+
+	// Check that every feature is preserved.
+	for _, candidate := range candidates {
+		for _, candidateFeature := range candidate.features {
+			preserved := false
+			for _, selectedCandidate := range candidates {
+				if _, ok := selected[selectedCandidate.fileName]; ok && slices.Contains(selectedCandidate.features, candidateFeature) {
+					preserved = true
+				}
+			}
+			assert.True(t, preserved)
+		}
+	}
+}
+
+// TestGetArchivePath ensures that corpus minimization archives call sequence files under their own name, unless a file
+// with that name was already archived, in which case a unique name is chosen rather than overwriting it.
+func TestGetArchivePath(t *testing.T) {
+	archiveDirectory := t.TempDir()
+
+	// Check the file's own name is used when no file with that name was archived.
+	archivePath, err := getArchivePath(archiveDirectory, "sequence.json")
+	assert.NoError(t, err)
+	assert.EqualValues(t, filepath.Join(archiveDirectory, "sequence.json"), archivePath)
+
+	// Archive files under that name and the first alternative name, and check the next unused name is chosen.
+	for _, fileName := range []string{"sequence.json", "sequence_1.json"} {
+		err = os.WriteFile(filepath.Join(archiveDirectory, fileName), []byte("[]"), 0644)
+		assert.NoError(t, err)
+	}
+	archivePath, err = getArchivePath(archiveDirectory, "sequence.json")
+	assert.NoError(t, err)
+	assert.EqualValues(t, filepath.Join(archiveDirectory, "sequence_2.json"), archivePath)
+}
+
+// TestGetCoverageFeaturesPCs ensures that corpus minimization treats every program counter executed, successfully or
+// in a reverted call, as a coverage feature, excluding those provided.
+func TestGetCoverageFeaturesPCs(t *testing.T) {
+	codeAddress := common.HexToAddress("0x1234")
+	codeHash := common.HexToHash("0x1")
+
+	// Record program counters executed successfully, and one executed in a reverted call.
+	minimizationCoverage := newMinimizationCoverage()
+	for _, pc := range []uint64{1, 2} {
+		_, err := minimizationCoverage.coverageMaps.UpdateAt(codeAddress, codeHash, 10, pc)
+		assert.NoError(t, err)
+	}
+	revertedCoverageMaps := coverage.NewCoverageMaps()
+	_, err := revertedCoverageMaps.UpdateAt(codeAddress, codeHash, 10, 3)
+	assert.NoError(t, err)
+	_, err = revertedCoverageMaps.RevertAll()
+	assert.NoError(t, err)
+	_, _, err = minimizationCoverage.coverageMaps.Update(revertedCoverageMaps)
+	assert.NoError(t, err)
+
+	// Check every program counter is a feature, unless excluded.
+	pcFeature := func(pc int, reverted bool) coverageFeature {
+		return coverageFeature{kind: coverageFeatureKindPC, codeHash: codeHash, pc: pc, reverted: reverted}
+	}
+	features := getCoverageFeatures(minimizationCoverage, nil)
+	assert.ElementsMatch(t, []coverageFeature{pcFeature(1, false), pcFeature(2, false), pcFeature(3, true)}, features)
+	features = getCoverageFeatures(minimizationCoverage, map[coverageFeature]struct{}{pcFeature(1, false): {}})
+	assert.ElementsMatch(t, []coverageFeature{pcFeature(2, false), pcFeature(3, true)}, features)
+}
+
+// TestGetCoverageFeaturesInterestingIds ensures that corpus minimization treats every identifier flagged as
+// interesting by a harness as a coverage feature, so call sequences flagging an identifier no other does are kept.
+func TestGetCoverageFeaturesInterestingIds(t *testing.T) {
+	// Record identifiers flagged as interesting.
+	minimizationCoverage := newMinimizationCoverage()
+	minimizationCoverage.interestingIds["7"] = struct{}{}
+	minimizationCoverage.interestingIds["8"] = struct{}{}
+
+	// Check every identifier is a feature, unless excluded.
+	idFeature := func(id string) coverageFeature {
+		return coverageFeature{kind: coverageFeatureKindInterestingId, interestingId: id}
+	}
+	features := getCoverageFeatures(minimizationCoverage, nil)
+	assert.ElementsMatch(t, []coverageFeature{idFeature("7"), idFeature("8")}, features)
+	features = getCoverageFeatures(minimizationCoverage, map[coverageFeature]struct{}{idFeature("7"): {}})
+	assert.ElementsMatch(t, []coverageFeature{idFeature("8")}, features)
+}
+
+// TestGetCoverageFeaturesNearMisses ensures only the closest near misses achieved by candidates are preserved as
+// coverage features during corpus minimization, unless setup already achieved them.
+func TestGetCoverageFeaturesNearMisses(t *testing.T) {
+	siteA := valuegeneration.ComparisonSite{CodeHash: common.Hash{1}, PC: 10}
+	siteB := valuegeneration.ComparisonSite{CodeHash: common.Hash{1}, PC: 20}
+	nearMissFeature := func(site valuegeneration.ComparisonSite, distance uint) coverageFeature {
+		return coverageFeature{kind: coverageFeatureKindNearMiss, codeHash: site.CodeHash, pc: int(site.PC), nearMissDistance: distance}
+	}
+
+	// Record near misses for two candidates, where each achieves the closest distance at one site.
+	candidateCoverage := func(distanceA uint, distanceB uint) *minimizationCandidate {
+		minimizationCoverage := newMinimizationCoverage()
+		minimizationCoverage.nearMisses[siteA] = distanceA
+		minimizationCoverage.nearMisses[siteB] = distanceB
+		return &minimizationCandidate{features: getCoverageFeatures(minimizationCoverage, nil)}
+	}
+	candidateA := candidateCoverage(1, 50)
+	candidateB := candidateCoverage(8, 3)
+	assert.ElementsMatch(t, []coverageFeature{nearMissFeature(siteA, 1), nearMissFeature(siteB, 50)}, candidateA.features)
+
+	// Check each candidate only retains the sites it achieved the closest distance at.
+	retainClosestNearMisses([]*minimizationCandidate{candidateA, candidateB}, nil)
+	assert.ElementsMatch(t, []coverageFeature{nearMissFeature(siteA, 1)}, candidateA.features)
+	assert.ElementsMatch(t, []coverageFeature{nearMissFeature(siteB, 3)}, candidateB.features)
+
+	// Check near misses achieved at least as closely by setup are not retained.
+	candidateA = candidateCoverage(1, 50)
+	candidateB = candidateCoverage(8, 3)
+	retainClosestNearMisses([]*minimizationCandidate{candidateA, candidateB}, map[valuegeneration.ComparisonSite]uint{siteA: 1, siteB: 4})
+	assert.Empty(t, candidateA.features)
+	assert.ElementsMatch(t, []coverageFeature{nearMissFeature(siteB, 3)}, candidateB.features)
+}
+
+// TestGetCoverageFeaturesStorageWrites ensures that corpus minimization treats every storage write recorded as a
+// coverage feature, so call sequences writing a value no other does are kept.
+func TestGetCoverageFeaturesStorageWrites(t *testing.T) {
+	address := common.HexToAddress("0x1234")
+	slot := common.HexToHash("0x1")
+
+	// Record writes of two distinct values, and a repeated write, to a storage slot.
+	minimizationCoverage := newMinimizationCoverage()
+	for _, value := range []uint64{5, 6, 5} {
+		minimizationCoverage.coverageMaps.UpdateStorageWriteAt(address, slot, uint256.NewInt(value), coverage.StorageWriteBucketingExact)
+	}
+
+	// Check every distinct storage write is a feature, unless excluded.
+	storageWriteFeature := func(value uint64) coverageFeature {
+		return coverageFeature{
+			kind:         coverageFeatureKindStorageWrite,
+			storageWrite: coverage.StorageWrite{Address: address, Slot: slot, Bucket: uint256.NewInt(value).Bytes32()},
+		}
+	}
+	features := getCoverageFeatures(minimizationCoverage, nil)
+	assert.ElementsMatch(t, []coverageFeature{storageWriteFeature(5), storageWriteFeature(6)}, features)
+	features = getCoverageFeatures(minimizationCoverage, map[coverageFeature]struct{}{storageWriteFeature(5): {}})
+	assert.ElementsMatch(t, []coverageFeature{storageWriteFeature(6)}, features)
+}
+
+// TestGetCoverageFeaturesEdges ensures that corpus minimization treats every hit count bucket each edge was taken
+// within, successfully or in a reverted call, as a coverage feature.
+func TestGetCoverageFeaturesEdges(t *testing.T) {
+	codeAddress := common.HexToAddress("0x1234")
+	codeHash := common.HexToHash("0x1")
+
+	// Record an edge taken once, and another taken twice, successfully.
+	minimizationCoverage := newMinimizationCoverage()
+	minimizationCoverage.coverageMaps.UpdateEdgeAt(codeAddress, codeHash, 0, 5)
+	minimizationCoverage.coverageMaps.UpdateEdgeAt(codeAddress, codeHash, 5, 9)
+	minimizationCoverage.coverageMaps.UpdateEdgeAt(codeAddress, codeHash, 5, 9)
+
+	// Record an edge taken once in a reverted call.
+	revertedCoverageMaps := coverage.NewCoverageMaps()
+	revertedCoverageMaps.UpdateEdgeAt(codeAddress, codeHash, 9, 12)
+	_, err := revertedCoverageMaps.RevertAll()
+	assert.NoError(t, err)
+	_, _, err = minimizationCoverage.coverageMaps.Update(revertedCoverageMaps)
+	assert.NoError(t, err)
+
+	// Check every edge is a feature with the bucket of the amount of times it was taken, unless excluded.
+	edgeFeature := func(previousPC uint64, pc uint64, bucket uint8, reverted bool) coverageFeature {
+		return coverageFeature{kind: coverageFeatureKindEdge, codeHash: codeHash, edgeKey: previousPC<<32 | pc, edgeBucket: bucket, reverted: reverted}
+	}
+	features := getCoverageFeatures(minimizationCoverage, nil)
+	assert.ElementsMatch(t, []coverageFeature{edgeFeature(0, 5, 1, false), edgeFeature(5, 9, 2, false), edgeFeature(9, 12, 1, true)}, features)
+	features = getCoverageFeatures(minimizationCoverage, map[coverageFeature]struct{}{edgeFeature(5, 9, 2, false): {}})
+	assert.ElementsMatch(t, []coverageFeature{edgeFeature(0, 5, 1, false), edgeFeature(9, 12, 1, true)}, features)
+}
+
+// TestUpdateInterestingIds ensures identifiers flagged as interesting by harnesses are only reported as new the first
+// time they are seen, so only the call sequences first flagging them are weighted higher.
+func TestUpdateInterestingIds(t *testing.T) {
+	corpus, err := NewCorpus(CorpusConfig{Directory: "", PowerSchedule: PowerScheduleNone, CoverageMode: coverage.CoverageModePC, StorageWriteBucketing: coverage.StorageWriteBucketingNone})
+	assert.NoError(t, err)
+
+	// Check a previously unseen interesting identifier is recorded.
+	hints := &chain.FuzzerHintResults{InterestingIds: []*big.Int{big.NewInt(1)}}
+	assert.True(t, corpus.updateInterestingIds(hints))
+	assert.Equal(t, 1, corpus.InterestingIdCount())
+
+	// Check a repeated interesting identifier is not reported as new.
+	assert.False(t, corpus.updateInterestingIds(hints))
+	assert.False(t, corpus.updateInterestingIds(nil))
+	assert.Equal(t, 1, corpus.InterestingIdCount())
+
+	// Check a new identifier alongside a repeated one is reported as new.
+	hints = &chain.FuzzerHintResults{InterestingIds: []*big.Int{big.NewInt(1), big.NewInt(2)}}
+	assert.True(t, corpus.updateInterestingIds(hints))
+	assert.Equal(t, 2, corpus.InterestingIdCount())
+}
+
+// TestUpdateFeedback ensures call sequences providing previously unseen interesting identifiers or closer comparisons
+// are weighted higher when chosen as mutation targets, while repeated feedback does not affect their weight.
+func TestUpdateFeedback(t *testing.T) {
+	corpus, err := NewCorpus(CorpusConfig{Directory: "", PowerSchedule: PowerScheduleNone, CoverageMode: coverage.CoverageModePC, StorageWriteBucketing: coverage.StorageWriteBucketingNone, ComparisonFeedbackEnabled: true})
+	assert.NoError(t, err)
+	hints := &chain.FuzzerHintResults{InterestingIds: []*big.Int{big.NewInt(1)}}
+	site := valuegeneration.ComparisonSite{CodeHash: common.Hash{1}, PC: 10}
+	comparisonResults := func(distance uint) *valuegeneration.ComparisonResults {
+		return &valuegeneration.ComparisonResults{NearMisses: map[valuegeneration.ComparisonSite]uint{site: distance}}
+	}
+
+	// Check a previously unseen interesting identifier multiplies the weight, treating a nil weight as one.
+	weight, updated := corpus.updateFeedback(nil, hints, nil)
+	assert.True(t, updated)
+	assert.EqualValues(t, interestingSequenceWeightMultiplier, weight.Int64())
+	assert.Equal(t, 1, corpus.InterestingIdCount())
+
+	// Check a repeated interesting identifier does not affect the weight.
+	weight, updated = corpus.updateFeedback(big.NewInt(3), hints, nil)
+	assert.False(t, updated)
+	assert.EqualValues(t, 3, weight.Int64())
+
+	// Check a closer comparison multiplies the weight, alongside a new interesting identifier.
+	hints = &chain.FuzzerHintResults{InterestingIds: []*big.Int{big.NewInt(2)}}
+	weight, updated = corpus.updateFeedback(big.NewInt(3), hints, comparisonResults(5))
+	assert.True(t, updated)
+	assert.EqualValues(t, 3*interestingSequenceWeightMultiplier*nearMissSequenceWeightMultiplier, weight.Int64())
+
+	// Check a comparison which is not closer does not affect the weight.
+	weight, updated = corpus.updateFeedback(nil, nil, comparisonResults(5))
+	assert.False(t, updated)
+	assert.Nil(t, weight)
+}
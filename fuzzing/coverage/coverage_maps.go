@@ -1,6 +1,9 @@
 package coverage
 
 import (
+	"bytes"
+
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
 	"sync"
@@ -9,6 +12,7 @@ import (
 	"github.com/crytic/medusa/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
 )
 
 // CoverageMaps represents a data structure used to identify instruction execution coverage of various smart contracts
@@ -31,6 +35,10 @@ type CoverageMaps struct {
 	// cachedCodeAddress and matches the cachedCodeHash, then this map is used to avoid an expensive lookup into maps.
 	cachedMap *ContractCoverageMap
 
+	// storageWrites represents the set of storage writes recorded, if storage write coverage is enabled. Storage
+	// writes are only recorded for call frames which did not revert.
+	storageWrites map[StorageWrite]struct{}
+
 	// updateLock is a lock to offer concurrent thread safety for map accesses.
 	updateLock sync.Mutex
 }
@@ -48,6 +56,7 @@ func (cm *CoverageMaps) Reset() {
 	cm.cachedCodeAddress = common.Address{}
 	cm.cachedCodeHash = common.Hash{}
 	cm.cachedMap = nil
+	cm.storageWrites = make(map[StorageWrite]struct{})
 }
 
 // Equal checks whether two coverage maps are the same. Equality is determined if the keys and values are all the same.
@@ -72,7 +81,9 @@ func (cm *CoverageMaps) Equal(b *CoverageMaps) bool {
 			}
 		}
 	}
-	return true
+
+	// Verify the equality of the storage writes.
+	return maps.Equal(cm.storageWrites, b.storageWrites)
 }
 
 // getContractCoverageMapHash obtain the hash used to look up a given contract's ContractCoverageMap.
@@ -123,7 +134,8 @@ func (cm *CoverageMaps) GetContractCoverageMap(bytecode []byte, init bool) (*Con
 	}
 }
 
-// Update updates the current coverage maps with the provided ones.
+// Update updates the current coverage maps with the provided ones. Storage writes not previously recorded are
+// considered successful coverage.
 // Returns two booleans indicating whether successful or reverted coverage changed, or an error if one occurred.
 func (cm *CoverageMaps) Update(coverageMaps *CoverageMaps) (bool, bool, error) {
 	// If our maps provided are nil, do nothing
@@ -166,6 +178,14 @@ func (cm *CoverageMaps) Update(coverageMaps *CoverageMaps) (bool, bool, error) {
 		}
 	}
 
+	// Merge any storage writes provided.
+	for storageWrite := range coverageMaps.storageWrites {
+		if _, exists := cm.storageWrites[storageWrite]; !exists {
+			cm.storageWrites[storageWrite] = struct{}{}
+			successCoverageChanged = true
+		}
+	}
+
 	// Return our results
 	return successCoverageChanged, revertedCoverageChanged, nil
 }
@@ -177,12 +197,29 @@ func (cm *CoverageMaps) UpdateAt(codeAddress common.Address, codeLookupHash comm
 		return false, nil
 	}
 
-	// Define variables used to update coverage maps and track changes.
+	// Obtain the coverage map for this code, set our coverage in it and return our change state
+	coverageMap, addedNewMap := cm.getOrCreateContractCoverageMap(codeAddress, codeLookupHash)
+	changedInMap, err := coverageMap.updateCoveredAt(codeSize, pc)
+
+	return addedNewMap || changedInMap, err
+}
+
+// UpdateEdgeAt increments the hit count of the edge between the JUMPDEST instructions at the provided program counters
+// within code coverage data. The previous program counter is zero if no JUMPDEST was executed prior in the call frame.
+// Hit counts are converted into buckets by classifyEdgeHits once a transaction's coverage is finalized.
+func (cm *CoverageMaps) UpdateEdgeAt(codeAddress common.Address, codeLookupHash common.Hash, previousPC uint64, pc uint64) {
+	coverageMap, _ := cm.getOrCreateContractCoverageMap(codeAddress, codeLookupHash)
+	coverageMap.successfulCoverage.updateEdgeAt(previousPC, pc)
+}
+
+// getOrCreateContractCoverageMap obtains the ContractCoverageMap for the code with the given lookup hash at the given
+// address, creating it if it does not exist.
+// Returns the ContractCoverageMap, and a boolean indicating whether it was created.
+func (cm *CoverageMaps) getOrCreateContractCoverageMap(codeAddress common.Address, codeLookupHash common.Hash) (*ContractCoverageMap, bool) {
+	// Define variables used to obtain the coverage map and track changes.
 	var (
-		addedNewMap  bool
-		changedInMap bool
-		coverageMap  *ContractCoverageMap
-		err          error
+		addedNewMap bool
+		coverageMap *ContractCoverageMap
 	)
 
 	// Try to obtain a coverage map from our cache
@@ -210,15 +247,54 @@ func (cm *CoverageMaps) UpdateAt(codeAddress common.Address, codeLookupHash comm
 		cm.cachedCodeHash = codeLookupHash
 		cm.cachedCodeAddress = codeAddress
 	}
+	return coverageMap, addedNewMap
+}
 
-	// Set our coverage in the map and return our change state
-	changedInMap, err = coverageMap.updateCoveredAt(codeSize, pc)
+// classifyEdgeHits converts the edge hit counts recorded in the coverage maps into hit count buckets, so that they
+// may be merged into other coverage maps. This is called once a transaction's coverage is finalized.
+func (cm *CoverageMaps) classifyEdgeHits() {
+	for _, mapsByAddress := range cm.maps {
+		for _, contractCoverageMap := range mapsByAddress {
+			contractCoverageMap.successfulCoverage.classifyEdgeHits()
+			contractCoverageMap.revertedCoverage.classifyEdgeHits()
+		}
+	}
+}
 
-	return addedNewMap || changedInMap, err
+// UpdateStorageWriteAt records a write of the provided value to a storage slot of the contract at the provided
+// address, grouping the value according to the provided StorageWriteBucketing.
+// Returns a boolean indicating whether the storage write was not previously recorded.
+func (cm *CoverageMaps) UpdateStorageWriteAt(address common.Address, slot common.Hash, value *uint256.Int, bucketing StorageWriteBucketing) bool {
+	storageWrite := StorageWrite{Address: address, Slot: slot, Bucket: bucketing.bucket(value)}
+	if _, exists := cm.storageWrites[storageWrite]; exists {
+		return false
+	}
+	cm.storageWrites[storageWrite] = struct{}{}
+	return true
+}
+
+// StorageWrites returns the storage writes recorded, ordered by address, slot, and bucket.
+func (cm *CoverageMaps) StorageWrites() []StorageWrite {
+	// Acquire our thread lock and defer our unlocking for when we exit this method
+	cm.updateLock.Lock()
+	defer cm.updateLock.Unlock()
+
+	// Sort the storage writes so they are returned deterministically.
+	storageWrites := maps.Keys(cm.storageWrites)
+	slices.SortFunc(storageWrites, func(a StorageWrite, b StorageWrite) int {
+		if c := bytes.Compare(a.Address[:], b.Address[:]); c != 0 {
+			return c
+		}
+		if c := bytes.Compare(a.Slot[:], b.Slot[:]); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.Bucket[:], b.Bucket[:])
+	})
+	return storageWrites
 }
 
 // RevertAll sets all coverage in the coverage map as reverted coverage. Reverted coverage is updated with successful
-// coverage, the successful coverage is cleared.
+// coverage, the successful coverage is cleared. Storage writes are cleared, as they were reverted.
 // Returns a boolean indicating whether reverted coverage increased, and an error if one occurred.
 func (cm *CoverageMaps) RevertAll() (bool, error) {
 	// Acquire our thread lock and defer our unlocking for when we exit this method
@@ -242,6 +318,9 @@ func (cm *CoverageMaps) RevertAll() (bool, error) {
 			contractCoverageMap.successfulCoverage.Reset()
 		}
 	}
+
+	// Clear our storage writes, as they were reverted.
+	cm.storageWrites = make(map[StorageWrite]struct{})
 	return revertedCoverageChanged, nil
 }
 
@@ -282,6 +361,111 @@ func (cm *CoverageMaps) UniquePCs() uint64 {
 	return uniquePCs
 }
 
+// CoveredPCs returns the program counters (PCs) hit across all contract deployments, for each code hash. A PC is
+// considered hit if it was executed successfully or reverted.
+func (cm *CoverageMaps) CoveredPCs() map[common.Hash][]int {
+	return cm.hitPCs(true, true)
+}
+
+// SuccessfulPCs returns the program counters (PCs) executed successfully across all contract deployments, for each
+// code hash.
+func (cm *CoverageMaps) SuccessfulPCs() map[common.Hash][]int {
+	return cm.hitPCs(true, false)
+}
+
+// RevertedPCs returns the program counters (PCs) executed in reverted calls across all contract deployments, for each
+// code hash.
+func (cm *CoverageMaps) RevertedPCs() map[common.Hash][]int {
+	return cm.hitPCs(false, true)
+}
+
+// SuccessfulEdges returns the hit count buckets (as bit flags) each edge was taken within successfully across all
+// contract deployments, by edge key, for each code hash.
+func (cm *CoverageMaps) SuccessfulEdges() map[common.Hash]map[uint64]uint8 {
+	return cm.hitEdges(true)
+}
+
+// RevertedEdges returns the hit count buckets (as bit flags) each edge was taken within in reverted calls across all
+// contract deployments, by edge key, for each code hash.
+func (cm *CoverageMaps) RevertedEdges() map[common.Hash]map[uint64]uint8 {
+	return cm.hitEdges(false)
+}
+
+// hitEdges returns the hit count buckets (as bit flags) each edge was taken within across all contract deployments, by
+// edge key, for each code hash, considering either successful or reverted coverage. Edges taken in a transaction whose
+// coverage is not yet finalized are included with the bucket of their current hit count.
+func (cm *CoverageMaps) hitEdges(successful bool) map[common.Hash]map[uint64]uint8 {
+	// Acquire our thread lock and defer our unlocking for when we exit this method
+	cm.updateLock.Lock()
+	defer cm.updateLock.Unlock()
+
+	hitEdges := make(map[common.Hash]map[uint64]uint8)
+	for codeHash, mapsByAddress := range cm.maps {
+		// Consider the coverage of all deployments of this code hash as a set.
+		bucketsByEdgeKey := make(map[uint64]uint8)
+		for _, contractCoverageMap := range mapsByAddress {
+			data := contractCoverageMap.revertedCoverage
+			if successful {
+				data = contractCoverageMap.successfulCoverage
+			}
+			if data == nil {
+				continue
+			}
+			for edgeKey, buckets := range data.edgeHitBuckets {
+				bucketsByEdgeKey[edgeKey] |= buckets
+			}
+			for edgeKey, hitCount := range data.edgeHitCounts {
+				bucketsByEdgeKey[edgeKey] |= getEdgeHitCountBucket(hitCount)
+			}
+		}
+		if len(bucketsByEdgeKey) > 0 {
+			hitEdges[codeHash] = bucketsByEdgeKey
+		}
+	}
+	return hitEdges
+}
+
+// hitPCs returns the program counters (PCs) hit across all contract deployments, for each code hash, considering
+// successful and/or reverted coverage as requested. PCs are returned in ascending order.
+func (cm *CoverageMaps) hitPCs(successful bool, reverted bool) map[common.Hash][]int {
+	// Acquire our thread lock and defer our unlocking for when we exit this method
+	cm.updateLock.Lock()
+	defer cm.updateLock.Unlock()
+
+	hitPCs := make(map[common.Hash][]int)
+	for codeHash, mapsByAddress := range cm.maps {
+		// Consider the coverage of all deployments of this code hash as a set.
+		uniquePCsForHash := make(map[int]struct{})
+		for _, contractCoverageMap := range mapsByAddress {
+			coverageData := make([]*CoverageMapBytecodeData, 0)
+			if successful {
+				coverageData = append(coverageData, contractCoverageMap.successfulCoverage)
+			}
+			if reverted {
+				coverageData = append(coverageData, contractCoverageMap.revertedCoverage)
+			}
+			for _, data := range coverageData {
+				if data == nil {
+					continue
+				}
+				for i, hits := range data.executedFlags {
+					if hits != 0 {
+						uniquePCsForHash[i] = struct{}{}
+					}
+				}
+			}
+		}
+
+		// Sort the PCs so they are returned deterministically.
+		if len(uniquePCsForHash) > 0 {
+			pcs := maps.Keys(uniquePCsForHash)
+			slices.Sort(pcs)
+			hitPCs[codeHash] = pcs
+		}
+	}
+	return hitPCs
+}
+
 // ContractCoverageMap represents a data structure used to identify instruction execution coverage of a contract.
 type ContractCoverageMap struct {
 	// successfulCoverage represents coverage for the contract bytecode, which did not encounter a revert and was
@@ -337,11 +521,22 @@ func (cm *ContractCoverageMap) updateCoveredAt(codeSize int, pc uint64) (bool, e
 // or runtime bytecode.
 type CoverageMapBytecodeData struct {
 	executedFlags []uint
+
+	// edgeHitCounts describes the amount of times each edge was taken in the transaction being traced, by edge key.
+	// This is only populated if edge coverage is enabled, and is cleared once the hit counts are classified into
+	// edgeHitBuckets.
+	edgeHitCounts map[uint64]uint
+
+	// edgeHitBuckets describes the hit count buckets (as bit flags) each edge was taken within, by edge key. This is
+	// only populated if edge coverage is enabled.
+	edgeHitBuckets map[uint64]uint8
 }
 
 // Reset resets the bytecode coverage map data to be empty.
 func (cm *CoverageMapBytecodeData) Reset() {
 	cm.executedFlags = nil
+	cm.edgeHitCounts = nil
+	cm.edgeHitBuckets = nil
 }
 
 // Equal checks whether the provided CoverageMapBytecodeData contains the same data as the current one.
@@ -352,7 +547,8 @@ func (cm *CoverageMapBytecodeData) Equal(b *CoverageMapBytecodeData) bool {
 	smallestSize := utils.Min(len(cm.executedFlags), len(b.executedFlags))
 	// TODO: Currently we are checking equality by making sure the two maps have the same hit counts
 	//  it may make sense to just check that both of them are greater than zero
-	return slices.Equal(cm.executedFlags[:smallestSize], b.executedFlags[:smallestSize])
+	return slices.Equal(cm.executedFlags[:smallestSize], b.executedFlags[:smallestSize]) &&
+		maps.Equal(cm.edgeHitBuckets, b.edgeHitBuckets)
 }
 
 // HitCount returns the number of times that the provided program counter (PC) has been hit. If zero is returned, then
@@ -375,9 +571,12 @@ func (cm *CoverageMapBytecodeData) HitCount(pc int) uint {
 // update updates the hit count of the current CoverageMapBytecodeData with the provided one.
 // Returns a boolean indicating whether new coverage was achieved, or an error if one was encountered.
 func (cm *CoverageMapBytecodeData) update(coverageMap *CoverageMapBytecodeData) (bool, error) {
+	// Update our edge coverage first, as it is tracked independently of our execution data.
+	edgesChanged := cm.updateEdges(coverageMap)
+
 	// If the coverage map execution data provided is nil, exit early
 	if coverageMap.executedFlags == nil {
-		return false, nil
+		return edgesChanged, nil
 	}
 
 	// If the current map has no execution data, simply set it to the provided one.
@@ -387,7 +586,7 @@ func (cm *CoverageMapBytecodeData) update(coverageMap *CoverageMapBytecodeData)
 	}
 
 	// Update each byte which represents a position in the bytecode which was covered.
-	changed := false
+	changed := edgesChanged
 	for i := 0; i < len(cm.executedFlags) && i < len(coverageMap.executedFlags); i++ {
 		// Only update the map if we haven't seen this coverage before
 		if cm.executedFlags[i] == 0 && coverageMap.executedFlags[i] != 0 {
@@ -398,6 +597,60 @@ func (cm *CoverageMapBytecodeData) update(coverageMap *CoverageMapBytecodeData)
 	return changed, nil
 }
 
+// updateEdges updates the edge coverage of the current CoverageMapBytecodeData with the provided one. Edge hit counts
+// are summed, while edge hit count buckets are combined.
+// Returns a boolean indicating whether an edge was taken within a hit count bucket it was not previously taken within.
+func (cm *CoverageMapBytecodeData) updateEdges(coverageMap *CoverageMapBytecodeData) bool {
+	// Sum our edge hit counts, which are still being recorded for a transaction.
+	if len(coverageMap.edgeHitCounts) > 0 {
+		if cm.edgeHitCounts == nil {
+			cm.edgeHitCounts = make(map[uint64]uint, len(coverageMap.edgeHitCounts))
+		}
+		for edgeKey, hitCount := range coverageMap.edgeHitCounts {
+			cm.edgeHitCounts[edgeKey] += hitCount
+		}
+	}
+
+	// Combine our edge hit count buckets, tracking whether any of them were new.
+	changed := false
+	if len(coverageMap.edgeHitBuckets) > 0 {
+		if cm.edgeHitBuckets == nil {
+			cm.edgeHitBuckets = make(map[uint64]uint8, len(coverageMap.edgeHitBuckets))
+		}
+		for edgeKey, buckets := range coverageMap.edgeHitBuckets {
+			if existingBuckets := cm.edgeHitBuckets[edgeKey]; existingBuckets|buckets != existingBuckets {
+				cm.edgeHitBuckets[edgeKey] = existingBuckets | buckets
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// updateEdgeAt increments the hit count of the edge between the JUMPDEST instructions at the provided program counters
+// within a CoverageMapBytecodeData.
+func (cm *CoverageMapBytecodeData) updateEdgeAt(previousPC uint64, pc uint64) {
+	if cm.edgeHitCounts == nil {
+		cm.edgeHitCounts = make(map[uint64]uint)
+	}
+	cm.edgeHitCounts[getEdgeKey(previousPC, pc)]++
+}
+
+// classifyEdgeHits converts the edge hit counts recorded in the CoverageMapBytecodeData into hit count buckets, and
+// clears the hit counts.
+func (cm *CoverageMapBytecodeData) classifyEdgeHits() {
+	if len(cm.edgeHitCounts) == 0 {
+		return
+	}
+	if cm.edgeHitBuckets == nil {
+		cm.edgeHitBuckets = make(map[uint64]uint8, len(cm.edgeHitCounts))
+	}
+	for edgeKey, hitCount := range cm.edgeHitCounts {
+		cm.edgeHitBuckets[edgeKey] |= getEdgeHitCountBucket(hitCount)
+	}
+	cm.edgeHitCounts = nil
+}
+
 // updateCoveredAt updates the hit count at a given program counter location within a CoverageMapBytecodeData.
 // Returns a boolean indicating whether new coverage was achieved, or an error if one occurred.
 func (cm *CoverageMapBytecodeData) updateCoveredAt(codeSize int, pc uint64) (bool, error) {
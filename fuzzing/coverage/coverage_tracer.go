@@ -59,6 +59,13 @@ type CoverageTracer struct {
 	// since init vs runtime produces different results from getContractCoverageMapHash.
 	// The Hash key is a contract's codehash, which uniquely identifies it.
 	codeHashCache [2]map[common.Hash]common.Hash
+
+	// coverageMode describes the units of execution tracked as coverage.
+	coverageMode CoverageMode
+
+	// storageWriteBucketing describes how values written to storage are grouped when recording storage write
+	// coverage. If it does not enable storage write coverage, storage writes are not recorded.
+	storageWriteBucketing StorageWriteBucketing
 }
 
 // coverageTracerCallFrameState tracks state across call frames in the tracer.
@@ -71,14 +78,22 @@ type coverageTracerCallFrameState struct {
 
 	// lookupHash describes the hash used to look up the ContractCoverageMap being updated in this frame.
 	lookupHash *common.Hash
+
+	// lastJumpdestPC describes the program counter of the last JUMPDEST instruction executed in this frame, or zero if
+	// none was executed yet. It is used to track edge coverage.
+	lastJumpdestPC uint64
 }
 
-// NewCoverageTracer returns a new CoverageTracer.
-func NewCoverageTracer() *CoverageTracer {
+// NewCoverageTracer returns a new CoverageTracer. The provided CoverageMode determines the units of execution tracked
+// as coverage, and the provided StorageWriteBucketing determines whether and how storage writes are recorded as
+// coverage.
+func NewCoverageTracer(coverageMode CoverageMode, storageWriteBucketing StorageWriteBucketing) *CoverageTracer {
 	tracer := &CoverageTracer{
-		coverageMaps:    NewCoverageMaps(),
-		callFrameStates: make([]*coverageTracerCallFrameState, 0),
-		codeHashCache:   [2]map[common.Hash]common.Hash{make(map[common.Hash]common.Hash), make(map[common.Hash]common.Hash)},
+		coverageMaps:          NewCoverageMaps(),
+		callFrameStates:       make([]*coverageTracerCallFrameState, 0),
+		codeHashCache:         [2]map[common.Hash]common.Hash{make(map[common.Hash]common.Hash), make(map[common.Hash]common.Hash)},
+		coverageMode:          coverageMode,
+		storageWriteBucketing: storageWriteBucketing,
 	}
 	nativeTracer := &tracers.Tracer{
 		Hooks: &tracing.Hooks{
@@ -142,6 +157,11 @@ func (t *CoverageTracer) OnExit(depth int, output []byte, gasUsed uint64, err er
 	if isTopLevelFrame {
 		// Update the final coverage map if this is the top level call frame
 		_, _, coverageUpdateErr = t.coverageMaps.Update(t.callFrameStates[t.callDepth].pendingCoverageMap)
+
+		// Our coverage for this transaction is finalized, so convert any edge hit counts into buckets.
+		if t.coverageMode.tracksEdges() {
+			t.coverageMaps.classifyEdgeHits()
+		}
 	} else {
 		// Move coverage up one call frame
 		_, _, coverageUpdateErr = t.callFrameStates[t.callDepth-1].pendingCoverageMap.Update(t.callFrameStates[t.callDepth].pendingCoverageMap)
@@ -192,6 +212,21 @@ func (t *CoverageTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tr
 		if coverageUpdateErr != nil {
 			logging.GlobalLogger.Panic("Coverage tracer failed to update coverage map while tracing state", coverageUpdateErr)
 		}
+
+		// If edge coverage is enabled and we reached a jump destination, record the edge from the last one.
+		if op == byte(vm.JUMPDEST) && t.coverageMode.tracksEdges() {
+			callFrameState.pendingCoverageMap.UpdateEdgeAt(address, *callFrameState.lookupHash, callFrameState.lastJumpdestPC, pc)
+			callFrameState.lastJumpdestPC = pc
+		}
+	}
+
+	// If storage write coverage is enabled and we're writing to storage, record the slot and value written.
+	if op == byte(vm.SSTORE) && t.storageWriteBucketing.Enabled() {
+		stack := scope.StackData()
+		if len(stack) >= 2 {
+			slot := common.Hash(stack[len(stack)-1].Bytes32())
+			callFrameState.pendingCoverageMap.UpdateStorageWriteAt(address, slot, &stack[len(stack)-2], t.storageWriteBucketing)
+		}
 	}
 }
 
@@ -0,0 +1,52 @@
+package coverage
+
+// CoverageMode describes the units of execution tracked as coverage by a CoverageTracer.
+type CoverageMode string
+
+const (
+	// CoverageModePC tracks each program counter executed as coverage.
+	CoverageModePC CoverageMode = "pc"
+
+	// CoverageModeEdge extends CoverageModePC, additionally tracking each transition between two JUMPDEST
+	// instructions within a call frame (an edge) as coverage. Edges are tracked alongside a bucket describing how many
+	// times they were taken within a transaction, so taking a known edge a notably different amount of times is also
+	// considered new coverage.
+	CoverageModeEdge CoverageMode = "edge"
+)
+
+// IsValid indicates whether the CoverageMode is a supported coverage mode.
+func (m CoverageMode) IsValid() bool {
+	return m == CoverageModePC || m == CoverageModeEdge
+}
+
+// tracksEdges indicates whether the CoverageMode tracks edges as coverage.
+func (m CoverageMode) tracksEdges() bool {
+	return m == CoverageModeEdge
+}
+
+// getEdgeKey obtains the key used to track the edge between the JUMPDEST instructions at the provided program
+// counters. The previous program counter is zero if no JUMPDEST was executed prior in the call frame.
+func getEdgeKey(previousPC uint64, pc uint64) uint64 {
+	return previousPC<<32 | pc&0xffffffff
+}
+
+// getEdgeHitCountBucket obtains a bit flag describing the bucket the provided amount of times an edge was taken
+// belongs to. Buckets are 1, 2, 3, 4-7, 8-15, 16-31, 32-127, and 128 or more.
+func getEdgeHitCountBucket(hitCount uint) uint8 {
+	switch {
+	case hitCount == 0:
+		return 0
+	case hitCount <= 3:
+		return 1 << (hitCount - 1)
+	case hitCount <= 7:
+		return 1 << 3
+	case hitCount <= 15:
+		return 1 << 4
+	case hitCount <= 31:
+		return 1 << 5
+	case hitCount <= 127:
+		return 1 << 6
+	default:
+		return 1 << 7
+	}
+}
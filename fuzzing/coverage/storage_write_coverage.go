@@ -0,0 +1,61 @@
+package coverage
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// StorageWriteBucketing describes a strategy used to group the values written to storage slots, so that writes of
+// values within a previously unseen group to a storage slot are treated as new coverage.
+type StorageWriteBucketing string
+
+const (
+	// StorageWriteBucketingNone disables storage write coverage.
+	StorageWriteBucketingNone StorageWriteBucketing = "none"
+
+	// StorageWriteBucketingZero groups values written to storage by whether they are zero or non-zero.
+	StorageWriteBucketingZero StorageWriteBucketing = "zero"
+
+	// StorageWriteBucketingMagnitude groups values written to storage by their bit length.
+	StorageWriteBucketingMagnitude StorageWriteBucketing = "magnitude"
+
+	// StorageWriteBucketingExact treats every distinct value written to storage as its own group.
+	StorageWriteBucketingExact StorageWriteBucketing = "exact"
+)
+
+// IsValid indicates whether the StorageWriteBucketing is a supported bucketing strategy.
+func (b StorageWriteBucketing) IsValid() bool {
+	return b == StorageWriteBucketingNone || b == StorageWriteBucketingZero || b == StorageWriteBucketingMagnitude ||
+		b == StorageWriteBucketingExact
+}
+
+// Enabled indicates whether the StorageWriteBucketing enables storage write coverage.
+func (b StorageWriteBucketing) Enabled() bool {
+	return b != "" && b != StorageWriteBucketingNone
+}
+
+// bucket obtains the group the provided value written to storage belongs to, given the bucketing strategy.
+func (b StorageWriteBucketing) bucket(value *uint256.Int) common.Hash {
+	switch b {
+	case StorageWriteBucketingZero:
+		if value.IsZero() {
+			return common.Hash{}
+		}
+		return common.BigToHash(common.Big1)
+	case StorageWriteBucketingMagnitude:
+		return common.Hash(uint256.NewInt(uint64(value.BitLen())).Bytes32())
+	default:
+		return common.Hash(value.Bytes32())
+	}
+}
+
+// StorageWrite describes a unit of storage write coverage: a write to a storage slot of a contract of a value within
+// a given group, as determined by a StorageWriteBucketing.
+type StorageWrite struct {
+	// Address describes the address of the contract whose storage was written to.
+	Address common.Address
+	// Slot describes the storage slot written to.
+	Slot common.Hash
+	// Bucket describes the group the value written belongs to.
+	Bucket common.Hash
+}
@@ -0,0 +1,56 @@
+package detectors
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ArbitraryCallDetector is a Detector which reports a call or delegatecall made to an address provided in the
+// transaction calldata, allowing the sender to choose the code which is called.
+type ArbitraryCallDetector struct{}
+
+// ID obtains a unique identifier for the detector.
+func (d *ArbitraryCallDetector) ID() string {
+	return "arbitrary-call"
+}
+
+// Name obtains a human-readable name for the detector.
+func (d *ArbitraryCallDetector) Name() string {
+	return "Arbitrary Call"
+}
+
+// Detect inspects the provided execution trace for a call or delegatecall made to an address provided in the
+// transaction calldata. The offending call frame is the call made to that address.
+// Returns a Finding describing the first occurrence of the pattern, or nil if it was not found.
+func (d *ArbitraryCallDetector) Detect(trace *executiontracer.ExecutionTrace) *Finding {
+	if trace == nil || trace.TopLevelCallFrame == nil {
+		return nil
+	}
+
+	// Determine which addresses were provided in calldata.
+	controlledAddresses := calldataAddresses(trace.TopLevelCallFrame)
+	if len(controlledAddresses) == 0 {
+		return nil
+	}
+
+	return walkCallFrames(trace.TopLevelCallFrame, func(callFrame *executiontracer.CallFrame) *Finding {
+		for _, childCallFrame := range callFrame.ChildCallFrames() {
+			if childCallFrame.ReturnError != nil {
+				continue
+			}
+			callType := childCallFrame.CallType
+			if (callType == vm.CALL || callType == vm.CALLCODE || callType == vm.DELEGATECALL) && controlledAddresses[childCallFrame.CodeAddress] {
+				return &Finding{
+					CallFrame: childCallFrame,
+					Description: fmt.Sprintf(
+						"%s made a %s to address %s, which was provided in calldata",
+						callFrame.ToAddress.String(), callType.String(), childCallFrame.CodeAddress.String(),
+					),
+				}
+			}
+		}
+		return nil
+	})
+}
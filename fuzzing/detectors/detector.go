@@ -0,0 +1,71 @@
+package detectors
+
+import (
+	"math/big"
+
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxPrecompileAddress describes the highest address which is treated as a potential precompile. Calldata words which
+// resolve to an address in this range are not treated as sender-controlled addresses, as they are indistinguishable
+// from small integer arguments.
+var maxPrecompileAddress = big.NewInt(0xff)
+
+// Detector describes a detector which inspects an execution trace for a vulnerable pattern.
+type Detector interface {
+	// ID obtains a unique identifier for the detector.
+	ID() string
+
+	// Name obtains a human-readable name for the detector.
+	Name() string
+
+	// Detect inspects the provided execution trace for the vulnerable pattern the detector targets.
+	// Returns a Finding describing the first occurrence of the pattern, or nil if it was not found.
+	Detect(trace *executiontracer.ExecutionTrace) *Finding
+}
+
+// Finding describes an occurrence of a vulnerable pattern in an execution trace, as reported by a Detector.
+type Finding struct {
+	// CallFrame refers to the offending call frame within the execution trace.
+	CallFrame *executiontracer.CallFrame
+
+	// Description describes the vulnerable pattern which was found.
+	Description string
+}
+
+// walkCallFrames visits the provided call frame and all of its descendants in chronological order, skipping any
+// which were reverted, as their effects were discarded. The visit function returns a Finding to stop walking early.
+// Returns the first Finding returned by the visit function, or nil if none was returned.
+func walkCallFrames(callFrame *executiontracer.CallFrame, visit func(callFrame *executiontracer.CallFrame) *Finding) *Finding {
+	if callFrame == nil || callFrame.ReturnError != nil {
+		return nil
+	}
+	if finding := visit(callFrame); finding != nil {
+		return finding
+	}
+	for _, childCallFrame := range callFrame.ChildCallFrames() {
+		if finding := walkCallFrames(childCallFrame, visit); finding != nil {
+			return finding
+		}
+	}
+	return nil
+}
+
+// calldataAddresses obtains the set of addresses provided in the calldata of the provided top level call frame. Every
+// 32-byte word following the method selector which is a left-padded address is treated as an address argument.
+// Returns the set of addresses found in calldata.
+func calldataAddresses(topLevelCallFrame *executiontracer.CallFrame) map[common.Address]bool {
+	addresses := make(map[common.Address]bool)
+	if topLevelCallFrame.IsContractCreation() || len(topLevelCallFrame.InputData) < 4 {
+		return addresses
+	}
+	arguments := topLevelCallFrame.InputData[4:]
+	for offset := 0; offset+32 <= len(arguments); offset += 32 {
+		word := new(big.Int).SetBytes(arguments[offset : offset+32])
+		if word.BitLen() <= common.AddressLength*8 && word.Cmp(maxPrecompileAddress) > 0 {
+			addresses[common.BigToAddress(word)] = true
+		}
+	}
+	return addresses
+}
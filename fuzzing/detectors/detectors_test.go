@@ -0,0 +1,134 @@
+package detectors
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testSender   = common.HexToAddress("0x10000")
+	testContract = common.HexToAddress("0x54919A19522Ce7c842E25735a9cFEcef1c0a06dA")
+	testTarget   = common.HexToAddress("0xA647FF3c36cFab592509E13860ab8C4F28781a66")
+)
+
+// newTestCallFrame creates a call frame for use in tests, entering it from the provided parent call frame.
+func newTestCallFrame(parent *executiontracer.CallFrame, callType vm.OpCode, to common.Address, inputData []byte) *executiontracer.CallFrame {
+	callFrame := &executiontracer.CallFrame{
+		ToAddress:       to,
+		CodeAddress:     to,
+		Operations:      make([]any, 0),
+		InputData:       inputData,
+		CallValue:       big.NewInt(0),
+		CallType:        callType,
+		ExecutedCode:    true,
+		ParentCallFrame: parent,
+	}
+	if parent != nil {
+		callFrame.SenderAddress = parent.ToAddress
+		parent.Operations = append(parent.Operations, callFrame)
+	} else {
+		callFrame.SenderAddress = testSender
+	}
+	return callFrame
+}
+
+// newTestCalldata creates calldata for a method call with the provided address argument.
+func newTestCalldata(address common.Address) []byte {
+	return append([]byte{0x01, 0x02, 0x03, 0x04}, common.LeftPadBytes(address.Bytes(), 32)...)
+}
+
+// TestReentrancyDetector ensures state writes made after an external call into a sender-controlled contract are
+// reported, while writes made before such a call, or after calls to the transaction sender, are not.
+func TestReentrancyDetector(t *testing.T) {
+	detector := &ReentrancyDetector{}
+
+	// A write before the call to a contract provided in calldata is not reported.
+	topLevelCallFrame := newTestCallFrame(nil, vm.CALL, testContract, newTestCalldata(testTarget))
+	topLevelCallFrame.Operations = append(topLevelCallFrame.Operations, &executiontracer.StorageWrite{})
+	externalCallFrame := newTestCallFrame(topLevelCallFrame, vm.CALL, testTarget, nil)
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+
+	// A write after the call is reported, with the external call as the offending frame.
+	topLevelCallFrame.Operations = append(topLevelCallFrame.Operations, &executiontracer.StorageWrite{})
+	finding := detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame})
+	assert.NotNil(t, finding)
+	assert.Equal(t, testTarget, finding.CallFrame.CodeAddress)
+
+	// The same call is not reported if it did not execute code, as the callee cannot re-enter.
+	externalCallFrame.ExecutedCode = false
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+
+	// A write after a call to an address which is not provided in calldata is not reported.
+	topLevelCallFrame = newTestCallFrame(nil, vm.CALL, testContract, newTestCalldata(common.Address{}))
+	newTestCallFrame(topLevelCallFrame, vm.CALL, testTarget, nil)
+	topLevelCallFrame.Operations = append(topLevelCallFrame.Operations, &executiontracer.StorageWrite{})
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+
+	// A write after a call to the transaction sender is not reported, as it is an externally owned account.
+	topLevelCallFrame = newTestCallFrame(nil, vm.CALL, testContract, newTestCalldata(common.Address{}))
+	newTestCallFrame(topLevelCallFrame, vm.CALL, testSender, nil)
+	topLevelCallFrame.Operations = append(topLevelCallFrame.Operations, &executiontracer.StorageWrite{})
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+
+	// A write after a call to an address provided in calldata is reported.
+	topLevelCallFrame = newTestCallFrame(nil, vm.CALL, testContract, newTestCalldata(testTarget))
+	newTestCallFrame(topLevelCallFrame, vm.CALL, testTarget, nil)
+	topLevelCallFrame.Operations = append(topLevelCallFrame.Operations, &executiontracer.StorageWrite{})
+	assert.NotNil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+
+	// Nothing is reported if the call frame reverted.
+	topLevelCallFrame.ReturnError = errors.New("execution reverted")
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+}
+
+// TestArbitraryCallDetector ensures calls and delegatecalls to addresses provided in calldata are reported, while
+// static calls and calls to other addresses are not.
+func TestArbitraryCallDetector(t *testing.T) {
+	detector := &ArbitraryCallDetector{}
+
+	for _, callType := range []vm.OpCode{vm.CALL, vm.DELEGATECALL} {
+		topLevelCallFrame := newTestCallFrame(nil, vm.CALL, testContract, newTestCalldata(testTarget))
+		newTestCallFrame(topLevelCallFrame, callType, testTarget, nil)
+		finding := detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame})
+		assert.NotNil(t, finding)
+		assert.Equal(t, testTarget, finding.CallFrame.CodeAddress)
+	}
+
+	// Static calls cannot change state and are not reported.
+	topLevelCallFrame := newTestCallFrame(nil, vm.CALL, testContract, newTestCalldata(testTarget))
+	newTestCallFrame(topLevelCallFrame, vm.STATICCALL, testTarget, nil)
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+
+	// Calls to addresses which were not provided in calldata are not reported.
+	topLevelCallFrame = newTestCallFrame(nil, vm.CALL, testContract, newTestCalldata(testSender))
+	newTestCallFrame(topLevelCallFrame, vm.CALL, testTarget, nil)
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+
+	// Small integer arguments are not mistaken for precompile addresses.
+	topLevelCallFrame = newTestCallFrame(nil, vm.CALL, testContract, newTestCalldata(common.BytesToAddress([]byte{0x04})))
+	newTestCallFrame(topLevelCallFrame, vm.CALL, common.BytesToAddress([]byte{0x04}), nil)
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+}
+
+// TestSelfDestructDetector ensures executed SELFDESTRUCT operations are reported unless they were reverted.
+func TestSelfDestructDetector(t *testing.T) {
+	detector := &SelfDestructDetector{}
+
+	topLevelCallFrame := newTestCallFrame(nil, vm.CALL, testContract, nil)
+	childCallFrame := newTestCallFrame(topLevelCallFrame, vm.CALL, testTarget, nil)
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+
+	childCallFrame.SelfDestructed = true
+	finding := detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame})
+	assert.NotNil(t, finding)
+	assert.Equal(t, childCallFrame, finding.CallFrame)
+
+	childCallFrame.ReturnError = errors.New("execution reverted")
+	assert.Nil(t, detector.Detect(&executiontracer.ExecutionTrace{TopLevelCallFrame: topLevelCallFrame}))
+}
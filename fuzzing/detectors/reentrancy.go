@@ -0,0 +1,59 @@
+package detectors
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ReentrancyDetector is a Detector which reports state writes made by a contract after it made an external call into a
+// sender-controlled contract, which could have re-entered the contract before its state was updated. Sender-controlled
+// contracts are addresses provided in the transaction calldata which executed code when called. The sender of the
+// transaction is not considered, as it is an externally owned account which cannot re-enter.
+type ReentrancyDetector struct{}
+
+// ID obtains a unique identifier for the detector.
+func (d *ReentrancyDetector) ID() string {
+	return "reentrancy"
+}
+
+// Name obtains a human-readable name for the detector.
+func (d *ReentrancyDetector) Name() string {
+	return "Reentrancy"
+}
+
+// Detect inspects the provided execution trace for state writes made after an external call into a sender-controlled
+// contract. The offending call frame is the external call.
+// Returns a Finding describing the first occurrence of the pattern, or nil if it was not found.
+func (d *ReentrancyDetector) Detect(trace *executiontracer.ExecutionTrace) *Finding {
+	if trace == nil || trace.TopLevelCallFrame == nil {
+		return nil
+	}
+
+	// Determine which addresses are controlled by the sender.
+	controlledAddresses := calldataAddresses(trace.TopLevelCallFrame)
+
+	return walkCallFrames(trace.TopLevelCallFrame, func(callFrame *executiontracer.CallFrame) *Finding {
+		// Track the last external call made into a sender-controlled contract, and report any state written after it.
+		// Calls which executed no code (e.g. transfers to accounts without code) cannot re-enter, so they are ignored.
+		var externalCallFrame *executiontracer.CallFrame
+		for _, operation := range callFrame.Operations {
+			if childCallFrame, ok := operation.(*executiontracer.CallFrame); ok {
+				isCall := childCallFrame.CallType == vm.CALL || childCallFrame.CallType == vm.CALLCODE
+				if isCall && childCallFrame.ExecutedCode && controlledAddresses[childCallFrame.CodeAddress] {
+					externalCallFrame = childCallFrame
+				}
+			} else if storageWrite, ok := operation.(*executiontracer.StorageWrite); ok && externalCallFrame != nil {
+				return &Finding{
+					CallFrame: externalCallFrame,
+					Description: fmt.Sprintf(
+						"storage slot %s of %s was written after an external call to sender-controlled contract %s",
+						storageWrite.Slot.Hex(), callFrame.ToAddress.String(), externalCallFrame.CodeAddress.String(),
+					),
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,38 @@
+package detectors
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+)
+
+// SelfDestructDetector is a Detector which reports any SELFDESTRUCT operation which was executed and not reverted.
+type SelfDestructDetector struct{}
+
+// ID obtains a unique identifier for the detector.
+func (d *SelfDestructDetector) ID() string {
+	return "selfdestruct"
+}
+
+// Name obtains a human-readable name for the detector.
+func (d *SelfDestructDetector) Name() string {
+	return "Reachable SELFDESTRUCT"
+}
+
+// Detect inspects the provided execution trace for an executed SELFDESTRUCT operation. The offending call frame is
+// the call frame which executed it.
+// Returns a Finding describing the first occurrence of the pattern, or nil if it was not found.
+func (d *SelfDestructDetector) Detect(trace *executiontracer.ExecutionTrace) *Finding {
+	if trace == nil {
+		return nil
+	}
+	return walkCallFrames(trace.TopLevelCallFrame, func(callFrame *executiontracer.CallFrame) *Finding {
+		if callFrame.SelfDestructed {
+			return &Finding{
+				CallFrame:   callFrame,
+				Description: fmt.Sprintf("%s executed SELFDESTRUCT", callFrame.ToAddress.String()),
+			}
+		}
+		return nil
+	})
+}
@@ -3,6 +3,7 @@ package executiontracer
 import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"math/big"
 )
 
@@ -44,7 +45,8 @@ type CallFrame struct {
 	CodeRuntimeBytecode []byte
 
 	// Operations contains a chronological history of updates in the call frame.
-	// Potential types currently are *types.Log (events) or CallFrame (entering of a new child frame).
+	// Potential types currently are *types.Log (events), *StorageWrite (storage updates) or CallFrame (entering of a
+	// new child frame).
 	Operations []any
 
 	// SelfDestructed indicates whether the call frame executed a SELFDESTRUCT operation.
@@ -63,6 +65,9 @@ type CallFrame struct {
 	// CallValue describes the ETH value attached to a given CallFrame
 	CallValue *big.Int
 
+	// CallType describes the operation used to enter the CallFrame (e.g. CALL, DELEGATECALL, STATICCALL, CREATE).
+	CallType vm.OpCode
+
 	// ExecutedCode is a boolean that indicates whether code was executed within a CallFrame. A simple transfer of ETH
 	// would be an example of a CallFrame where ExecutedCode would be false
 	ExecutedCode bool
@@ -75,6 +80,16 @@ type CallFrame struct {
 	ParentCallFrame *CallFrame
 }
 
+// StorageWrite describes a storage slot update made by an SSTORE operation within a CallFrame, as recorded by an
+// ExecutionTracer. The update applies to the storage of the call frame's ToAddress.
+type StorageWrite struct {
+	// Slot refers to the storage slot which was written to.
+	Slot common.Hash
+
+	// Value refers to the value written to the storage slot.
+	Value common.Hash
+}
+
 // IsContractCreation indicates whether a contract creation operation was attempted immediately within this call frame.
 // This does not include child or parent frames.
 // Returns true if this call frame attempted contract creation.
@@ -89,6 +104,18 @@ func (c *CallFrame) IsProxyCall() bool {
 	return c.ToAddress != c.CodeAddress
 }
 
+// Reverted indicates whether the changes made by this call frame were reverted, either because it returned an error
+// or because any of its parent call frames did.
+// Returns true if the call frame or any of its parent call frames returned an error.
+func (c *CallFrame) Reverted() bool {
+	for callFrame := c; callFrame != nil; callFrame = callFrame.ParentCallFrame {
+		if callFrame.ReturnError != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // ChildCallFrames is a getter function that returns all children of the current CallFrame. A child CallFrame is one
 // that is entered by this CallFrame
 func (c *CallFrame) ChildCallFrames() CallFrames {
@@ -25,6 +25,10 @@ type ExecutionTrace struct {
 	// address calls upon a contract.
 	TopLevelCallFrame *CallFrame
 
+	// HighlightedCallFrame refers to a call frame within the trace which should be highlighted when the trace is
+	// displayed (e.g. a call frame a detector flagged). It may be nil if no call frame should be highlighted.
+	HighlightedCallFrame *CallFrame
+
 	// contractDefinitions represents the known contract definitions at the time of tracing. This is used to help
 	// obtain any additional information regarding execution.
 	contractDefinitions contracts.Contracts
@@ -318,6 +322,9 @@ func (t *ExecutionTrace) generateElementsAndLogsForCallFrame(currentDepth int, c
 	// Add the call frame enter header elements
 	newElements, consoleLogString := t.generateCallFrameEnterElements(callFrame)
 	elements = append(elements, prefix)
+	if callFrame == t.HighlightedCallFrame {
+		elements = append(elements, colors.RedBold, "[highlighted] ", colors.Reset)
+	}
 	elements = append(elements, newElements...)
 
 	// If this call frame was a console.log contract call, add the string to the list of logs
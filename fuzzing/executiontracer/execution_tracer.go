@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/chain/types"
 	"github.com/crytic/medusa/fuzzing/contracts"
 	"github.com/crytic/medusa/utils"
 	"github.com/ethereum/go-ethereum/common"
@@ -17,6 +18,29 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+// executionTracerResultsKey describes the key to use when storing tracer results in call message results, or when
+// querying them.
+const executionTracerResultsKey = "ExecutionTracerResults"
+
+// GetExecutionTracerResults obtains the ExecutionTrace stored by an ExecutionTracer from message results. This is nil
+// if no ExecutionTrace was recorded by a tracer (e.g. ExecutionTracer was not attached during this message execution).
+func GetExecutionTracerResults(messageResults *types.MessageResults) *ExecutionTrace {
+	// Try to obtain the results the tracer should've stored.
+	if genericResult, ok := messageResults.AdditionalResults[executionTracerResultsKey]; ok {
+		if castedResult, ok := genericResult.(*ExecutionTrace); ok {
+			return castedResult
+		}
+	}
+
+	// If we could not obtain them, return nil.
+	return nil
+}
+
+// RemoveExecutionTracerResults removes the ExecutionTrace stored by an ExecutionTracer from message results.
+func RemoveExecutionTracerResults(messageResults *types.MessageResults) {
+	delete(messageResults.AdditionalResults, executionTracerResultsKey)
+}
+
 // CallWithExecutionTrace obtains an execution trace for a given call, on the provided chain, using the state
 // provided. If a nil state is provided, the current chain state will be used.
 // Returns the ExecutionTrace for the call or an error if one occurs.
@@ -84,7 +108,7 @@ func NewExecutionTracer(contractDefinitions contracts.Contracts, cheatCodeContra
 			OnOpcode:  tracer.OnOpcode,
 		},
 	}
-	tracer.nativeTracer = &chain.TestChainTracer{Tracer: innerTracer, CaptureTxEndSetAdditionalResults: nil}
+	tracer.nativeTracer = &chain.TestChainTracer{Tracer: innerTracer, CaptureTxEndSetAdditionalResults: tracer.CaptureTxEndSetAdditionalResults}
 
 	return tracer
 }
@@ -119,6 +143,14 @@ func (t *ExecutionTracer) OnTxEnd(receipt *coretypes.Receipt, err error) {
 	t.traceMap[receipt.TxHash] = t.trace
 }
 
+// CaptureTxEndSetAdditionalResults can be used to set additional results captured from execution tracing. If this
+// tracer is used during transaction execution (block creation), the results can later be queried from the block.
+// This method will only be called on the added tracer if it implements the extended TestChainTracer interface.
+func (t *ExecutionTracer) CaptureTxEndSetAdditionalResults(results *types.MessageResults) {
+	// Store our tracer results.
+	results.AdditionalResults[executionTracerResultsKey] = t.trace
+}
+
 // OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
 func (t *ExecutionTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
 	// Reset our capture state
@@ -192,7 +224,9 @@ func (t *ExecutionTracer) resolveCallFrameContractDefinitions(callFrame *CallFra
 }
 
 // captureEnteredCallFrame is a helper method used when a new call frame is entered to record information about it.
-func (t *ExecutionTracer) captureEnteredCallFrame(fromAddress common.Address, toAddress common.Address, inputData []byte, isContractCreation bool, value *big.Int) {
+func (t *ExecutionTracer) captureEnteredCallFrame(fromAddress common.Address, toAddress common.Address, inputData []byte, callType vm.OpCode, value *big.Int) {
+	isContractCreation := callType == vm.CREATE || callType == vm.CREATE2
+
 	// Create our call frame struct to track data for this call frame we entered.
 	callFrameData := &CallFrame{
 		SenderAddress:       fromAddress,
@@ -212,6 +246,7 @@ func (t *ExecutionTracer) captureEnteredCallFrame(fromAddress common.Address, to
 		ReturnData:          nil,
 		ExecutedCode:        false,
 		CallValue:           value,
+		CallType:            callType,
 		ReturnError:         nil,
 		ParentCallFrame:     t.currentCallFrame,
 	}
@@ -263,7 +298,7 @@ func (t *ExecutionTracer) captureExitedCallFrame(output []byte, err error) {
 // OnEnter initializes the tracing operation for the top of a call frame, as defined by tracers.Tracer.
 func (t *ExecutionTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
 	// Capture that a new call frame was entered.
-	t.captureEnteredCallFrame(from, to, input, (typ == byte(vm.CREATE) || typ == byte(vm.CREATE2)), value)
+	t.captureEnteredCallFrame(from, to, input, vm.OpCode(typ), value)
 }
 
 // OnExit is called after a call to finalize tracing completes for the top of a call frame, as defined by tracers.Tracer.
@@ -298,6 +333,17 @@ func (t *ExecutionTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope t
 		t.currentCallFrame.SelfDestructed = true
 	}
 
+	// If we encounter an SSTORE operation, record the storage write.
+	if op == byte(vm.SSTORE) {
+		stack := scope.StackData()
+		if len(stack) >= 2 {
+			t.currentCallFrame.Operations = append(t.currentCallFrame.Operations, &StorageWrite{
+				Slot:  stack[len(stack)-1].Bytes32(),
+				Value: stack[len(stack)-2].Bytes32(),
+			})
+		}
+	}
+
 	// If a log operation occurred, add a deferred operation to capture it.
 	// TODO: Move this to OnLog
 	if op == byte(vm.LOG0) || op == byte(vm.LOG1) || op == byte(vm.LOG2) || op == byte(vm.LOG3) || op == byte(vm.LOG4) {
@@ -1,6 +1,7 @@
 package fuzzing
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -27,6 +28,7 @@ import (
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/utils/randomutils"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 
 	"github.com/crytic/medusa/chain"
 	compilationTypes "github.com/crytic/medusa/compilation/types"
@@ -38,6 +40,7 @@ import (
 	"github.com/crytic/medusa/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -91,6 +94,12 @@ type Fuzzer struct {
 	// Hooks describes the replaceable functions used by the Fuzzer.
 	Hooks FuzzerHooks
 
+	// replayChainTracerFuncs describes a list of functions called upon by a FuzzerWorker to attach tracers to the
+	// chains it replays call sequences on when checking their reproducibility, so that shrink request verifiers which
+	// rely on them can be run against the replay. Unlike FuzzerWorkerChainCreated events, these are only used
+	// internally and are not triggered for the worker's own chain.
+	replayChainTracerFuncs []func(worker *FuzzerWorker, testChain *chain.TestChain)
+
 	// logger describes the Fuzzer's log object that can be used to log important events
 	logger *logging.Logger
 }
@@ -162,6 +171,7 @@ func NewFuzzer(config config.ProjectConfig) (*Fuzzer, error) {
 			NewShrinkingValueMutatorFunc:       defaultShrinkingValueMutatorFunc,
 			ChainSetupFunc:                     chainSetupFromCompilations,
 			CallSequenceTestFuncs:              make([]CallSequenceTestFunc, 0),
+			CallSequenceEndTestFuncs:           make([]CallSequenceTestFunc, 0),
 		},
 		logger: logger,
 	}
@@ -186,7 +196,11 @@ func NewFuzzer(config config.ProjectConfig) (*Fuzzer, error) {
 		fuzzer.logger.Info("Finished compiling targets in ", time.Since(start).Round(time.Second))
 
 		// Add our compilation targets
-		fuzzer.AddCompilationTargets(compilations)
+		err = fuzzer.AddCompilationTargets(compilations)
+		if err != nil {
+			fuzzer.logger.Error("Failed to add compilation targets", err)
+			return nil, err
+		}
 	}
 
 	// Register any default providers if specified.
@@ -199,6 +213,27 @@ func NewFuzzer(config config.ProjectConfig) (*Fuzzer, error) {
 	if fuzzer.config.Fuzzing.Testing.OptimizationTesting.Enabled {
 		attachOptimizationTestCaseProvider(fuzzer)
 	}
+	if fuzzer.config.Fuzzing.Testing.FuzzTesting.Enabled {
+		attachFuzzTestCaseProvider(fuzzer)
+	}
+	if fuzzer.config.Fuzzing.Testing.DifferentialTesting.Enabled {
+		_, err = attachDifferentialTestCaseProvider(fuzzer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if fuzzer.config.Fuzzing.Testing.DrainTesting.Enabled {
+		attachDrainTestCaseProvider(fuzzer)
+	}
+	if fuzzer.config.Fuzzing.Testing.DetectorTesting.Enabled {
+		attachDetectorTestCaseProvider(fuzzer)
+	}
+	if fuzzer.config.Fuzzing.Testing.GasTesting.Enabled {
+		_, err = attachGasTestCaseProvider(fuzzer)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return fuzzer, nil
 }
 
@@ -252,6 +287,16 @@ func (f *Fuzzer) RegisterTestCase(testCase TestCase) {
 	f.testCasesLock.Lock()
 	defer f.testCasesLock.Unlock()
 
+	// If the test method was configured through NatSpec annotations, record the annotations with the test case.
+	if annotatable, ok := testCase.(annotatableTestCase); ok {
+		if annotations := annotatable.methodAnnotations(); annotations != nil {
+			testCase = &AnnotatedTestCase{
+				testCase:    testCase,
+				annotations: annotations,
+			}
+		}
+	}
+
 	// Display what is being tested
 	f.logger.Info(testCase.LogMessage().Elements()...)
 
@@ -265,6 +310,9 @@ func (f *Fuzzer) ReportTestCaseFinished(testCase TestCase) {
 	f.testCasesLock.Lock()
 	defer f.testCasesLock.Unlock()
 
+	// Report the test case as it was registered, in case it was wrapped to record its annotations.
+	testCase = f.registeredTestCase(testCase)
+
 	// If we already reported this test case as finished, stop
 	if _, alreadyExists := f.testCasesFinished[testCase.ID()]; alreadyExists {
 		return
@@ -279,15 +327,56 @@ func (f *Fuzzer) ReportTestCaseFinished(testCase TestCase) {
 		f.logger.Info(testCase.LogMessage().Elements()...)
 	}
 
-	// If the config specifies, we stop after the first failed test reported.
+	// If the config specifies, we stop after the first failed test reported. Errored tests are not failures.
 	if testCase.Status() == TestCaseStatusFailed && f.config.Fuzzing.Testing.StopOnFailedTest {
 		f.Stop()
 	}
 }
 
+// registeredTestCase obtains the TestCase registered with the Fuzzer for the provided one, which may wrap it (e.g. as an
+// AnnotatedTestCase). If no matching TestCase was registered, the provided one is returned.
+// This function expects the testCasesLock to be held by the caller.
+func (f *Fuzzer) registeredTestCase(testCase TestCase) TestCase {
+	for _, registeredTestCase := range f.testCases {
+		if annotatedTestCase, ok := registeredTestCase.(*AnnotatedTestCase); ok && annotatedTestCase.ID() == testCase.ID() {
+			return annotatedTestCase
+		}
+	}
+	return testCase
+}
+
+// reportTestCaseFlaky is used to report a failed or errored TestCase as finalized to the Fuzzer, where its result did
+// not reproduce when replayed. The TestCase is replaced by a FlakyTestCase in the Fuzzer's results.
+func (f *Fuzzer) reportTestCaseFlaky(testCase TestCase, flakiness *CallSequenceFlakiness) {
+	// Replace the test case with its flaky counterpart, retaining any annotations it was registered with.
+	var flakyTestCase TestCase = &FlakyTestCase{
+		testCase:  testCase,
+		flakiness: flakiness,
+	}
+	f.testCasesLock.Lock()
+	for i := 0; i < len(f.testCases); i++ {
+		if f.testCases[i].ID() != testCase.ID() {
+			continue
+		}
+		if annotatedTestCase, ok := f.testCases[i].(*AnnotatedTestCase); ok {
+			flakyTestCase = &AnnotatedTestCase{
+				testCase:    flakyTestCase,
+				annotations: annotatedTestCase.annotations,
+			}
+		}
+		f.testCases[i] = flakyTestCase
+	}
+	f.testCasesLock.Unlock()
+
+	// Report it as finished.
+	f.ReportTestCaseFinished(flakyTestCase)
+}
+
 // AddCompilationTargets takes a compilation and updates the Fuzzer state with additional Fuzzer.ContractDefinitions
 // definitions and Fuzzer.BaseValueSet values.
-func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilation) {
+// Returns an error if the NatSpec annotations of a contract's methods could not be parsed or applied, or if a contract
+// calls a cheat code which relies on a storage layout the compilation platform did not output.
+func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilation) error {
 	var seedFromAST bool
 
 	// No need to handle the error here since having compilation artifacts implies that we used a supported
@@ -319,8 +408,17 @@ func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilati
 		f.compilations = append(f.compilations, compilations[i])
 		compilation := &f.compilations[len(f.compilations)-1]
 
-		// Loop for each source
-		for sourcePath, source := range compilation.SourcePathToArtifact {
+		// Parse the compilation's ASTs so we can resolve the NatSpec annotations of each contract's methods.
+		annotationResolver, err := fuzzerTypes.NewAnnotationResolver(compilation)
+		if err != nil {
+			return fmt.Errorf("failed to parse NatSpec annotations: %v", err)
+		}
+
+		// Loop for each source, in sorted order so that the base value set is seeded deterministically
+		sourcePaths := maps.Keys(compilation.SourcePathToArtifact)
+		sort.Strings(sourcePaths)
+		for _, sourcePath := range sourcePaths {
+			source := compilation.SourcePathToArtifact[sourcePath]
 			// Seed from the contract's AST if we did not use slither or failed to do so
 			if seedFromAST {
 				// Seed our base value set from every source's AST
@@ -328,7 +426,9 @@ func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilati
 			}
 
 			// Loop for every contract and register it in our contract definitions
-			for contractName := range source.Contracts {
+			contractNames := maps.Keys(source.Contracts)
+			sort.Strings(contractNames)
+			for _, contractName := range contractNames {
 				contract := source.Contracts[contractName]
 
 				// Skip interfaces.
@@ -338,14 +438,28 @@ func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilati
 
 				contractDefinition := fuzzerTypes.NewContract(contractName, sourcePath, &contract, compilation)
 
-				// Sort available methods by type
-				assertionTestMethods, propertyTestMethods, optimizationTestMethods := fuzzingutils.BinTestByType(&contract,
+				// Sort available methods by type. Revert property tests and fuzz test methods are only separated out if
+				// their respective testing modes are enabled, otherwise they are treated like any other method.
+				var revertPropertyTestPrefixes, fuzzTestPrefixes []string
+				if f.config.Fuzzing.Testing.PropertyTesting.Enabled {
+					revertPropertyTestPrefixes = f.config.Fuzzing.Testing.PropertyTesting.RevertTestPrefixes
+				}
+				if f.config.Fuzzing.Testing.FuzzTesting.Enabled {
+					fuzzTestPrefixes = f.config.Fuzzing.Testing.FuzzTesting.TestPrefixes
+				}
+				assertionTestMethods, propertyTestMethods, optimizationTestMethods, fuzzTestMethods := fuzzingutils.BinTestByType(&contract,
 					f.config.Fuzzing.Testing.PropertyTesting.TestPrefixes,
-					f.config.Fuzzing.Testing.OptimizationTesting.TestPrefixes,
+					revertPropertyTestPrefixes,
+					f.config.Fuzzing.Testing.OptimizationTesting.AllTestPrefixes(),
+					fuzzTestPrefixes,
 					f.config.Fuzzing.Testing.AssertionTesting.TestViewMethods)
 				contractDefinition.AssertionTestMethods = assertionTestMethods
 				contractDefinition.PropertyTestMethods = propertyTestMethods
 				contractDefinition.OptimizationTestMethods = optimizationTestMethods
+				contractDefinition.FuzzTestMethods = fuzzTestMethods
+
+				// Pair handler methods with their preconditions, which are never called as part of a call sequence.
+				contractDefinition = contractDefinition.WithPreconditionMethods()
 
 				// Filter and record methods available for assertion testing. Property and optimization tests are always run.
 				if len(f.config.Fuzzing.Testing.TargetFunctionSignatures) > 0 {
@@ -357,16 +471,152 @@ func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilati
 					contractDefinition = contractDefinition.WithExcludedAssertionMethods(f.config.Fuzzing.Testing.ExcludeFunctionSignatures)
 				}
 
+				// Apply any per-method configuration provided through NatSpec annotations.
+				annotations, err := annotationResolver.Resolve(contractDefinition)
+				if err != nil {
+					return fmt.Errorf("failed to resolve NatSpec annotations for contract %v: %v", contractName, err)
+				}
+				contractDefinition, err = f.applyMethodAnnotations(contractDefinition, annotations)
+				if err != nil {
+					return err
+				}
+
 				f.contractDefinitions = append(f.contractDefinitions, contractDefinition)
 			}
 		}
 
 		// Cache all of our source code if it hasn't been already.
-		err := compilation.CacheSourceCode()
+		err = compilation.CacheSourceCode()
 		if err != nil {
 			f.logger.Warn("Failed to cache compilation source file data", err)
 		}
 	}
+
+	// Ensure every contract method excluded through NatSpec annotations exists, now that every contract is known.
+	err = f.checkAnnotationExcludes()
+	if err != nil {
+		return err
+	}
+
+	// Storage layout cheat codes always revert without a storage layout, so fail early rather than fuzzing with them.
+	return f.checkStorageLayoutCheatCodes()
+}
+
+// checkAnnotationExcludes verifies that every contract method excluded through the NatSpec annotations of a method
+// refers to a method of a contract definition known to the Fuzzer.
+// Returns an error if an excluded contract method could not be resolved.
+func (f *Fuzzer) checkAnnotationExcludes() error {
+	for _, contractDefinition := range f.contractDefinitions {
+		for _, method := range contractDefinition.CompiledContract().Abi.Methods {
+			annotations := contractDefinition.MethodAnnotations(&method)
+			if annotations == nil {
+				continue
+			}
+			if unresolved := annotations.UnresolvedExclude(f.contractDefinitions); unresolved != "" {
+				return fmt.Errorf("method %v.%v is annotated with @custom:%v %v, which does not refer to a method of any known contract", contractDefinition.Name(), method.Sig, fuzzerTypes.AnnotationExclude, unresolved)
+			}
+		}
+	}
+	return nil
+}
+
+// checkStorageLayoutCheatCodes verifies that, if any contract calls a cheat code which relies on the storage layout of
+// the contract it targets, the compilation platform produced storage layouts for the contracts.
+// Returns an error if a contract calls such a cheat code but no storage layouts are available.
+func (f *Fuzzer) checkStorageLayoutCheatCodes() error {
+	if !f.config.Fuzzing.TestChainConfig.CheatCodeConfig.CheatCodesEnabled {
+		return nil
+	}
+
+	// If any contract has a storage layout, the platform outputs them, so the cheat codes can be used.
+	for _, contractDefinition := range f.contractDefinitions {
+		if contractDefinition.CompiledContract().StorageLayout != nil {
+			return nil
+		}
+	}
+
+	contractDefinition, cheatCodeSig := findStorageLayoutCheatCodeCall(f.contractDefinitions)
+	if contractDefinition == nil {
+		return nil
+	}
+	return fmt.Errorf("contract %v calls the %v cheat code, which requires a storage layout that the %v compilation platform does not output; use the solc compilation platform (solc 0.5.13 or newer) instead", contractDefinition.Name(), cheatCodeSig, f.config.Compilation.Platform)
+}
+
+// findStorageLayoutCheatCodeCall searches the bytecode of the provided contract definitions for calls to a cheat code
+// which relies on the storage layout of the contract it targets. Only the bytecode is searched, so contracts which
+// merely declare the cheat codes (e.g. in an interface) are not matched.
+// Returns the first contract definition found calling such a cheat code and the signature of the cheat code, or nil
+// if none were found.
+func findStorageLayoutCheatCodeCall(contractDefinitions fuzzerTypes.Contracts) (*fuzzerTypes.Contract, string) {
+	for _, contractDefinition := range contractDefinitions {
+		compiledContract := contractDefinition.CompiledContract()
+		for _, cheatCodeSig := range chain.StorageLayoutCheatCodeSignatures {
+			// Solidity pushes the selector of an external call with a PUSH4 instruction.
+			pushSelector := append([]byte{byte(vm.PUSH4)}, crypto.Keccak256([]byte(cheatCodeSig))[:4]...)
+			if bytes.Contains(compiledContract.InitBytecode, pushSelector) || bytes.Contains(compiledContract.RuntimeBytecode, pushSelector) {
+				return contractDefinition, cheatCodeSig
+			}
+		}
+	}
+	return nil, ""
+}
+
+// applyMethodAnnotations applies the provided per-method annotations, keyed by method signature, to the contract
+// definition, logging any methods which were skipped as a result.
+// Returns the annotated contract definition, or an error if an annotation could not be applied.
+func (f *Fuzzer) applyMethodAnnotations(contractDefinition *fuzzerTypes.Contract, annotations map[string]*fuzzerTypes.MethodAnnotations) (*fuzzerTypes.Contract, error) {
+	for methodSig, methodAnnotations := range annotations {
+		if methodAnnotations.Skip {
+			f.logger.Info("Skipping method ", colors.Bold, contractDefinition.Name(), ".", methodSig, colors.Reset, " due to its NatSpec annotations")
+		}
+
+		// Call sequences are never generated longer than the configured length, so a test cannot be annotated with a
+		// longer one.
+		if methodAnnotations.SequenceLength > f.config.Fuzzing.CallSequenceLength {
+			return nil, fmt.Errorf("method %v.%v is annotated with a call sequence length of %d, which exceeds the configured call sequence length of %d", contractDefinition.Name(), methodSig, methodAnnotations.SequenceLength, f.config.Fuzzing.CallSequenceLength)
+		}
+
+		// Optimization tests never fail, so they cannot be expected to.
+		if methodAnnotations.ExpectFail && slices.ContainsFunc(contractDefinition.OptimizationTestMethods, func(method abi.Method) bool { return method.Sig == methodSig }) {
+			return nil, fmt.Errorf("method %v.%v is an optimization test, which never fails, so it cannot be annotated with @custom:%v", contractDefinition.Name(), methodSig, fuzzerTypes.AnnotationExpectFail)
+		}
+	}
+	return contractDefinition.WithAnnotations(annotations), nil
+}
+
+// testCallSequenceLength returns the maximum length of call sequences evaluated against the test for the given contract
+// method. This is the configured call sequence length, unless the method was annotated with its own.
+func (f *Fuzzer) testCallSequenceLength(contract *fuzzerTypes.Contract, method *abi.Method) int {
+	annotations := contract.MethodAnnotations(method)
+	if annotations != nil && annotations.SequenceLength > 0 {
+		return annotations.SequenceLength
+	}
+	return f.config.Fuzzing.CallSequenceLength
+}
+
+// callSequenceAllowed indicates whether the provided call sequence may be evaluated against the test for the given
+// contract method. Call sequences longer than the test's call sequence length, or calling a contract method its
+// annotations excluded, are not evaluated against it.
+func (f *Fuzzer) callSequenceAllowed(contract *fuzzerTypes.Contract, method *abi.Method, callSequence calls.CallSequence) bool {
+	if len(callSequence) > f.testCallSequenceLength(contract, method) {
+		return false
+	}
+
+	// Check the call sequence does not call any excluded method.
+	annotations := contract.MethodAnnotations(method)
+	if annotations == nil || len(annotations.Exclude) == 0 {
+		return true
+	}
+	for _, callSequenceElement := range callSequence {
+		if callSequenceElement.Contract == nil {
+			continue
+		}
+		calledMethod, err := callSequenceElement.Method()
+		if err == nil && calledMethod != nil && annotations.Excludes(callSequenceElement.Contract, calledMethod) {
+			return false
+		}
+	}
+	return true
 }
 
 // createTestChain creates a test chain with the account balance allocations specified by the config.
@@ -422,9 +672,23 @@ func (f *Fuzzer) createTestChain() (*chain.TestChain, error) {
 
 	// Set our block gas limit
 	testChain.BlockGasLimit = f.config.Fuzzing.BlockGasLimit
+
+	// Set our storage layout resolver, so cheat codes can access storage variables of known contracts by name.
+	testChain.StorageLayoutResolver = f.resolveStorageLayout
 	return testChain, err
 }
 
+// resolveStorageLayout is a storage layout resolver used by test chains which matches the runtime bytecode of a
+// deployed contract against the contract definitions known to the fuzzer.
+// Returns the storage layout of the matched contract definition, or nil if no match or layout is known.
+func (f *Fuzzer) resolveStorageLayout(address common.Address, runtimeBytecode []byte) *compilationTypes.StorageLayout {
+	matchedDefinition := f.contractDefinitions.MatchBytecode(nil, runtimeBytecode)
+	if matchedDefinition == nil {
+		return nil
+	}
+	return matchedDefinition.CompiledContract().StorageLayout
+}
+
 // chainSetupFromCompilations is a TestChainSetupFunc which sets up the base test chain state by deploying
 // all compiled contract definitions. This includes any successful compilations as a result of the Fuzzer.config
 // definitions, as well as those added by Fuzzer.AddCompilationTargets. The contract deployment order is defined by
@@ -611,6 +875,12 @@ func defaultCallSequenceGeneratorConfigFunc(fuzzer *Fuzzer, valueSet *valuegener
 		RandomMutatedCorpusTailWeight:            10,
 		RandomMutatedSpliceAtRandomWeight:        20,
 		RandomMutatedInterleaveAtRandomWeight:    10,
+		RandomDeleteAtRandomWeight:               50,
+		RandomDuplicateAtRandomWeight:            50,
+		RandomSwapAtRandomWeight:                 50,
+		RandomReplaceSenderAtRandomWeight:        30,
+		RandomReplaceDelayAtRandomWeight:         30,
+		RandomCopyArgumentAtRandomWeight:         30,
 		ValueGenerator:                           mutationalGenerator,
 		ValueMutator:                             mutationalGenerator,
 	}
@@ -737,6 +1007,74 @@ func (f *Fuzzer) spawnWorkersLoop(baseTestChain *chain.TestChain) error {
 	return err
 }
 
+// createBaseTestChain creates a test chain and sets it up with the deployment/setup strategy defined by the fuzzer.
+// Returns the test chain, or an error if one occurred.
+func (f *Fuzzer) createBaseTestChain() (*chain.TestChain, error) {
+	// Create our test chain
+	baseTestChain, err := f.createTestChain()
+	if err != nil {
+		f.logger.Error("Failed to create the test chain", err)
+		return nil, err
+	}
+
+	// Set it up with our deployment/setup strategy defined by the fuzzer.
+	f.logger.Info("Setting up test chain")
+	trace, err := f.Hooks.ChainSetupFunc(f, baseTestChain)
+	if err != nil {
+		if trace != nil {
+			f.logger.Error("Failed to initialize the test chain", err, errors.New(trace.Log().ColorString()))
+		} else {
+			f.logger.Error("Failed to initialize the test chain", err)
+		}
+		return nil, err
+	}
+	f.logger.Info("Finished setting up test chain")
+	return baseTestChain, nil
+}
+
+// corpusConfig returns the corpus.CorpusConfig described by the fuzzing configuration.
+func (f *Fuzzer) corpusConfig() corpus.CorpusConfig {
+	return corpus.CorpusConfig{
+		Directory:                 f.config.Fuzzing.CorpusDirectory,
+		PowerSchedule:             corpus.PowerSchedule(f.config.Fuzzing.PowerSchedule),
+		CoverageMode:              coverage.CoverageMode(f.config.Fuzzing.CoverageMode),
+		StorageWriteBucketing:     coverage.StorageWriteBucketing(f.config.Fuzzing.StorageWriteCoverage),
+		ComparisonFeedbackEnabled: f.config.Fuzzing.ComparisonFeedbackEnabled,
+	}
+}
+
+// MinimizeCorpus minimizes the corpus in the configured corpus directory, keeping a minimal set of call sequences which
+// preserves the coverage achieved by the corpus on the post-setup test chain. Call sequences which are not kept are
+// moved to the provided archive directory.
+// Returns a corpus.MinimizationResult describing the outcome, or an error if one occurred.
+func (f *Fuzzer) MinimizeCorpus(archiveDirectory string) (*corpus.MinimizationResult, error) {
+	// Read the corpus
+	var err error
+	f.corpus, err = corpus.NewCorpus(f.corpusConfig())
+	if err != nil {
+		f.logger.Error("Failed to create the corpus", err)
+		return nil, err
+	}
+
+	// Create our test chain and set it up with our deployment/setup strategy.
+	baseTestChain, err := f.createBaseTestChain()
+	if err != nil {
+		return nil, err
+	}
+	defer baseTestChain.Close()
+
+	// Minimize the corpus.
+	f.logger.Info("Minimizing corpus")
+	startTime := time.Now()
+	result, err := f.corpus.Minimize(baseTestChain, f.contractDefinitions, archiveDirectory)
+	if err != nil {
+		f.logger.Error("Failed to minimize the corpus", err)
+		return nil, err
+	}
+	f.logger.Info("Finished minimizing corpus in ", time.Since(startTime).Round(time.Second))
+	return result, nil
+}
+
 // Start begins a fuzzing operation on the provided project configuration. This operation will not return until an error
 // is encountered or the fuzzing operation has completed. Its execution can be cancelled using the Stop method.
 // Returns an error if one is encountered.
@@ -744,8 +1082,14 @@ func (f *Fuzzer) Start() error {
 	// Define our variable to catch errors
 	var err error
 
-	// While we're fuzzing, we'll want to have an initialized random provider.
-	f.randomProvider = rand.New(rand.NewSource(time.Now().UnixNano()))
+	// While we're fuzzing, we'll want to have an initialized random provider. Every random provider used by the fuzzer
+	// is derived from it, so we seed it as configured (or randomly), and log the seed so the campaign can be reproduced.
+	seed := f.config.Fuzzing.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	f.logger.Info("Fuzzing with seed ", colors.Bold, seed, colors.Reset)
+	f.randomProvider = rand.New(rand.NewSource(seed))
 
 	// Create our running context (allows us to cancel across threads)
 	f.ctx, f.ctxCancelFunc = context.WithCancel(context.Background())
@@ -758,7 +1102,7 @@ func (f *Fuzzer) Start() error {
 
 	// Set up the corpus
 	f.logger.Info("Initializing corpus")
-	f.corpus, err = corpus.NewCorpus(f.config.Fuzzing.CorpusDirectory)
+	f.corpus, err = corpus.NewCorpus(f.corpusConfig())
 	if err != nil {
 		f.logger.Error("Failed to create the corpus", err)
 		return err
@@ -773,25 +1117,11 @@ func (f *Fuzzer) Start() error {
 	f.testCasesFinished = make(map[string]TestCase)
 	f.testCasesLock.Unlock()
 
-	// Create our test chain
-	baseTestChain, err := f.createTestChain()
-	if err != nil {
-		f.logger.Error("Failed to create the test chain", err)
-		return err
-	}
-
-	// Set it up with our deployment/setup strategy defined by the fuzzer.
-	f.logger.Info("Setting up test chain")
-	trace, err := f.Hooks.ChainSetupFunc(f, baseTestChain)
+	// Create our test chain and set it up with our deployment/setup strategy.
+	baseTestChain, err := f.createBaseTestChain()
 	if err != nil {
-		if trace != nil {
-			f.logger.Error("Failed to initialize the test chain", err, errors.New(trace.Log().ColorString()))
-		} else {
-			f.logger.Error("Failed to initialize the test chain", err)
-		}
 		return err
 	}
-	f.logger.Info("Finished setting up test chain")
 
 	// Initialize our coverage maps by measuring the coverage we get from the corpus.
 	var corpusActiveSequences, corpusTotalSequences int
@@ -799,7 +1129,7 @@ func (f *Fuzzer) Start() error {
 		f.logger.Info("Running call sequences in the corpus")
 	}
 	startTime := time.Now()
-	corpusActiveSequences, corpusTotalSequences, err = f.corpus.Initialize(baseTestChain, f.contractDefinitions)
+	corpusActiveSequences, corpusTotalSequences, err = f.corpus.Initialize(baseTestChain, f.contractDefinitions, randomutils.ForkRandomProvider(f.randomProvider))
 	if corpusTotalSequences > 0 {
 		f.logger.Info("Finished running call sequences in the corpus in ", time.Since(startTime).Round(time.Second))
 	}
@@ -832,9 +1162,9 @@ func (f *Fuzzer) Start() error {
 
 	// If StopOnNoTests is true and there are no test cases, then throw an error
 	if f.config.Fuzzing.Testing.StopOnNoTests && len(f.testCases) == 0 {
-		err = fmt.Errorf("no assertion, property, optimization, or custom tests were found to fuzz")
+		err = fmt.Errorf("no assertion, property, optimization, fuzz, or custom tests were found to fuzz")
 		if !f.config.Fuzzing.Testing.AssertionTesting.TestViewMethods {
-			err = fmt.Errorf("no assertion, property, optimization, or custom tests were found to fuzz and testing view methods is disabled")
+			err = fmt.Errorf("no assertion, property, optimization, fuzz, or custom tests were found to fuzz and testing view methods is disabled")
 		}
 		f.logger.Error("Failed to start fuzzer", err)
 		return err
@@ -868,6 +1198,16 @@ func (f *Fuzzer) Start() error {
 	// Print our results on exit.
 	f.printExitingResults()
 
+	// Write the outcomes of the calls made to each method to the corpus directory, if one is set.
+	if f.config.Fuzzing.CorpusDirectory != "" {
+		path, statsErr := writeMethodCallStats(f.metrics.MethodCallStats(), f.config.Fuzzing.CorpusDirectory)
+		if statsErr != nil {
+			f.logger.Error("Failed to write method call stats", statsErr)
+		} else {
+			f.logger.Info("Method call stats saved to: ", colors.Bold, path, colors.Reset)
+		}
+	}
+
 	// Finally, generate our coverage report if we have set a valid corpus directory.
 	if err == nil && len(f.config.Fuzzing.CoverageFormats) > 0 {
 		// Write to the default directory if we have no corpus directory set.
@@ -985,8 +1325,10 @@ func (f *Fuzzer) printExitingResults() {
 	testCaseDisplayOrder := map[TestCaseStatus]int{
 		TestCaseStatusNotStarted: 0,
 		TestCaseStatusPassed:     1,
-		TestCaseStatusFailed:     2,
-		TestCaseStatusRunning:    3,
+		TestCaseStatusErrored:    2,
+		TestCaseStatusFlaky:      3,
+		TestCaseStatusFailed:     4,
+		TestCaseStatusRunning:    5,
 	}
 
 	// Sort the test cases by status and then ID.
@@ -1004,8 +1346,10 @@ func (f *Fuzzer) printExitingResults() {
 
 	// Define variables to track our final test count.
 	var (
-		testCountPassed int
-		testCountFailed int
+		testCountPassed  int
+		testCountFailed  int
+		testCountErrored int
+		testCountFlaky   int
 	)
 
 	// Print the results of each individual test case.
@@ -1018,9 +1362,46 @@ func (f *Fuzzer) printExitingResults() {
 			testCountPassed++
 		} else if testCase.Status() == TestCaseStatusFailed {
 			testCountFailed++
+		} else if testCase.Status() == TestCaseStatusErrored {
+			testCountErrored++
+		} else if testCase.Status() == TestCaseStatusFlaky {
+			testCountFlaky++
 		}
 	}
 
 	// Print our final tally of test statuses.
-	f.logger.Info("Test summary: ", colors.GreenBold, testCountPassed, colors.Reset, " test(s) passed, ", colors.RedBold, testCountFailed, colors.Reset, " test(s) failed")
+	summary := []any{"Test summary: ", colors.GreenBold, testCountPassed, colors.Reset, " test(s) passed, ", colors.RedBold, testCountFailed, colors.Reset, " test(s) failed"}
+	if testCountErrored > 0 {
+		summary = append(summary, ", ", colors.YellowBold, testCountErrored, colors.Reset, " test(s) errored")
+	}
+	if testCountFlaky > 0 {
+		summary = append(summary, ", ", colors.YellowBold, testCountFlaky, colors.Reset, " test(s) flaky")
+	}
+	f.logger.Info(summary...)
+
+	// Print any methods which were called but never succeeded, as they likely indicate a broken harness.
+	neverSucceeded := utils.SliceWhere(f.metrics.MethodCallStats(), func(stats *MethodCallStats) bool {
+		return stats.Calls > 0 && stats.Successes == 0
+	})
+	if len(neverSucceeded) > 0 {
+		buffer := logging.NewLogBuffer()
+		buffer.Append(colors.YellowBold, "Methods which never succeeded:", colors.Reset)
+		for _, stats := range neverSucceeded {
+			buffer.Append("\n", colors.Bold, stats.Contract, ".", stats.Method, colors.Reset, fmt.Sprintf(": %d call(s), most commonly reverting with \"%s\"", stats.Calls, stats.mostCommonRevertReason()))
+		}
+		f.logger.Info(buffer.Elements()...)
+	}
+
+	// Print how often each handler method was not called because its precondition was not met.
+	preconditionRejections := f.metrics.PreconditionRejections()
+	if len(preconditionRejections) > 0 {
+		methods := maps.Keys(preconditionRejections)
+		sort.Strings(methods)
+		buffer := logging.NewLogBuffer()
+		buffer.Append(colors.Bold, "Precondition rejections:", colors.Reset)
+		for _, method := range methods {
+			buffer.Append("\n", colors.Bold, method, colors.Reset, fmt.Sprintf(": %d", preconditionRejections[method]))
+		}
+		f.logger.Info(buffer.Elements()...)
+	}
 }
@@ -30,6 +30,16 @@ type FuzzerHooks struct {
 	// CallSequenceTestFuncs describes a list of functions to be called upon by a FuzzerWorker after every call
 	// in a call sequence. These must not commit to state
 	CallSequenceTestFuncs []CallSequenceTestFunc
+
+	// CallSequenceEndTestFuncs describes a list of functions to be called upon by a FuzzerWorker once execution of a
+	// call sequence ends, against the state resulting from it. Execution ends either when every call in the sequence
+	// was executed, or when a shrink request cut it short. These must not commit to state.
+	CallSequenceEndTestFuncs []CallSequenceTestFunc
+
+	// StatelessTestFuncs describes a list of functions to be called upon by a FuzzerWorker after every call sequence
+	// it tests, to run tests which are executed in isolation from the testing base state, rather than as part of a
+	// call sequence. These must revert any state changes they make before returning.
+	StatelessTestFuncs []StatelessTestFunc
 }
 
 // NewShrinkingValueMutatorFunc describes the function used to set up a value mutator used to shrink call
@@ -55,15 +65,33 @@ type TestChainSetupFunc func(fuzzer *Fuzzer, testChain *chain.TestChain) (*execu
 // current call sequence from being further generated and tested.
 type CallSequenceTestFunc func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error)
 
+// StatelessTestFunc defines a method called by a fuzzing.FuzzerWorker to run a test in isolation from the testing
+// base state. It returns the call sequence which was tested alongside a ShrinkCallSequenceRequest set, which represents
+// a set of requests for the tested call sequence to be shrunk, alongside verifiers to guide the shrinking process.
+type StatelessTestFunc func(worker *FuzzerWorker) (calls.CallSequence, []ShrinkCallSequenceRequest, error)
+
 // ShrinkCallSequenceRequest is a structure signifying a request for a shrunken call sequence from the FuzzerWorker.
 type ShrinkCallSequenceRequest struct {
 	// VerifierFunction is a method is called upon by a FuzzerWorker to check if a shrunken call sequence satisfies
 	// the needs of an original method.
 	VerifierFunction func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error)
 	// FinishedCallback is a method called upon when the shrink request has concluded. It provides the finalized
-	// shrunken call sequence.
-	FinishedCallback func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error
+	// shrunken call sequence. If CheckReproducibility is set and the shrunken call sequence did not reproduce its
+	// result when replayed, flakiness describes how, so it can be provided when reporting failed test cases.
+	FinishedCallback func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error
 	// RecordResultInCorpus indicates whether the shrunken call sequence should be recorded in the corpus. If so, when
 	// the shrinking operation is completed, the sequence will be added to the corpus if it doesn't already exist.
 	RecordResultInCorpus bool
+	// ExcludeFromCorpus indicates whether call sequences tested while shrinking should be excluded from the coverage
+	// checks which add call sequences to the corpus. This is used for call sequences which should never be mutated
+	// or replayed as part of other call sequences.
+	ExcludeFromCorpus bool
+	// BisectBeforeShrinking indicates whether the shortest prefix of the call sequence which satisfies the
+	// VerifierFunction should be located by bisection before shrinking. This is used when a test is not checked after
+	// every call, so a failure may be detected many calls after the call which caused it.
+	BisectBeforeShrinking bool
+	// CheckReproducibility indicates whether the shrunken call sequence should be replayed on a fresh clone of the base
+	// chain before the FinishedCallback is called, to verify it still satisfies the VerifierFunction. Failed test cases
+	// reported from the FinishedCallback are flagged as flaky if it does not.
+	CheckReproducibility bool
 }
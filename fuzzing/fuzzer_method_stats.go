@@ -0,0 +1,196 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/crytic/medusa/compilation/abiutils"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// MethodCallStats describes the outcomes of the calls the fuzzer made to a single contracts.DeployedContractMethod.
+type MethodCallStats struct {
+	// Address describes the address of the deployed contract which was called.
+	Address common.Address `json:"address"`
+	// Contract describes the name of the contract which was called.
+	Contract string `json:"contract"`
+	// Method describes the signature of the method which was called.
+	Method string `json:"method"`
+	// Calls describes the amount of calls made to the method.
+	Calls uint64 `json:"calls"`
+	// Successes describes the amount of calls made to the method which did not revert.
+	Successes uint64 `json:"successes"`
+	// Reverts describes the amount of calls made to the method which reverted.
+	Reverts uint64 `json:"reverts"`
+	// RevertReasons describes the amount of reverts for each decoded revert reason.
+	RevertReasons map[string]uint64 `json:"revertReasons"`
+}
+
+// methodCallStatsKey describes a key used to index MethodCallStats for a single deployed contract method.
+type methodCallStatsKey struct {
+	// address describes the address of the deployed contract.
+	address common.Address
+	// methodId describes the contract and method definition.
+	methodId contracts.ContractMethodID
+}
+
+// methodCallStatsTracker records MethodCallStats for every deployed contract method called by a FuzzerWorker.
+type methodCallStatsTracker struct {
+	// stats describes the MethodCallStats recorded for each deployed contract method.
+	stats map[methodCallStatsKey]*MethodCallStats
+	// statsLock is used for thread-synchronization when updating stats.
+	statsLock sync.Mutex
+}
+
+// newMethodCallStatsTracker returns a new methodCallStatsTracker with no recorded stats.
+func newMethodCallStatsTracker() *methodCallStatsTracker {
+	return &methodCallStatsTracker{
+		stats: make(map[methodCallStatsKey]*MethodCallStats),
+	}
+}
+
+// recordCall records the outcome of the provided call sequence element, which must have been executed.
+func (t *methodCallStatsTracker) recordCall(element *calls.CallSequenceElement) {
+	// We can only attribute calls made through the ABI to a contract method.
+	if element.Contract == nil || element.Call.To == nil || element.Call.DataAbiValues == nil || element.ChainReference == nil {
+		return
+	}
+	method := element.Call.DataAbiValues.Method
+	executionResult := element.ChainReference.MessageResults().ExecutionResult
+
+	t.statsLock.Lock()
+	defer t.statsLock.Unlock()
+
+	// Obtain the stats for this method, creating them if this is its first call.
+	key := methodCallStatsKey{address: *element.Call.To, methodId: contracts.GetContractMethodID(element.Contract, method)}
+	stats, ok := t.stats[key]
+	if !ok {
+		stats = &MethodCallStats{
+			Address:       *element.Call.To,
+			Contract:      element.Contract.Name(),
+			Method:        method.Sig,
+			RevertReasons: make(map[string]uint64),
+		}
+		t.stats[key] = stats
+	}
+
+	// Record the outcome of the call.
+	stats.Calls++
+	if executionResult.Failed() {
+		stats.Reverts++
+		stats.RevertReasons[describeRevertReason(element.Contract, executionResult)]++
+	} else {
+		stats.Successes++
+	}
+}
+
+// describeRevertReason obtains a string describing why the provided execution result reverted: a panic reason, an
+// error string, or the signature of a custom error defined by the called contract. Custom error arguments are omitted
+// so that reverts with the same cause are grouped together.
+func describeRevertReason(contract *contracts.Contract, executionResult *core.ExecutionResult) string {
+	if panicCode := abiutils.GetSolidityPanicCode(executionResult.Err, executionResult.ReturnData, false); panicCode != nil {
+		return abiutils.GetPanicReason(panicCode.Uint64())
+	}
+	if errorMessage := abiutils.GetSolidityRevertErrorString(executionResult.Err, executionResult.ReturnData); errorMessage != nil {
+		return fmt.Sprintf("error: %v", *errorMessage)
+	}
+	if customError, _ := abiutils.GetSolidityCustomRevertError(&contract.CompiledContract().Abi, executionResult.Err, executionResult.ReturnData); customError != nil {
+		return fmt.Sprintf("custom error: %v", customError.Sig)
+	}
+	if errors.Is(executionResult.Err, vm.ErrExecutionReverted) {
+		if len(executionResult.ReturnData) == 0 {
+			return "revert: no reason"
+		}
+		return fmt.Sprintf("revert: unresolved selector 0x%x", executionResult.ReturnData[:utils.Min(4, len(executionResult.ReturnData))])
+	}
+	return executionResult.Err.Error()
+}
+
+// MethodCallStats returns the MethodCallStats recorded across all workers, merged for each deployed contract method
+// and sorted by contract name, method signature, and address.
+func (m *FuzzerMetrics) MethodCallStats() []*MethodCallStats {
+	// Merge the stats recorded by each worker.
+	merged := make(map[methodCallStatsKey]*MethodCallStats)
+	for _, workerMetrics := range m.workerMetrics {
+		workerMetrics.methodCallStats.statsLock.Lock()
+		for key, stats := range workerMetrics.methodCallStats.stats {
+			mergedStats, ok := merged[key]
+			if !ok {
+				mergedStats = &MethodCallStats{
+					Address:       stats.Address,
+					Contract:      stats.Contract,
+					Method:        stats.Method,
+					RevertReasons: make(map[string]uint64),
+				}
+				merged[key] = mergedStats
+			}
+			mergedStats.Calls += stats.Calls
+			mergedStats.Successes += stats.Successes
+			mergedStats.Reverts += stats.Reverts
+			for reason, count := range stats.RevertReasons {
+				mergedStats.RevertReasons[reason] += count
+			}
+		}
+		workerMetrics.methodCallStats.statsLock.Unlock()
+	}
+
+	// Sort the merged stats so they are reported deterministically.
+	sortedStats := make([]*MethodCallStats, 0, len(merged))
+	for _, stats := range merged {
+		sortedStats = append(sortedStats, stats)
+	}
+	sort.Slice(sortedStats, func(i, j int) bool {
+		if sortedStats[i].Contract != sortedStats[j].Contract {
+			return sortedStats[i].Contract < sortedStats[j].Contract
+		}
+		if sortedStats[i].Method != sortedStats[j].Method {
+			return sortedStats[i].Method < sortedStats[j].Method
+		}
+		return sortedStats[i].Address.Cmp(sortedStats[j].Address) < 0
+	})
+	return sortedStats
+}
+
+// mostCommonRevertReason obtains the revert reason recorded most often for the method, or an empty string if it never
+// reverted.
+func (s *MethodCallStats) mostCommonRevertReason() string {
+	var mostCommonReason string
+	for reason, count := range s.RevertReasons {
+		if count > s.RevertReasons[mostCommonReason] || (count == s.RevertReasons[mostCommonReason] && reason < mostCommonReason) {
+			mostCommonReason = reason
+		}
+	}
+	return mostCommonReason
+}
+
+// writeMethodCallStats writes the provided MethodCallStats to a JSON file in the provided directory.
+// Returns the path of the file written, or an error if one occurred.
+func writeMethodCallStats(stats []*MethodCallStats, directory string) (string, error) {
+	// If the directory doesn't exist, create it.
+	err := utils.MakeDirectory(directory)
+	if err != nil {
+		return "", err
+	}
+
+	// Write the stats to a file.
+	b, err := json.MarshalIndent(stats, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	statsPath := filepath.Join(directory, "method_call_stats.json")
+	err = os.WriteFile(statsPath, b, 0644)
+	if err != nil {
+		return "", fmt.Errorf("could not export method call stats: %v", err)
+	}
+	return statsPath, nil
+}
@@ -1,6 +1,9 @@
 package fuzzing
 
-import "math/big"
+import (
+	"math/big"
+	"sync"
+)
 
 // FuzzerMetrics represents a struct tracking metrics for a Fuzzer run.
 type FuzzerMetrics struct {
@@ -28,6 +31,16 @@ type fuzzerWorkerMetrics struct {
 
 	// shrinking indicates whether the fuzzer worker is currently shrinking.
 	shrinking bool
+
+	// preconditionRejections is the amount of times a handler method was not called because its precondition was not
+	// met, keyed by "Contract.method(signature)".
+	preconditionRejections map[string]uint64
+
+	// preconditionRejectionsLock is used for thread-synchronization when updating preconditionRejections.
+	preconditionRejectionsLock *sync.Mutex
+
+	// methodCallStats records the outcomes of the calls made to each deployed contract method.
+	methodCallStats *methodCallStatsTracker
 }
 
 // newFuzzerMetrics obtains a new FuzzerMetrics struct for a given number of workers specified by workerCount.
@@ -43,6 +56,9 @@ func newFuzzerMetrics(workerCount int) *FuzzerMetrics {
 		metrics.workerMetrics[i].callsTested = big.NewInt(0)
 		metrics.workerMetrics[i].workerStartupCount = big.NewInt(0)
 		metrics.workerMetrics[i].gasUsed = big.NewInt(0)
+		metrics.workerMetrics[i].preconditionRejections = make(map[string]uint64)
+		metrics.workerMetrics[i].preconditionRejectionsLock = &sync.Mutex{}
+		metrics.workerMetrics[i].methodCallStats = newMethodCallStatsTracker()
 	}
 	return &metrics
 }
@@ -82,6 +98,20 @@ func (m *FuzzerMetrics) GasUsed() *big.Int {
 	return gasUsed
 }
 
+// PreconditionRejections returns the amount of times each handler method was not called because its precondition was
+// not met, keyed by "Contract.method(signature)".
+func (m *FuzzerMetrics) PreconditionRejections() map[string]uint64 {
+	preconditionRejections := make(map[string]uint64)
+	for _, workerMetrics := range m.workerMetrics {
+		workerMetrics.preconditionRejectionsLock.Lock()
+		for method, count := range workerMetrics.preconditionRejections {
+			preconditionRejections[method] += count
+		}
+		workerMetrics.preconditionRejectionsLock.Unlock()
+	}
+	return preconditionRejections
+}
+
 // WorkerStartupCount describes the amount of times the worker was spawned for this index. Workers are periodically
 // reset.
 func (m *FuzzerMetrics) WorkerStartupCount() *big.Int {
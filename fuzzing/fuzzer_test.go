@@ -4,19 +4,30 @@ import (
 	"encoding/hex"
 	"math/big"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/crytic/medusa/fuzzing/executiontracer"
 
 	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/compilation"
+	"github.com/crytic/medusa/compilation/platforms"
+	compilationTypes "github.com/crytic/medusa/compilation/types"
 	"github.com/crytic/medusa/events"
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/valuegeneration"
 	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -153,6 +164,275 @@ func TestAssertionMode(t *testing.T) {
 	}
 }
 
+// TestAssertionFailureEvents runs a test to ensure the emission of a configured event, including from a nested call
+// frame, is treated as an assertion failure, with the decoded event reported as the failure reason.
+func TestAssertionFailureEvents(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/assertions/assert_event.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Testing.AssertionTesting.FailOnEvents = []string{"AssertionFailed(string, uint256)"}
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for failed assertion tests, which should report the decoded event.
+			assertFailedTestsExpected(f, true)
+			for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+				assert.Contains(t, testCase.Message(), "AssertionFailed(\"odd value\"")
+			}
+		},
+	})
+}
+
+// TestAssertionFailureCustomErrors runs a test to ensure a revert with a configured custom error is treated as an
+// assertion failure, with the decoded custom error reported as the failure reason.
+func TestAssertionFailureCustomErrors(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/assertions/assert_custom_error.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.Testing.AssertionTesting.FailOnCustomErrors = []string{"InvariantViolated(uint256)"}
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that only the method reverting with the configured custom error failed.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, 1)
+			for _, testCase := range failedTestCases {
+				assert.Contains(t, testCase.Name(), "checkValue")
+				assert.Contains(t, testCase.Message(), "InvariantViolated(")
+			}
+		},
+	})
+}
+
+// TestDifferentialTesting runs a test to ensure a divergence in behavior between a reference and candidate contract is
+// reported as a failed differential test case, with the execution traces of both sides.
+func TestDifferentialTesting(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/differential/differential_average.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"ReferenceAverage", "CandidateAverage"}
+			projectConfig.Fuzzing.TestLimit = 10_000
+			projectConfig.Fuzzing.Testing.DifferentialTesting.Enabled = true
+			projectConfig.Fuzzing.Testing.DifferentialTesting.Pairs = []config.DifferentialTestPairConfig{
+				{
+					Reference:    "ReferenceAverage",
+					Candidate:    "CandidateAverage",
+					StorageSlots: []string{"0x0"},
+				},
+			}
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that the differential test failed and reported both execution traces.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, 1)
+			for _, testCase := range failedTestCases {
+				assert.Contains(t, testCase.Name(), "ReferenceAverage vs CandidateAverage")
+				assert.Contains(t, testCase.Message(), "revert status differs")
+				assert.Contains(t, testCase.Message(), "[Reference Execution Trace]")
+				assert.Contains(t, testCase.Message(), "[Candidate Execution Trace]")
+			}
+		},
+	})
+}
+
+// TestDifferentialTestingStateful runs a test to ensure equivalent implementations whose storage evolves across calls
+// are not reported as diverging, as every call to the reference is mirrored onto the candidate and both are executed
+// from the state prior to the call.
+func TestDifferentialTestingStateful(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/differential/differential_stateful_counter.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"ReferenceCounter", "CandidateCounter"}
+			projectConfig.Fuzzing.TestLimit = 5_000
+			projectConfig.Fuzzing.Testing.DifferentialTesting.Enabled = true
+			projectConfig.Fuzzing.Testing.DifferentialTesting.Pairs = []config.DifferentialTestPairConfig{
+				{
+					Reference:    "ReferenceCounter",
+					Candidate:    "CandidateCounter",
+					StorageSlots: []string{"0x0"},
+				},
+			}
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that the differential test passed.
+			assert.Empty(t, f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed))
+			assert.Len(t, f.fuzzer.TestCasesWithStatus(TestCaseStatusPassed), 1)
+		},
+	})
+}
+
+// TestDifferentialTestingMethodPair runs a test to ensure equivalent methods of stateful contracts which are both
+// fuzzing targets are not reported as diverging, as the candidate is only called through calls mirrored from the
+// reference, and calls to the reference which cannot be mirrored stop the pair from being compared.
+func TestDifferentialTestingMethodPair(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/differential/differential_method_pair.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"ReferenceLedger", "CandidateLedger"}
+			projectConfig.Fuzzing.TestLimit = 5_000
+			projectConfig.Fuzzing.Testing.DifferentialTesting.Enabled = true
+			projectConfig.Fuzzing.Testing.DifferentialTesting.Pairs = []config.DifferentialTestPairConfig{
+				{
+					Reference:    "ReferenceLedger.add(uint256)",
+					Candidate:    "CandidateLedger.addBounded(uint256)",
+					StorageSlots: []string{"0x0"},
+				},
+			}
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that the differential test passed.
+			assert.Empty(t, f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed))
+			assert.Len(t, f.fuzzer.TestCasesWithStatus(TestCaseStatusPassed), 1)
+		},
+	})
+}
+
+// TestDrainTesting runs a test to ensure senders which gain more ETH or tokens than the configured profit threshold
+// are reported as failed drain test cases.
+func TestDrainTesting(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/drain/drain_assets.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"TestVault", "TestToken"}
+			projectConfig.Fuzzing.TargetContractsBalances = []*big.Int{big.NewInt(1e18), big.NewInt(0)}
+			projectConfig.Fuzzing.TestLimit = 10_000
+			projectConfig.Fuzzing.Testing.StopOnFailedTest = false
+			projectConfig.Fuzzing.Testing.DrainTesting.Enabled = true
+			projectConfig.Fuzzing.Testing.DrainTesting.TokenContracts = []string{"TestToken"}
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that both the ETH and token drain tests failed.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, 2)
+			for _, testCase := range failedTestCases {
+				drainTestCase, ok := testCase.(*DrainTestCase)
+				assert.True(t, ok)
+				assert.Positive(t, drainTestCase.Profit().Sign())
+				assert.Contains(t, testCase.Message(), "gained")
+			}
+		},
+	})
+}
+
+// TestDetectorTesting runs a test to ensure each trace-based detector reports the vulnerable pattern it targets, with
+// the offending call frame highlighted in the execution trace.
+func TestDetectorTesting(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/detectors/vulnerable_patterns.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"TestContract", "Recipient"}
+			projectConfig.Fuzzing.TestLimit = 10_000
+			projectConfig.Fuzzing.Testing.StopOnFailedTest = false
+			projectConfig.Fuzzing.Testing.DetectorTesting.Enabled = true
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that every detector reported its finding.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, 3)
+			for _, testCase := range failedTestCases {
+				assert.Contains(t, testCase.Message(), "[highlighted]")
+			}
+		},
+	})
+}
+
+// TestGasTesting runs a test to ensure the fuzzer reports a method whose gas usage grows unbounded past the configured
+// threshold, while methods with bounded gas usage pass.
+func TestGasTesting(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/gas/unbounded_loop.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			projectConfig.Fuzzing.TestLimit = 10_000
+			projectConfig.Fuzzing.Testing.StopOnFailedTest = false
+			projectConfig.Fuzzing.Testing.GasTesting.Enabled = true
+			projectConfig.Fuzzing.Testing.GasTesting.GasThreshold = "150000"
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that only the unbounded loop exceeded the threshold.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, 1)
+			for _, testCase := range failedTestCases {
+				gasTestCase, ok := testCase.(*GasTestCase)
+				assert.True(t, ok)
+				assert.Greater(t, gasTestCase.GasUsed(), uint64(150000))
+				assert.Contains(t, testCase.Name(), "incrementAll()")
+			}
+
+			// Check that the bounded method passed and reported the maximum gas it used.
+			passedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusPassed)
+			assert.Len(t, passedTestCases, 1)
+			for _, testCase := range passedTestCases {
+				assert.Contains(t, testCase.Message(), "used a maximum of")
+			}
+		},
+	})
+}
+
 // TestAssertionsNotRequire runs a test to ensure require and revert statements are not mistaken for assert statements.
 // It runs tests against a contract which immediately makes these statements and expects to find no errors before
 // timing out.
@@ -200,6 +480,364 @@ func TestAssertionsAndProperties(t *testing.T) {
 	})
 }
 
+// TestPropertyRevertTests runs a test to ensure property tests which are expected to revert fail when they do not,
+// and that property tests which revert unexpectedly are reported as errored when configured to do so.
+func TestPropertyRevertTests(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/property_tests/revert_property_test.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.PropertyTesting.RevertTestPrefixes = []string{"revert_"}
+			config.Fuzzing.Testing.PropertyTesting.ErrorOnRevert = true
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that each test resulted in the expected status.
+			expectedStatuses := map[string]TestCaseStatus{
+				"revert_xIsNeverTen":       TestCaseStatusFailed,
+				"revert_alwaysReverts":     TestCaseStatusPassed,
+				"property_revertsOnTwenty": TestCaseStatusErrored,
+			}
+			for _, testCase := range f.fuzzer.TestCases() {
+				propertyTestCase, ok := testCase.(*PropertyTestCase)
+				assert.True(t, ok)
+				assert.EqualValues(t, expectedStatuses[propertyTestCase.targetMethod.Name], propertyTestCase.Status())
+			}
+		},
+	})
+}
+
+// TestPropertyCheckFrequency runs a test to ensure property tests checked every N calls, or only at the end of a call
+// sequence, still detect failures and report a call sequence bisected and shrunk to the call which caused them.
+func TestPropertyCheckFrequency(t *testing.T) {
+	checkFrequencies := []int{0, 3}
+	for _, checkFrequency := range checkFrequencies {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/property_tests/check_frequency.sol",
+			configUpdates: func(projectConfig *config.ProjectConfig) {
+				projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+				projectConfig.Fuzzing.TestLimit = 10_000
+				projectConfig.Fuzzing.Testing.PropertyTesting.CheckFrequency = checkFrequency
+				projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+				projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				projectConfig.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// Check that the property test failed with the single call required to fail it.
+				failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+				assert.Len(t, failedTestCases, 1)
+				for _, testCase := range failedTestCases {
+					assert.Len(t, *testCase.CallSequence(), 1)
+				}
+			},
+		})
+	}
+}
+
+// TestReproducibilityChecks runs a test to ensure failures which do not reproduce when replayed on a fresh chain are
+// reported as flaky rather than failed.
+func TestReproducibilityChecks(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/property_tests/check_frequency.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			projectConfig.Fuzzing.TestLimit = 10_000
+			projectConfig.Fuzzing.Testing.ReproducibilityChecks = 3
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Wrap the test functions so the property test only fails on the chain of the worker which found the
+			// failure, simulating a failure which depends on worker state.
+			for i, callSequenceTestFunc := range f.fuzzer.Hooks.CallSequenceTestFuncs {
+				callSequenceTestFunc := callSequenceTestFunc
+				f.fuzzer.Hooks.CallSequenceTestFuncs[i] = func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+					shrinkRequests, err := callSequenceTestFunc(worker, callSequence)
+					workerChain := worker.Chain()
+					for j := range shrinkRequests {
+						verifierFunc := shrinkRequests[j].VerifierFunction
+						shrinkRequests[j].VerifierFunction = func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+							if worker.Chain() != workerChain {
+								return false, nil
+							}
+							return verifierFunc(worker, callSequence)
+						}
+					}
+					return shrinkRequests, err
+				}
+			}
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that the failure was reported as flaky rather than failed.
+			assert.Empty(t, f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed))
+			flakyTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFlaky)
+			assert.Len(t, flakyTestCases, 1)
+			for _, testCase := range flakyTestCases {
+				flakyTestCase, ok := testCase.(*FlakyTestCase)
+				assert.True(t, ok)
+				assert.EqualValues(t, TestCaseStatusFailed, flakyTestCase.OriginalTestCase().Status())
+				assert.Contains(t, testCase.Message(), "did not reproduce")
+			}
+		},
+	})
+}
+
+// TestReportTestCaseFinishedFlaky ensures failed and errored test cases are reported as flaky when their results did
+// not reproduce, while passed test cases are reported as they are.
+func TestReportTestCaseFinishedFlaky(t *testing.T) {
+	for _, status := range []TestCaseStatus{TestCaseStatusFailed, TestCaseStatusErrored, TestCaseStatusPassed} {
+		// Create a minimal fuzzer with a single registered test case of the given status.
+		testCase := &PropertyTestCase{
+			status:         status,
+			targetContract: fuzzerTypes.NewContract("TestContract", "", &compilationTypes.CompiledContract{}, nil),
+			targetMethod:   abi.NewMethod("property", "property", abi.Function, "view", true, false, nil, nil),
+		}
+		fuzzer := &Fuzzer{
+			testCases:         []TestCase{testCase},
+			testCasesFinished: make(map[string]TestCase),
+		}
+		fuzzer.config.Fuzzing.Testing.StopOnFailedTest = true
+
+		// Report the test case finished with flakiness, and check it was only reclassified if it failed or errored.
+		worker := &FuzzerWorker{fuzzer: fuzzer}
+		worker.reportTestCaseFinished(testCase, &CallSequenceFlakiness{replay: 1, divergedCallIndex: -1})
+		if status == TestCaseStatusPassed {
+			assert.Len(t, fuzzer.TestCasesWithStatus(TestCaseStatusPassed), 1)
+			continue
+		}
+		flakyTestCases := fuzzer.TestCasesWithStatus(TestCaseStatusFlaky)
+		assert.Len(t, flakyTestCases, 1)
+		assert.Empty(t, fuzzer.TestCasesWithStatus(status))
+		for _, flakyTestCase := range flakyTestCases {
+			assert.EqualValues(t, status, flakyTestCase.(*FlakyTestCase).OriginalTestCase().Status())
+		}
+	}
+}
+
+// TestNatSpecAnnotations runs a test to ensure per-test configuration provided through NatSpec annotations is applied
+// to the tests and handler functions they annotate.
+func TestNatSpecAnnotations(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/annotations/annotations.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			projectConfig.Fuzzing.TestLimit = 10_000
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that the skipped property test was not registered, and no test failed: the skipped handler is
+			// never called, the excluded handler is never evaluated, and the expected failure is reported as a pass.
+			assert.Len(t, f.fuzzer.TestCases(), 3)
+			assert.Empty(t, f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed))
+			for _, testCase := range f.fuzzer.TestCases() {
+				assert.NotContains(t, testCase.Name(), "property_skipped")
+				if strings.Contains(testCase.Name(), "property_expectedToFail") {
+					annotatedTestCase, ok := testCase.(*AnnotatedTestCase)
+					assert.True(t, ok)
+					assert.True(t, annotatedTestCase.Annotations().ExpectFail)
+					assert.EqualValues(t, TestCaseStatusFailed, annotatedTestCase.OriginalTestCase().Status())
+					assert.Contains(t, testCase.Message(), "@custom:medusa-expect-fail")
+				}
+			}
+		},
+	})
+}
+
+// TestNatSpecAnnotationValidation ensures NatSpec annotations which exclude contract methods that do not exist, or which
+// expect an optimization test to fail, are rejected.
+func TestNatSpecAnnotationValidation(t *testing.T) {
+	// Create a contract with a handler and an optimization test.
+	handler := abi.NewMethod("handler", "handler", abi.Function, "nonpayable", false, false, nil, nil)
+	optimization := abi.NewMethod("optimize_value", "optimize_value", abi.Function, "view", true, false, nil, nil)
+	newContract := func() *fuzzerTypes.Contract {
+		contract := fuzzerTypes.NewContract("TestContract", "", &compilationTypes.CompiledContract{
+			Abi: abi.ABI{Methods: map[string]abi.Method{handler.Name: handler, optimization.Name: optimization}},
+		}, nil)
+		contract.OptimizationTestMethods = []abi.Method{optimization}
+		return contract
+	}
+	fuzzer := &Fuzzer{}
+	fuzzer.config.Fuzzing.CallSequenceLength = 100
+
+	// Check excluded methods referenced by name or signature are resolved, while ones which do not exist are not.
+	for _, testCase := range []struct {
+		exclude []string
+		valid   bool
+	}{
+		{exclude: []string{"TestContract.handler"}, valid: true},
+		{exclude: []string{"TestContract.handler()", "TestContract.optimize_value"}, valid: true},
+		{exclude: []string{"TestContract.handler", "TestContract.missing"}, valid: false},
+		{exclude: []string{"OtherContract.handler"}, valid: false},
+	} {
+		contract, err := fuzzer.applyMethodAnnotations(newContract(), map[string]*fuzzerTypes.MethodAnnotations{
+			handler.Sig: {Exclude: testCase.exclude},
+		})
+		assert.NoError(t, err)
+		fuzzer.contractDefinitions = fuzzerTypes.Contracts{contract}
+		err = fuzzer.checkAnnotationExcludes()
+		assert.Equal(t, testCase.valid, err == nil, testCase.exclude)
+	}
+
+	// Check a handler may be expected to fail, but an optimization test may not.
+	_, err := fuzzer.applyMethodAnnotations(newContract(), map[string]*fuzzerTypes.MethodAnnotations{
+		handler.Sig: {ExpectFail: true},
+	})
+	assert.NoError(t, err)
+	_, err = fuzzer.applyMethodAnnotations(newContract(), map[string]*fuzzerTypes.MethodAnnotations{
+		optimization.Sig: {ExpectFail: true},
+	})
+	assert.Error(t, err)
+}
+
+// TestPreconditions runs a test to ensure handler methods are only called when their preconditions are met, and
+// precondition methods are never called as part of a call sequence.
+func TestPreconditions(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/preconditions/preconditions.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			projectConfig.Fuzzing.TestLimit = 10_000
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = true
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Check the precondition method was paired with its handler rather than treated as a method to fuzz.
+			for _, contract := range f.fuzzer.ContractDefinitions() {
+				for _, method := range contract.AssertionTestMethods {
+					assert.NotEqual(t, "deposit_precondition", method.Name)
+					if method.Name == "deposit" {
+						assert.NotNil(t, contract.PreconditionMethod(&method))
+					}
+				}
+			}
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check the handler was never called while its precondition was not met, and that rejections were recorded.
+			assert.Empty(t, f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed))
+			assert.Greater(t, f.fuzzer.metrics.PreconditionRejections()["TestContract.deposit(uint256)"], uint64(0))
+		},
+	})
+}
+
+// TestMethodCallStats runs a test to ensure the outcomes of calls made to each method are recorded, alongside their
+// decoded revert reasons, and written to the corpus directory.
+func TestMethodCallStats(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/method_stats/always_reverts.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			projectConfig.Fuzzing.TestLimit = 1_000
+			projectConfig.Fuzzing.CorpusDirectory = "corpus"
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check that the calls made to each method were recorded with their revert reasons.
+			stats := f.fuzzer.metrics.MethodCallStats()
+			assert.Len(t, stats, 3)
+			for _, methodStats := range stats {
+				assert.Greater(t, methodStats.Calls, uint64(0))
+				assert.EqualValues(t, methodStats.Calls, methodStats.Successes+methodStats.Reverts)
+				switch methodStats.Method {
+				case "setX(uint256)":
+					assert.EqualValues(t, 0, methodStats.Reverts)
+				case "alwaysReverts()":
+					assert.EqualValues(t, 0, methodStats.Successes)
+					assert.EqualValues(t, methodStats.Reverts, methodStats.RevertReasons["error: always reverts"])
+				case "alwaysRevertsWithCustomError()":
+					assert.EqualValues(t, 0, methodStats.Successes)
+					assert.EqualValues(t, methodStats.Reverts, methodStats.RevertReasons["custom error: Unauthorized(address)"])
+				}
+			}
+
+			// Check that the stats were written to the corpus directory.
+			assert.FileExists(t, filepath.Join("corpus", "method_call_stats.json"))
+		},
+	})
+}
+
+// TestDeterministicSeed runs the same single-worker campaign twice with a fixed seed, ensuring the fuzzer generates the
+// same calls in both runs.
+func TestDeterministicSeed(t *testing.T) {
+	runs := make([][]*MethodCallStats, 0)
+	corpora := make([][]string, 0)
+	for i := 0; i < 2; i++ {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/method_stats/always_reverts.sol",
+			configUpdates: func(projectConfig *config.ProjectConfig) {
+				projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+				projectConfig.Fuzzing.Workers = 1
+				projectConfig.Fuzzing.TestLimit = 500
+				projectConfig.Fuzzing.Seed = 1234
+				projectConfig.Fuzzing.CorpusDirectory = "corpus"
+				projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+				projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				projectConfig.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// Record the calls made in this run.
+				runs = append(runs, f.fuzzer.metrics.MethodCallStats())
+
+				// Record the call sequences this run added to the corpus. Corpus file names are not deterministic, so
+				// we sort their contents instead.
+				corpusFiles, err := filepath.Glob(filepath.Join("corpus", "call_sequences", "*.json"))
+				assert.NoError(t, err)
+				callSequences := make([]string, 0, len(corpusFiles))
+				for _, corpusFile := range corpusFiles {
+					callSequence, err := os.ReadFile(corpusFile)
+					assert.NoError(t, err)
+					callSequences = append(callSequences, string(callSequence))
+				}
+				sort.Strings(callSequences)
+				corpora = append(corpora, callSequences)
+			},
+		})
+	}
+
+	// Check that both runs made the same calls, and recorded the same call sequences in the corpus.
+	assert.Len(t, runs, 2)
+	assert.EqualValues(t, runs[0], runs[1])
+	assert.Len(t, corpora, 2)
+	assert.NotEmpty(t, corpora[0])
+	assert.EqualValues(t, corpora[0], corpora[1])
+}
+
 // TestOptimizationMode runs a test to ensure that optimization mode works as expected
 func TestOptimizationMode(t *testing.T) {
 	filePaths := []string{
@@ -220,11 +858,14 @@ func TestOptimizationMode(t *testing.T) {
 				err := f.fuzzer.Start()
 				assert.NoError(t, err)
 
-				// Check the value found for optimization test
+				// Check the value found for optimization test, and that the call sequence which produced it was shrunk
+				// to the single call required, with an execution trace attached.
 				var testCases = f.fuzzer.TestCasesWithStatus(TestCaseStatusPassed)
 				for _, testCase := range testCases {
 					if optimizationTestCase, ok := testCase.(*OptimizationTestCase); ok {
 						assert.EqualValues(t, optimizationTestCase.Value().Cmp(big.NewInt(4241)), 0)
+						assert.Len(t, *optimizationTestCase.CallSequence(), 1)
+						assert.Contains(t, optimizationTestCase.Message(), "[Optimization Test Execution Trace]")
 					}
 				}
 			},
@@ -232,6 +873,82 @@ func TestOptimizationMode(t *testing.T) {
 	}
 }
 
+// TestOptimizationModeMultiObjective runs a test to ensure that minimization tests and tests returning several values
+// find the best value for each objective, and record a timeline of its improvements.
+func TestOptimizationModeMultiObjective(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/optimizations/multi_objective.sol",
+		configUpdates: func(projectConfig *config.ProjectConfig) {
+			projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			projectConfig.Fuzzing.TestLimit = 10_000
+			projectConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check the values found for each optimization test objective
+			testCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusPassed)
+			assert.Len(t, testCases, 2)
+			for _, testCase := range testCases {
+				optimizationTestCase, ok := testCase.(*OptimizationTestCase)
+				assert.True(t, ok)
+				if optimizationTestCase.Minimize() {
+					assert.EqualValues(t, 0, optimizationTestCase.Value().Cmp(big.NewInt(-999)))
+				} else {
+					assert.EqualValues(t, 0, optimizationTestCase.Values()[0].Cmp(big.NewInt(99)))
+					assert.EqualValues(t, 0, optimizationTestCase.Values()[1].Cmp(big.NewInt(49)))
+				}
+
+				// Each improvement in the timeline should be better than the last, ending with the best value.
+				for objectiveIndex, value := range optimizationTestCase.Values() {
+					timeline := optimizationTestCase.Timeline(objectiveIndex)
+					assert.NotEmpty(t, timeline)
+					assert.EqualValues(t, 0, timeline[len(timeline)-1].Value.Cmp(value))
+				}
+			}
+		},
+	})
+}
+
+// TestFuzzTestMode runs a test to ensure stateless fuzz tests are called with arguments in isolation and that a
+// reverting fuzz test is reported as failed with a single shrunken call.
+func TestFuzzTestMode(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/fuzz_tests/fuzz_test_reverts.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.Testing.FuzzTesting.Enabled = true
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for the failed fuzz test, which should have been shrunk to a single call.
+			assertFailedTestsExpected(f, true)
+			for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+				fuzzTestCase, ok := testCase.(*FuzzTestCase)
+				assert.True(t, ok)
+				assert.EqualValues(t, "testFuzz_revertsOnLargeInput", fuzzTestCase.targetMethod.Name)
+				assert.Len(t, *fuzzTestCase.CallSequence(), 1)
+			}
+
+			// Check that the fuzz test which never reverts passed.
+			assert.Len(t, f.fuzzer.TestCasesWithStatus(TestCaseStatusPassed), 1)
+		},
+	})
+}
+
 // TestChainBehaviour runs tests to ensure the chain behaves as expected.
 func TestChainBehaviour(t *testing.T) {
 	// Run a test to simulate out of gas errors to make sure its handled well by the Chain and does not panic.
@@ -321,6 +1038,84 @@ func TestCheatCodes(t *testing.T) {
 	}
 }
 
+// TestCheatCodesFFIRestrictions tests that the ffi cheat code reverts for commands which are not allowed or which time
+// out, that environment variables which are not whitelisted are not passed to commands, and that input provided to
+// the ffi and tryFfi cheat codes is passed to commands through stdin.
+func TestCheatCodesFFIRestrictions(t *testing.T) {
+	// The test relies on a unix shell, so we skip it on Windows.
+	if utils.IsWindowsEnvironment() {
+		t.Skip("ffi restriction tests rely on a unix shell")
+	}
+
+	// Set the environment variables the test contract expects to be passed or stripped.
+	t.Setenv("MEDUSA_FFI_TEST_VISIBLE", "visible")
+	t.Setenv("MEDUSA_FFI_TEST_STRIPPED", "stripped")
+
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/cheat_codes/utils/ffi_restrictions_unix.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Workers = 1
+			config.Fuzzing.TestLimit = uint64(config.Fuzzing.CallSequenceLength)
+
+			// enable assertion testing only
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = true
+
+			config.Fuzzing.TestChainConfig.CheatCodeConfig.CheatCodesEnabled = true
+			config.Fuzzing.TestChainConfig.CheatCodeConfig.EnableFFI = true
+			config.Fuzzing.TestChainConfig.CheatCodeConfig.FFIAllowedCommands = []string{"sh"}
+			config.Fuzzing.TestChainConfig.CheatCodeConfig.FFITimeout = 1
+			config.Fuzzing.TestChainConfig.CheatCodeConfig.FFIEnvironmentWhitelist = []string{"PATH", "MEDUSA_FFI_TEST_VISIBLE"}
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for failed assertion tests.
+			assertFailedTestsExpected(f, false)
+		},
+	})
+}
+
+// TestStorageCheatCodes tests the cheat codes which read and write named storage variables. These require the storage
+// layout of the target contract, so the test is compiled with the solc platform directly.
+func TestStorageCheatCodes(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/cheat_codes/vm/read_write_storage.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			// Switch our compilation to the solc platform, targeting the same file.
+			platformConfig, err := config.Compilation.GetPlatformConfig()
+			assert.NoError(t, err)
+			solcPlatformConfig := platforms.NewSolcCompilationConfig(platformConfig.(*platforms.CryticCompilationConfig).Target)
+			config.Compilation, err = compilation.NewCompilationConfigFromPlatformConfig(solcPlatformConfig)
+			assert.NoError(t, err)
+
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 1_000
+
+			// enable assertion testing only
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = true
+
+			config.Fuzzing.TestChainConfig.CheatCodeConfig.CheatCodesEnabled = true
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for failed assertion tests.
+			assertFailedTestsExpected(f, false)
+		},
+	})
+}
+
 // TestConsoleLog tests the console.log precompile contract by logging a variety of different primitive types and
 // then failing. The execution trace for the failing call sequence should hold the various logs.
 func TestConsoleLog(t *testing.T) {
@@ -375,6 +1170,35 @@ func TestConsoleLog(t *testing.T) {
 	}
 }
 
+// TestFuzzerHintCheatCodes tests the medusa cheat code contract, ensuring values added to the fuzzer's dictionary at
+// runtime are used in value generation to solve a test which cannot be solved from source constants alone.
+func TestFuzzerHintCheatCodes(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/cheat_codes/medusa/fuzzer_hints.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 10000
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Fuzzing.TestChainConfig.CheatCodeConfig.CheatCodesEnabled = true
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for failed assertion tests.
+			assertFailedTestsExpected(f, true)
+
+			// Make sure the interesting hint caused the corpus to collect call sequences, recording its identifier so
+			// the sequence flagging it was weighted higher.
+			assertCorpusCallSequencesCollected(f, true)
+			assert.EqualValues(t, 1, f.fuzzer.corpus.InterestingIdCount())
+		},
+	})
+}
+
 // TestDeploymentsInnerDeployments runs tests to ensure dynamically deployed contracts are detected by the Fuzzer and
 // their properties are tested appropriately.
 func TestDeploymentsInnerDeployments(t *testing.T) {
@@ -730,6 +1554,95 @@ func TestValueGenerationSolving(t *testing.T) {
 	}
 }
 
+// TestValueGenerationComparisonFeedback runs a test to ensure the fuzzer can solve comparisons against values which
+// are only computed at runtime, using the operands of comparisons observed during execution.
+func TestValueGenerationComparisonFeedback(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/match_runtime_magic.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.ComparisonFeedbackEnabled = true
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for any failed tests and verify coverage was captured
+			assertFailedTestsExpected(f, true)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+}
+
+// TestValueGenerationComparisonFeedbackDisabled runs a test to ensure the fuzzer does not provide a uint function
+// argument which is only computed at runtime when comparison feedback is disabled, as it cannot observe the operands of
+// comparisons to learn it.
+func TestValueGenerationComparisonFeedbackDisabled(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/match_runtime_magic.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.ComparisonFeedbackEnabled = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check the magic value was never provided
+			assertFailedTestsExpected(f, false)
+		},
+	})
+}
+
+// TestUpdateValueSetFromComparisons ensures the comparison operands and hash inputs recorded during execution are added
+// to a worker's value set, except for operands matching the function selector of a known method.
+func TestUpdateValueSetFromComparisons(t *testing.T) {
+	// Create a worker which knows of a single method, whose function selector is 0x12345678.
+	worker := &FuzzerWorker{
+		valueSet:        valuegeneration.NewValueSet(),
+		methodSelectors: map[uint32]struct{}{0x12345678: {}},
+	}
+
+	// Create comparison results with an operand, a function selector operand, and a hash input spanning two 32-byte
+	// words, plus trailing bytes.
+	operand := big.NewInt(1000)
+	selectorOperand := big.NewInt(0x12345678)
+	firstWord := common.LeftPadBytes(big.NewInt(7).Bytes(), 32)
+	secondWord := common.LeftPadBytes(big.NewInt(8).Bytes(), 32)
+	hashInput := append(append(append([]byte{}, firstWord...), secondWord...), 0xff)
+	worker.updateValueSetFromComparisons(&valuegeneration.ComparisonResults{
+		Operands:   []*big.Int{operand, selectorOperand},
+		HashInputs: [][]byte{hashInput},
+	})
+
+	// Check the function selector operand was skipped.
+	assert.False(t, worker.valueSet.ContainsInteger(selectorOperand))
+
+	// Check the operand and its neighbouring values were added.
+	assert.True(t, worker.valueSet.ContainsInteger(big.NewInt(999)))
+	assert.True(t, worker.valueSet.ContainsInteger(big.NewInt(1000)))
+	assert.True(t, worker.valueSet.ContainsInteger(big.NewInt(1001)))
+
+	// Check the hash input and every 32-byte word within it were added.
+	assert.True(t, worker.valueSet.ContainsBytes(hashInput))
+	assert.True(t, worker.valueSet.ContainsInteger(big.NewInt(7)))
+	assert.True(t, worker.valueSet.ContainsInteger(big.NewInt(8)))
+	assert.Len(t, worker.valueSet.Integers(), 5)
+
+	// Check nil results are ignored.
+	worker.updateValueSetFromComparisons(nil)
+	assert.Len(t, worker.valueSet.Integers(), 5)
+}
+
 // TestASTValueExtraction runs a test to ensure appropriate AST values can be mined out of a compiled source's AST.
 func TestASTValueExtraction(t *testing.T) {
 	// Define our expected values to be mined.
@@ -867,6 +1780,48 @@ func TestVMCorrectness(t *testing.T) {
 	})
 }
 
+// TestCorpusSequenceMutationStrategies runs a test to ensure the fuzzer can solve a problem requiring specific calls
+// in sequence when only using the sequence-level corpus mutation strategies which delete, duplicate, swap, or modify
+// calls from corpus call sequences.
+func TestCorpusSequenceMutationStrategies(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/corpus_mutation/specific_call_sequence.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Only use the sequence-level corpus mutation strategies.
+			existingSeqGenConfigFunc := f.fuzzer.Hooks.NewCallSequenceGeneratorConfigFunc
+			f.fuzzer.Hooks.NewCallSequenceGeneratorConfigFunc = func(fuzzer *Fuzzer, valueSet *valuegeneration.ValueSet, randomProvider *rand.Rand) (*CallSequenceGeneratorConfig, error) {
+				seqGenConfig, err := existingSeqGenConfigFunc(fuzzer, valueSet, randomProvider)
+				if err != nil {
+					return nil, err
+				}
+				seqGenConfig.RandomUnmodifiedCorpusHeadWeight = 0
+				seqGenConfig.RandomUnmodifiedCorpusTailWeight = 0
+				seqGenConfig.RandomUnmodifiedSpliceAtRandomWeight = 0
+				seqGenConfig.RandomUnmodifiedInterleaveAtRandomWeight = 0
+				seqGenConfig.RandomMutatedCorpusHeadWeight = 0
+				seqGenConfig.RandomMutatedCorpusTailWeight = 0
+				seqGenConfig.RandomMutatedSpliceAtRandomWeight = 0
+				seqGenConfig.RandomMutatedInterleaveAtRandomWeight = 0
+				return seqGenConfig, nil
+			}
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for any failed tests and verify coverage was captured
+			assertFailedTestsExpected(f, true)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+}
+
 // TestCorpusReplayability will test whether the corpus, when replayed, will end up with the same coverage.
 // Additionally, check if the second run is solved with sequences executed being less or equal to the total corpus
 // call sequences. This should occur as the corpus call sequences should be executed unmodified first (including
@@ -926,6 +1881,109 @@ func TestCorpusReplayability(t *testing.T) {
 	})
 }
 
+// TestCorpusMinimization runs a test to ensure that minimizing a corpus preserves the coverage it achieves, and moves
+// the call sequences it removes to the archive directory.
+func TestCorpusMinimization(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/match_uints_xy.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.CorpusDirectory = "corpus"
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Make sure we have some coverage
+			assertCorpusCallSequencesCollected(f, true)
+			originalCoverage := f.fuzzer.corpus.CoverageMaps()
+
+			// Minimize the corpus
+			result, err := f.fuzzer.MinimizeCorpus("archive")
+			assert.NoError(t, err)
+			assert.EqualValues(t, 0, result.InvalidSequences)
+			assert.EqualValues(t, result.TotalSequences, result.KeptSequences+result.RedundantSequences)
+			assert.Greater(t, result.KeptSequences, 0)
+
+			// Check that every removed call sequence was archived.
+			archivedFiles, err := filepath.Glob(filepath.Join("archive", "*.json"))
+			assert.NoError(t, err)
+			assert.Len(t, archivedFiles, result.RedundantSequences)
+			keptFiles, err := filepath.Glob(filepath.Join("corpus", "call_sequences", "*.json"))
+			assert.NoError(t, err)
+			assert.Len(t, keptFiles, result.KeptSequences)
+
+			// Replay the minimized corpus and check that it achieves the same coverage (disregarding hit count)
+			baseTestChain, err := f.fuzzer.createBaseTestChain()
+			assert.NoError(t, err)
+			_, _, err = f.fuzzer.corpus.Initialize(baseTestChain, f.fuzzer.contractDefinitions, rand.New(rand.NewSource(0)))
+			assert.NoError(t, err)
+			minimizedCoverage := f.fuzzer.corpus.CoverageMaps()
+
+			successCovIncreased, revertCovIncreased, err := originalCoverage.Update(minimizedCoverage)
+			assert.False(t, successCovIncreased)
+			assert.False(t, revertCovIncreased)
+			assert.NoError(t, err)
+
+			successCovIncreased, revertCovIncreased, err = minimizedCoverage.Update(originalCoverage)
+			assert.False(t, successCovIncreased)
+			assert.False(t, revertCovIncreased)
+			assert.NoError(t, err)
+		},
+	})
+}
+
+// TestEdgeCoverage runs a test to ensure edge coverage can be collected when enabled.
+func TestEdgeCoverage(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/coverage/edge_coverage.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.CoverageMode = "edge"
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for any failed tests and verify coverage was captured
+			assertFailedTestsExpected(f, true)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+}
+
+// TestStorageWriteCoverage runs a test to ensure storage writes are treated as coverage when enabled, allowing the
+// fuzzer to explore contract states which do not achieve new program counter coverage.
+func TestStorageWriteCoverage(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/coverage/storage_write_novelty.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.StorageWriteCoverage = "exact"
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for any failed tests and verify coverage was captured
+			assertFailedTestsExpected(f, true)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+}
+
 // TestDeploymentOrderWithCoverage will ensure that changing the order of deployment for the target contracts does not
 // lead to the same coverage. This is also proof that changing the order changes the addresses of the contracts leading
 // to the coverage not being useful.
@@ -1017,3 +2075,26 @@ func TestExcludeFunctionSignatures(t *testing.T) {
 			}
 		}})
 }
+
+// TestFindStorageLayoutCheatCodeCall ensures contracts are only found to call a storage layout cheat code if their
+// bytecode pushes its selector, so the fuzzer can fail at startup when no storage layouts are available for it.
+func TestFindStorageLayoutCheatCodeCall(t *testing.T) {
+	// Create a contract which merely contains the selector, and one which pushes it as it would when calling it.
+	selector := crypto.Keccak256([]byte("writeStorage(address,string,bytes32)"))[:4]
+	declaring := fuzzerTypes.NewContract("Declaring", "", &compilationTypes.CompiledContract{
+		RuntimeBytecode: append([]byte{byte(vm.PUSH1), 0x00}, selector...),
+	}, nil)
+	calling := fuzzerTypes.NewContract("Calling", "", &compilationTypes.CompiledContract{
+		RuntimeBytecode: append([]byte{byte(vm.PUSH1), 0x00, byte(vm.PUSH4)}, selector...),
+	}, nil)
+
+	// Check the contract which doesn't push the selector is not found.
+	contractDefinition, cheatCodeSig := findStorageLayoutCheatCodeCall(fuzzerTypes.Contracts{declaring})
+	assert.Nil(t, contractDefinition)
+	assert.Empty(t, cheatCodeSig)
+
+	// Check the contract which pushes the selector is found, along with the cheat code it calls.
+	contractDefinition, cheatCodeSig = findStorageLayoutCheatCodeCall(fuzzerTypes.Contracts{declaring, calling})
+	assert.Same(t, calling, contractDefinition)
+	assert.EqualValues(t, "writeStorage(address,string,bytes32)", cheatCodeSig)
+}
@@ -1,7 +1,10 @@
 package fuzzing
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"math"
 	"math/big"
 	"math/rand"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/crytic/medusa/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // FuzzerWorker describes a single thread worker utilizing its own go-ethereum test node to run property tests against
@@ -25,9 +29,21 @@ type FuzzerWorker struct {
 
 	// chain describes a test chain created by the FuzzerWorker to deploy contracts and run tests against.
 	chain *chain.TestChain
+	// baseTestChain describes the test chain in a setup state which the FuzzerWorker's chain was cloned from. It is
+	// cloned again to replay call sequences in isolation from the worker's state.
+	baseTestChain *chain.TestChain
 	// coverageTracer describes the tracer used to collect coverage maps during fuzzing campaigns.
 	coverageTracer *coverage.CoverageTracer
 
+	// comparisonTracer describes the tracer used to collect comparison operands and hash inputs during fuzzing
+	// campaigns.
+	comparisonTracer *valuegeneration.ComparisonTracer
+
+	// methodSelectors describes the function selectors of every method of the contract definitions known to the
+	// Fuzzer. Comparison operands matching them are not added to the value set, as they are compared against the
+	// selector of every call by a contract's function dispatcher.
+	methodSelectors map[uint32]struct{}
+
 	// testingBaseBlockNumber refers to the block number at which all contracts for testing have been deployed, prior
 	// to any fuzzing activity. This block number is reverted to after testing each call sequence to reset state.
 	testingBaseBlockNumber uint64
@@ -43,6 +59,10 @@ type FuzzerWorker struct {
 	// pureMethods is a list of contract functions which are side-effect free with respect to the EVM (view and/or pure in terms of Solidity mutability).
 	pureMethods []fuzzerTypes.DeployedContractMethod
 
+	// fuzzTestMethods is a list of contract functions which are stateless fuzz tests. These are never called as part of
+	// a call sequence, but are instead called in isolation from the testing base state by a StatelessTestFunc.
+	fuzzTestMethods []fuzzerTypes.DeployedContractMethod
+
 	// randomProvider provides random data as inputs to decisions throughout the worker.
 	randomProvider *rand.Rand
 	// sequenceGenerator creates entirely new or mutated call sequences based on corpus call sequences, for use in
@@ -87,7 +107,10 @@ func newFuzzerWorker(fuzzer *Fuzzer, workerIndex int, randomProvider *rand.Rand)
 		deployedContracts:    make(map[common.Address]*fuzzerTypes.Contract),
 		stateChangingMethods: make([]fuzzerTypes.DeployedContractMethod, 0),
 		pureMethods:          make([]fuzzerTypes.DeployedContractMethod, 0),
+		fuzzTestMethods:      make([]fuzzerTypes.DeployedContractMethod, 0),
 		coverageTracer:       nil,
+		comparisonTracer:     nil,
+		methodSelectors:      getMethodSelectors(fuzzer.contractDefinitions),
 		randomProvider:       randomProvider,
 		valueSet:             valueSet,
 	}
@@ -132,6 +155,22 @@ func (fw *FuzzerWorker) DeployedContract(address common.Address) *fuzzerTypes.Co
 	return nil
 }
 
+// deployedContractByName obtains the address and definition of a deployed contract with the given name. If the contract
+// was deployed more than once, the deployment with the lowest address is returned, so the same deployment is resolved
+// across workers. If it does not exist, it returns nil.
+func (fw *FuzzerWorker) deployedContractByName(name string) (*common.Address, *fuzzerTypes.Contract) {
+	var contractAddress *common.Address
+	var contractDefinition *fuzzerTypes.Contract
+	for address, contract := range fw.deployedContracts {
+		if contract.Name() == name && (contractAddress == nil || bytes.Compare(address.Bytes(), contractAddress.Bytes()) < 0) {
+			address := address
+			contractAddress = &address
+			contractDefinition = contract
+		}
+	}
+	return contractAddress, contractDefinition
+}
+
 // ValueSet obtains the value set used to power the value generator for this worker.
 func (fw *FuzzerWorker) ValueSet() *valuegeneration.ValueSet {
 	return fw.valueSet
@@ -233,21 +272,104 @@ func (fw *FuzzerWorker) updateMethods() {
 	// Clear our list of methods
 	fw.stateChangingMethods = make([]fuzzerTypes.DeployedContractMethod, 0)
 	fw.pureMethods = make([]fuzzerTypes.DeployedContractMethod, 0)
+	fw.fuzzTestMethods = make([]fuzzerTypes.DeployedContractMethod, 0)
+
+	// Loop through each deployed contract, in order of address so that method selection is deterministic.
+	contractAddresses := maps.Keys(fw.deployedContracts)
+	slices.SortFunc(contractAddresses, func(a, b common.Address) int {
+		return a.Cmp(b)
+	})
+	for _, contractAddress := range contractAddresses {
+		contractDefinition := fw.deployedContracts[contractAddress]
+		// Track any stateless fuzz tests separately, as they are not called within call sequences.
+		for _, method := range contractDefinition.FuzzTestMethods {
+			fw.fuzzTestMethods = append(fw.fuzzTestMethods, fuzzerTypes.DeployedContractMethod{Address: contractAddress, Contract: contractDefinition, Method: method})
+		}
+
+		// Differential testing candidates are not called directly, as their state must only change through the calls
+		// mirrored onto them from their reference.
+		if fw.fuzzer.config.Fuzzing.Testing.DifferentialTesting.IsCandidateContract(contractDefinition.Name()) {
+			continue
+		}
 
-	// Loop through each deployed contract
-	for contractAddress, contractDefinition := range fw.deployedContracts {
 		// If we deployed the contract, also enumerate property tests and state changing methods.
 		for _, method := range contractDefinition.AssertionTestMethods {
 			// Any non-constant method should be tracked as a state changing method.
 			if method.IsConstant() {
 				// Only track the pure/view method if testing view methods is enabled
 				if fw.fuzzer.config.Fuzzing.Testing.AssertionTesting.TestViewMethods {
-					fw.pureMethods = append(fw.pureMethods, fuzzerTypes.DeployedContractMethod{Address: contractAddress, Contract: contractDefinition, Method: method})
+					fw.pureMethods = append(fw.pureMethods, fuzzerTypes.DeployedContractMethod{Address: contractAddress, Contract: contractDefinition, Method: method, Precondition: contractDefinition.PreconditionMethod(&method)})
 				}
 			} else {
-				fw.stateChangingMethods = append(fw.stateChangingMethods, fuzzerTypes.DeployedContractMethod{Address: contractAddress, Contract: contractDefinition, Method: method})
+				fw.stateChangingMethods = append(fw.stateChangingMethods, fuzzerTypes.DeployedContractMethod{Address: contractAddress, Contract: contractDefinition, Method: method, Precondition: contractDefinition.PreconditionMethod(&method)})
+			}
+		}
+	}
+}
+
+// updateValueSetFromFuzzerHints adds any values a harness requested be added to the value dictionary (through the
+// medusa cheat code contract) to the worker's value set, so they may be used in value generation.
+func (fw *FuzzerWorker) updateValueSetFromFuzzerHints(hints *chain.FuzzerHintResults) {
+	// If no hints were provided, there is nothing to add.
+	if hints == nil {
+		return
+	}
+
+	// Add each value to the appropriate set, given its type.
+	for _, value := range hints.DictionaryValues {
+		switch v := value.(type) {
+		case *big.Int:
+			fw.valueSet.AddInteger(v)
+		case common.Address:
+			fw.valueSet.AddAddress(v)
+		case []byte:
+			fw.valueSet.AddBytes(v)
+		case string:
+			fw.valueSet.AddString(v)
+		}
+	}
+}
+
+// getMethodSelectors obtains the function selectors of every method of the provided contract definitions.
+// Returns a set of the function selectors, as integers.
+func getMethodSelectors(contractDefinitions fuzzerTypes.Contracts) map[uint32]struct{} {
+	methodSelectors := make(map[uint32]struct{})
+	for _, contractDefinition := range contractDefinitions {
+		for _, method := range contractDefinition.CompiledContract().Abi.Methods {
+			methodSelectors[binary.BigEndian.Uint32(method.ID)] = struct{}{}
+		}
+	}
+	return methodSelectors
+}
+
+// updateValueSetFromComparisons adds the comparison operands and hash inputs recorded during execution to the
+// worker's value set, so they may be used in value generation. Comparison operands are added alongside their
+// neighbouring values, so that inequalities may also be satisfied. Operands matching the function selector of a
+// known method are skipped, as they stem from function dispatchers rather than from the logic of a method.
+func (fw *FuzzerWorker) updateValueSetFromComparisons(results *valuegeneration.ComparisonResults) {
+	// If no results were provided, there is nothing to add.
+	if results == nil {
+		return
+	}
+
+	// Add each operand and its neighbouring values.
+	for _, operand := range results.Operands {
+		if operand.IsUint64() && operand.Uint64() <= math.MaxUint32 {
+			if _, isMethodSelector := fw.methodSelectors[uint32(operand.Uint64())]; isMethodSelector {
+				continue
 			}
 		}
+		fw.valueSet.AddInteger(operand)
+		fw.valueSet.AddInteger(new(big.Int).Sub(operand, big.NewInt(1)))
+		fw.valueSet.AddInteger(new(big.Int).Add(operand, big.NewInt(1)))
+	}
+
+	// Add each hash input, as well as every 32-byte word within it (e.g. mapping keys).
+	for _, hashInput := range results.HashInputs {
+		fw.valueSet.AddBytes(hashInput)
+		for i := 0; i+32 <= len(hashInput); i += 32 {
+			fw.valueSet.AddInteger(new(big.Int).SetBytes(hashInput[i : i+32]))
+		}
 	}
 }
 
@@ -284,9 +406,16 @@ func (fw *FuzzerWorker) testNextCallSequence() (calls.CallSequence, []ShrinkCall
 	// request for a shrunk call sequence, we exit our call sequence execution immediately to go fulfill the shrink
 	// request.
 	executionCheckFunc := func(currentlyExecutedSequence calls.CallSequence) (bool, error) {
+		// Add any values the harness requested be added to our value dictionary.
+		lastCallSequenceElement := currentlyExecutedSequence[len(currentlyExecutedSequence)-1]
+		fw.updateValueSetFromFuzzerHints(chain.GetFuzzerHintResults(lastCallSequenceElement.ChainReference.MessageResults()))
+
+		// Add any values compared or hashed during execution to our value dictionary.
+		fw.updateValueSetFromComparisons(valuegeneration.GetComparisonTracerResults(lastCallSequenceElement.ChainReference.MessageResults()))
+
 		// Check for updates to coverage and corpus.
 		// If we detect coverage changes, add this sequence with weight as 1 + sequences tested (to avoid zero weights)
-		err := fw.fuzzer.corpus.CheckSequenceCoverageAndUpdate(currentlyExecutedSequence, fw.getNewCorpusCallSequenceWeight(), true)
+		err := fw.fuzzer.corpus.CheckSequenceCoverageAndUpdate(currentlyExecutedSequence, fw.getNewCorpusCallSequenceWeight(), fw.sequenceGenerator.mutationTargets, true)
 		if err != nil {
 			return true, err
 		}
@@ -302,8 +431,8 @@ func (fw *FuzzerWorker) testNextCallSequence() (calls.CallSequence, []ShrinkCall
 		}
 
 		// Update our metrics
+		fw.workerMetrics().methodCallStats.recordCall(lastCallSequenceElement)
 		fw.workerMetrics().callsTested.Add(fw.workerMetrics().callsTested, big.NewInt(1))
-		lastCallSequenceElement := currentlyExecutedSequence[len(currentlyExecutedSequence)-1]
 		fw.workerMetrics().gasUsed.Add(fw.workerMetrics().gasUsed, new(big.Int).SetUint64(lastCallSequenceElement.ChainReference.Block.MessageResults[lastCallSequenceElement.ChainReference.TransactionIndex].Receipt.GasUsed))
 
 		// If our fuzzer context is done, exit out immediately without results.
@@ -328,6 +457,18 @@ func (fw *FuzzerWorker) testNextCallSequence() (calls.CallSequence, []ShrinkCall
 		return nil, nil, nil
 	}
 
+	// Execution of the call sequence ended, either because the sequence is finished or because we have shrink
+	// requests, so call each end of sequence test function against the resulting state.
+	if len(testedCallSequence) > 0 {
+		for _, callSequenceEndTestFunc := range fw.fuzzer.Hooks.CallSequenceEndTestFuncs {
+			newShrinkRequests, err := callSequenceEndTestFunc(fw, testedCallSequence)
+			if err != nil {
+				return nil, nil, err
+			}
+			shrinkCallSequenceRequests = append(shrinkCallSequenceRequests, newShrinkRequests...)
+		}
+	}
+
 	// If this was not a new call sequence, indicate not to save the shrunken result to the corpus again.
 	if !isNewSequence {
 		for i := 0; i < len(shrinkCallSequenceRequests); i++ {
@@ -367,10 +508,13 @@ func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.Ca
 	// request.
 	executionCheckFunc := func(currentlyExecutedSequence calls.CallSequence) (bool, error) {
 		// Check for updates to coverage and corpus (using only the section of the sequence we tested so far).
-		// If we detect coverage changes, add this sequence.
-		seqErr := fw.fuzzer.corpus.CheckSequenceCoverageAndUpdate(currentlyExecutedSequence, fw.getNewCorpusCallSequenceWeight(), true)
-		if seqErr != nil {
-			return true, seqErr
+		// If we detect coverage changes, add this sequence. Sequences which should not be used in the corpus are
+		// skipped entirely.
+		if !shrinkRequest.ExcludeFromCorpus {
+			seqErr := fw.fuzzer.corpus.CheckSequenceCoverageAndUpdate(currentlyExecutedSequence, fw.getNewCorpusCallSequenceWeight(), nil, true)
+			if seqErr != nil {
+				return true, seqErr
+			}
 		}
 
 		// If our fuzzer context is done, exit out immediately without results.
@@ -403,6 +547,34 @@ func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.Ca
 	return validShrunkSequence, nil
 }
 
+// bisectCallSequence takes a provided call sequence and locates the shortest prefix of it which continues to satisfy
+// the provided shrink verifier, using a binary search. This assumes that once a prefix satisfies the verifier, any
+// longer prefix does too.
+// Returns the shortest prefix found, or an error if one occurred.
+func (fw *FuzzerWorker) bisectCallSequence(callSequence calls.CallSequence, shrinkRequest ShrinkCallSequenceRequest) (calls.CallSequence, error) {
+	// The full call sequence is known to satisfy the verifier, so we search for the shortest prefix in [1, len].
+	low, high := 1, len(callSequence)
+	for low < high && !utils.CheckContextDone(fw.fuzzer.ctx) {
+		prefixLength := (low + high) / 2
+		possibleBisectedSequence, err := callSequence[:prefixLength].Clone()
+		if err != nil {
+			return nil, err
+		}
+
+		// Test the prefix, narrowing our search to the shorter half if it satisfied our conditions.
+		validBisectedSequence, err := fw.testShrunkenCallSequence(possibleBisectedSequence, shrinkRequest)
+		if err != nil {
+			return nil, err
+		}
+		if validBisectedSequence {
+			high = prefixLength
+		} else {
+			low = prefixLength + 1
+		}
+	}
+	return callSequence[:high], nil
+}
+
 // shrinkCallSequence takes a provided call sequence and attempts to shrink it by looking for redundant
 // calls which can be removed, and values which can be minimized, while continuing to satisfy the provided shrink
 // verifier.
@@ -416,6 +588,15 @@ func (fw *FuzzerWorker) shrinkCallSequence(callSequence calls.CallSequence, shri
 	// Define a variable to track our most optimized sequence across all optimization iterations.
 	optimizedSequence := callSequence
 
+	// If requested, locate the first call which satisfies our conditions before shrinking, discarding any calls after it.
+	if shrinkRequest.BisectBeforeShrinking && len(optimizedSequence) > 1 {
+		var err error
+		optimizedSequence, err = fw.bisectCallSequence(optimizedSequence, shrinkRequest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Obtain our shrink limits and begin shrinking.
 	shrinkIteration := uint64(0)
 	shrinkLimit := fw.fuzzer.config.Fuzzing.ShrinkLimit
@@ -516,10 +697,20 @@ func (fw *FuzzerWorker) shrinkCallSequence(callSequence calls.CallSequence, shri
 		return nil, err
 	}
 
+	// If requested, verify the shrunken call sequence reproduces its result when replayed on a fresh chain, so any
+	// test cases reported by the finished callback can be flagged as flaky if it does not.
+	var flakiness *CallSequenceFlakiness
+	if shrinkRequest.CheckReproducibility {
+		flakiness, err = fw.checkCallSequenceReproducibility(optimizedSequence, shrinkRequest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Shrinking is complete. If our config specified we want all result sequences to have execution traces attached,
 	// attach them now to each element in the sequence. Otherwise, call sequences will only have traces that the
 	// test providers choose to attach themselves.
-	err = shrinkRequest.FinishedCallback(fw, optimizedSequence, fw.fuzzer.config.Fuzzing.Testing.TraceAll)
+	err = shrinkRequest.FinishedCallback(fw, optimizedSequence, fw.fuzzer.config.Fuzzing.Testing.TraceAll, flakiness)
 	if err != nil {
 		return nil, err
 	}
@@ -539,6 +730,7 @@ func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
 	// This means any tracers added or events subscribed to within this inner function are done so prior to chain
 	// setup (initial contract deployments), so data regarding that can be tracked as well.
 	var err error
+	fw.baseTestChain = baseTestChain
 	fw.chain, err = baseTestChain.Clone(func(initializedChain *chain.TestChain) error {
 		// Subscribe our chain event handlers
 		initializedChain.Events.ContractDeploymentAddedEventEmitter.Subscribe(fw.onChainContractDeploymentAddedEvent)
@@ -555,9 +747,16 @@ func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
 
 		// If we have coverage-guided fuzzing enabled, create a tracer to collect coverage and connect it to the chain.
 		if fw.fuzzer.config.Fuzzing.CoverageEnabled {
-			fw.coverageTracer = coverage.NewCoverageTracer()
+			fw.coverageTracer = coverage.NewCoverageTracer(coverage.CoverageMode(fw.fuzzer.config.Fuzzing.CoverageMode), coverage.StorageWriteBucketing(fw.fuzzer.config.Fuzzing.StorageWriteCoverage))
 			initializedChain.AddTracer(fw.coverageTracer.NativeTracer(), true, false)
 		}
+
+		// If we have comparison feedback enabled, create a tracer to collect comparison operands and hash inputs and
+		// connect it to the chain.
+		if fw.fuzzer.config.Fuzzing.ComparisonFeedbackEnabled {
+			fw.comparisonTracer = valuegeneration.NewComparisonTracer()
+			initializedChain.AddTracer(fw.comparisonTracer.NativeTracer(), true, false)
+		}
 		return nil
 	})
 
@@ -603,18 +802,38 @@ func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
 			return false, fmt.Errorf("error returned by an event handler when a worker emitted an event indicating testing of a new call sequence is starting: %v", err)
 		}
 
-		// Test a new sequence
-		callSequence, shrinkVerifiers, err := fw.testNextCallSequence()
-		if err != nil {
-			return false, err
-		}
-
-		// If we have any requests to shrink call sequences, do so now.
-		for _, shrinkVerifier := range shrinkVerifiers {
-			_, err = fw.shrinkCallSequence(callSequence, shrinkVerifier)
+		// Test a new sequence, unless the only methods we have to call are stateless fuzz tests.
+		if len(fw.stateChangingMethods) > 0 || len(fw.pureMethods) > 0 || len(fw.fuzzTestMethods) == 0 {
+			callSequence, shrinkVerifiers, err := fw.testNextCallSequence()
 			if err != nil {
 				return false, err
 			}
+
+			// If we have any requests to shrink call sequences, do so now.
+			for _, shrinkVerifier := range shrinkVerifiers {
+				_, err = fw.shrinkCallSequence(callSequence, shrinkVerifier)
+				if err != nil {
+					return false, err
+				}
+			}
+		}
+
+		// Run any stateless tests, which execute in isolation from the testing base state.
+		if len(fw.fuzzTestMethods) > 0 {
+			for _, statelessTestFunc := range fw.fuzzer.Hooks.StatelessTestFuncs {
+				callSequence, shrinkVerifiers, err := statelessTestFunc(fw)
+				if err != nil {
+					return false, err
+				}
+
+				// If we have any requests to shrink call sequences, do so now.
+				for _, shrinkVerifier := range shrinkVerifiers {
+					_, err = fw.shrinkCallSequence(callSequence, shrinkVerifier)
+					if err != nil {
+						return false, err
+					}
+				}
+			}
 		}
 
 		// Emit an event indicating the worker is about to test a new call sequence.
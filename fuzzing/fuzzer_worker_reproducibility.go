@@ -0,0 +1,143 @@
+package fuzzing
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/fuzzing/calls"
+)
+
+// CallSequenceFlakiness describes how a call sequence which satisfied a shrink request's verifier failed to do so when
+// replayed on a fresh clone of the base chain.
+type CallSequenceFlakiness struct {
+	// replay describes the 1-based index of the replay which did not reproduce the result.
+	replay int
+	// divergedCallIndex describes the index of the first call in the sequence whose execution differed from the
+	// original execution during the replay, or -1 if every call executed identically.
+	divergedCallIndex int
+	// divergence describes how the execution of the diverged call differed.
+	divergence string
+}
+
+// String provides a human-readable description of the flakiness.
+func (f *CallSequenceFlakiness) String() string {
+	if f.divergedCallIndex < 0 {
+		return fmt.Sprintf("replay %d executed every call identically, but did not reproduce the failure", f.replay)
+	}
+	return fmt.Sprintf("replay %d diverged at call %d: %s", f.replay, f.divergedCallIndex+1, f.divergence)
+}
+
+// describeCallDivergence compares the execution results of a call sequence element against those of the same element
+// executed in a replay.
+// Returns a description of how the execution differed, or an empty string if it did not.
+func describeCallDivergence(original *calls.CallSequenceElement, replayed *calls.CallSequenceElement) string {
+	// If either element has no execution results, we have nothing to compare.
+	if original.ChainReference == nil || replayed.ChainReference == nil {
+		return ""
+	}
+
+	originalResult := original.ChainReference.MessageResults().ExecutionResult
+	replayedResult := replayed.ChainReference.MessageResults().ExecutionResult
+	if originalResult.Failed() != replayedResult.Failed() {
+		return fmt.Sprintf("call reverted in one execution but not the other (original error: %v, replay error: %v)", originalResult.Err, replayedResult.Err)
+	}
+	if !bytes.Equal(originalResult.ReturnData, replayedResult.ReturnData) {
+		return "call returned different data"
+	}
+	if originalResult.UsedGas != replayedResult.UsedGas {
+		return fmt.Sprintf("call used a different amount of gas (original: %d, replay: %d)", originalResult.UsedGas, replayedResult.UsedGas)
+	}
+	return ""
+}
+
+// replayCallSequence executes a clone of the provided call sequence on a fresh clone of the base chain, and checks
+// whether it satisfies the provided shrink request's verifier. The worker's chain is restored prior to returning.
+// Returns nil if the verifier was satisfied, flakiness describing where the replay diverged otherwise, or an error if
+// one occurred.
+func (fw *FuzzerWorker) replayCallSequence(callSequence calls.CallSequence, shrinkRequest ShrinkCallSequenceRequest, replay int) (*CallSequenceFlakiness, error) {
+	// Clone our base chain, allowing providers to attach any tracers their verifiers rely on.
+	replayChain, err := fw.baseTestChain.Clone(func(initializedChain *chain.TestChain) error {
+		for _, replayChainTracerFunc := range fw.fuzzer.replayChainTracerFuncs {
+			replayChainTracerFunc(fw, initializedChain)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer replayChain.Close()
+
+	// Swap our worker's chain for the replay chain, so the verifier runs against it, restoring it once finished.
+	originalChain := fw.chain
+	fw.chain = replayChain
+	defer func() {
+		fw.chain = originalChain
+	}()
+
+	// Clone the call sequence so we do not overwrite the execution results of the original.
+	replayedSequence, err := callSequence.Clone()
+	if err != nil {
+		return nil, err
+	}
+	fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
+		// If we are at the end of our sequence, return nil indicating we should stop executing.
+		if currentIndex >= len(replayedSequence) {
+			return nil, nil
+		}
+
+		replayedSequence[currentIndex].Call.FillFromTestChainProperties(fw.chain)
+		return replayedSequence[currentIndex], nil
+	}
+	_, err = calls.ExecuteCallSequenceIteratively(fw.chain, fetchElementFunc, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the verifier is still satisfied, the result reproduced.
+	reproduced, err := shrinkRequest.VerifierFunction(fw, replayedSequence)
+	if err != nil || reproduced {
+		return nil, err
+	}
+
+	// Otherwise, locate the first call whose execution diverged from the original.
+	flakiness := &CallSequenceFlakiness{
+		replay:            replay,
+		divergedCallIndex: -1,
+	}
+	for i := 0; i < len(callSequence); i++ {
+		if divergence := describeCallDivergence(callSequence[i], replayedSequence[i]); divergence != "" {
+			flakiness.divergedCallIndex = i
+			flakiness.divergence = divergence
+			break
+		}
+	}
+	return flakiness, nil
+}
+
+// checkCallSequenceReproducibility replays the provided call sequence on fresh clones of the base chain, as many times
+// as configured, to verify it continues to satisfy the provided shrink request's verifier.
+// Returns nil if every replay satisfied the verifier, flakiness describing the first replay which did not otherwise,
+// or an error if one occurred.
+func (fw *FuzzerWorker) checkCallSequenceReproducibility(callSequence calls.CallSequence, shrinkRequest ShrinkCallSequenceRequest) (*CallSequenceFlakiness, error) {
+	for replay := 1; replay <= fw.fuzzer.config.Fuzzing.Testing.ReproducibilityChecks; replay++ {
+		flakiness, err := fw.replayCallSequence(callSequence, shrinkRequest, replay)
+		if err != nil || flakiness != nil {
+			return flakiness, err
+		}
+	}
+	return nil, nil
+}
+
+// reportTestCaseFinished is used by test providers to report a TestCase status as finalized to the Fuzzer, from within
+// a ShrinkCallSequenceRequest.FinishedCallback. If the TestCase failed or errored but the shrunken call sequence did not
+// reproduce the result when replayed, as described by the flakiness provided to the callback, it is reported as flaky
+// instead.
+func (fw *FuzzerWorker) reportTestCaseFinished(testCase TestCase, flakiness *CallSequenceFlakiness) {
+	failedOrErrored := testCase.Status() == TestCaseStatusFailed || testCase.Status() == TestCaseStatusErrored
+	if flakiness != nil && failedOrErrored {
+		fw.fuzzer.reportTestCaseFlaky(testCase, flakiness)
+		return
+	}
+	fw.fuzzer.ReportTestCaseFinished(testCase)
+}
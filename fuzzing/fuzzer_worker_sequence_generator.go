@@ -3,12 +3,17 @@ package fuzzing
 import (
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/contracts"
 	"github.com/crytic/medusa/fuzzing/valuegeneration"
 	"github.com/crytic/medusa/utils"
 	"github.com/crytic/medusa/utils/randomutils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/exp/slices"
 )
 
 // CallSequenceGenerator generates call sequences iteratively per element, for use in fuzzing campaigns. It is attached
@@ -34,6 +39,10 @@ type CallSequenceGenerator struct {
 	// to its fetching by PopSequenceElement.
 	prefetchModifyCallFunc PrefetchModifyCallFunc
 
+	// mutationTargets describes the file names of the corpus items the current baseSequence was derived from, as
+	// returned by corpus.Corpus.RandomMutationTargetSequence.
+	mutationTargets []string
+
 	// mutationStrategyChooser is a weighted random selector of functions that prepare the CallSequenceGenerator with
 	// a baseSequence derived from corpus entries.
 	mutationStrategyChooser *randomutils.WeightedRandomChooser[CallSequenceGeneratorMutationStrategy]
@@ -86,6 +95,33 @@ type CallSequenceGeneratorConfig struct {
 	// number of calls from each.
 	RandomMutatedInterleaveAtRandomWeight uint64
 
+	// RandomDeleteAtRandomWeight defines the weight that the CallSequenceGenerator should use the call sequence
+	// generation strategy of taking the head of a corpus sequence and deleting a random call from it.
+	RandomDeleteAtRandomWeight uint64
+
+	// RandomDuplicateAtRandomWeight defines the weight that the CallSequenceGenerator should use the call sequence
+	// generation strategy of taking the head of a corpus sequence and inserting a copy of a random call from it at a
+	// random position.
+	RandomDuplicateAtRandomWeight uint64
+
+	// RandomSwapAtRandomWeight defines the weight that the CallSequenceGenerator should use the call sequence
+	// generation strategy of taking the head of a corpus sequence and swapping the positions of two random calls in it.
+	RandomSwapAtRandomWeight uint64
+
+	// RandomReplaceSenderAtRandomWeight defines the weight that the CallSequenceGenerator should use the call sequence
+	// generation strategy of taking the head of a corpus sequence and replacing the sender of a random call in it.
+	RandomReplaceSenderAtRandomWeight uint64
+
+	// RandomReplaceDelayAtRandomWeight defines the weight that the CallSequenceGenerator should use the call sequence
+	// generation strategy of taking the head of a corpus sequence and replacing the block number and timestamp delays
+	// of a random call in it, without modifying the call itself.
+	RandomReplaceDelayAtRandomWeight uint64
+
+	// RandomCopyArgumentAtRandomWeight defines the weight that the CallSequenceGenerator should use the call sequence
+	// generation strategy of taking the head of a corpus sequence and copying a random argument of one call in it to
+	// an argument of the same type of another call in it.
+	RandomCopyArgumentAtRandomWeight uint64
+
 	// ValueGenerator defines the value provider to use when generating new values for call sequences. This is used both
 	// for ABI call data generation, and generation of additional values such as the "value" field of a
 	// transaction/call.
@@ -119,7 +155,7 @@ func NewCallSequenceGenerator(worker *FuzzerWorker, config *CallSequenceGenerato
 	generator := &CallSequenceGenerator{
 		worker:                  worker,
 		config:                  config,
-		mutationStrategyChooser: randomutils.NewWeightedRandomChooser[CallSequenceGeneratorMutationStrategy](),
+		mutationStrategyChooser: randomutils.NewWeightedRandomChooserWithRand[CallSequenceGeneratorMutationStrategy](randomutils.ForkRandomProvider(worker.randomProvider), &sync.Mutex{}),
 	}
 
 	generator.mutationStrategyChooser.AddChoices(
@@ -179,6 +215,48 @@ func NewCallSequenceGenerator(worker *FuzzerWorker, config *CallSequenceGenerato
 			},
 			new(big.Int).SetUint64(config.RandomMutatedInterleaveAtRandomWeight),
 		),
+		randomutils.NewWeightedRandomChoice(
+			CallSequenceGeneratorMutationStrategy{
+				CallSequenceGeneratorFunc: callSeqGenFuncDeleteAtRandom,
+				PrefetchModifyCallFunc:    nil,
+			},
+			new(big.Int).SetUint64(config.RandomDeleteAtRandomWeight),
+		),
+		randomutils.NewWeightedRandomChoice(
+			CallSequenceGeneratorMutationStrategy{
+				CallSequenceGeneratorFunc: callSeqGenFuncDuplicateAtRandom,
+				PrefetchModifyCallFunc:    nil,
+			},
+			new(big.Int).SetUint64(config.RandomDuplicateAtRandomWeight),
+		),
+		randomutils.NewWeightedRandomChoice(
+			CallSequenceGeneratorMutationStrategy{
+				CallSequenceGeneratorFunc: callSeqGenFuncSwapAtRandom,
+				PrefetchModifyCallFunc:    nil,
+			},
+			new(big.Int).SetUint64(config.RandomSwapAtRandomWeight),
+		),
+		randomutils.NewWeightedRandomChoice(
+			CallSequenceGeneratorMutationStrategy{
+				CallSequenceGeneratorFunc: callSeqGenFuncReplaceSenderAtRandom,
+				PrefetchModifyCallFunc:    nil,
+			},
+			new(big.Int).SetUint64(config.RandomReplaceSenderAtRandomWeight),
+		),
+		randomutils.NewWeightedRandomChoice(
+			CallSequenceGeneratorMutationStrategy{
+				CallSequenceGeneratorFunc: callSeqGenFuncReplaceDelayAtRandom,
+				PrefetchModifyCallFunc:    nil,
+			},
+			new(big.Int).SetUint64(config.RandomReplaceDelayAtRandomWeight),
+		),
+		randomutils.NewWeightedRandomChoice(
+			CallSequenceGeneratorMutationStrategy{
+				CallSequenceGeneratorFunc: callSeqGenFuncCopyArgumentAtRandom,
+				PrefetchModifyCallFunc:    nil,
+			},
+			new(big.Int).SetUint64(config.RandomCopyArgumentAtRandomWeight),
+		),
 	)
 
 	return generator
@@ -193,12 +271,14 @@ func (g *CallSequenceGenerator) InitializeNextSequence() (bool, error) {
 	g.baseSequence = make(calls.CallSequence, g.worker.fuzzer.config.Fuzzing.CallSequenceLength)
 	g.fetchIndex = 0
 	g.prefetchModifyCallFunc = nil
+	g.mutationTargets = nil
 
 	// Check if there are any previously un-executed corpus call sequences. If there are, the fuzzer should execute
 	// those first.
 	unexecutedSequence := g.worker.fuzzer.corpus.UnexecutedCallSequence()
 	if unexecutedSequence != nil {
-		g.baseSequence = *unexecutedSequence
+		// Copy the sequence, so elements regenerated due to unmet preconditions do not replace those in the corpus.
+		g.baseSequence = slices.Clone(*unexecutedSequence)
 		return false, nil
 	}
 
@@ -243,23 +323,42 @@ func (g *CallSequenceGenerator) PopSequenceElement() (*calls.CallSequenceElement
 	// Obtain our base call element
 	element := g.baseSequence[g.fetchIndex]
 
-	// If it is nil, we generate an entirely new call. Otherwise, we apply pre-execution modifications.
+	// If we have an element, if our generator set a post-call modify for this function, execute it now to modify
+	// our call prior to return. This allows mutations to be applied on a per-call time frame, rather than
+	// per-sequence, making use of the most recent runtime data.
 	var err error
-	if element == nil {
-		element, err = g.generateNewElement()
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		// We have an element, if our generator set a post-call modify for this function, execute it now to modify
-		// our call prior to return. This allows mutations to be applied on a per-call time frame, rather than
-		// per-sequence, making use of the most recent runtime data.
+	if element != nil {
 		if g.prefetchModifyCallFunc != nil {
 			err = g.prefetchModifyCallFunc(g, element)
 			if err != nil {
 				return nil, err
 			}
 		}
+
+		// If the element was derived from the corpus, its method's precondition may no longer be met in the current
+		// state, in which case we record the rejection and generate an entirely new call in its place.
+		method, preconditionMet, err := g.checkElementPrecondition(element)
+		if err != nil {
+			return nil, err
+		}
+		if !preconditionMet {
+			g.recordPreconditionRejection(method)
+			element = nil
+		}
+	}
+
+	// If it is nil, we generate an entirely new call.
+	if element == nil {
+		element, err = g.generateNewElement()
+		if err != nil {
+			return nil, err
+		}
+
+		// If no method's precondition is met in the current state, no call can be made, so the sequence ends here.
+		if element == nil {
+			g.baseSequence = g.baseSequence[:g.fetchIndex]
+			return nil, nil
+		}
 	}
 
 	// Update the element with the current nonce for the associated chain.
@@ -272,32 +371,159 @@ func (g *CallSequenceGenerator) PopSequenceElement() (*calls.CallSequenceElement
 }
 
 // generateNewElement generates a new call sequence element which targets a method in a contract
-// deployed to the CallSequenceGenerator's parent FuzzerWorker chain, with fuzzed call data.
-// Returns the call sequence element, or an error if one was encountered.
+// deployed to the CallSequenceGenerator's parent FuzzerWorker chain, with fuzzed call data. Only methods whose
+// precondition is met for the selected sender, in the block the call will be included in after its delays, are
+// targeted. If the randomly selected method's precondition is not met, a single rejection is recorded for it before
+// falling back to other methods.
+// Returns the call sequence element, nil if no method's precondition is met, or an error if one was encountered.
 func (g *CallSequenceGenerator) generateNewElement() (*calls.CallSequenceElement, error) {
 	// Check to make sure that we have any functions to call
 	if len(g.worker.stateChangingMethods) == 0 && len(g.worker.pureMethods) == 0 {
 		return nil, fmt.Errorf("cannot generate fuzzed call as there are no methods to call")
 	}
 
-	// Only call view functions if there are no state-changing methods
-	var callOnlyPureFunctions bool
-	if len(g.worker.stateChangingMethods) == 0 && len(g.worker.pureMethods) > 0 {
-		callOnlyPureFunctions = true
+	// Select a random sender and the block delays for the call, which preconditions are evaluated for.
+	selectedSender := g.selectSender()
+	blockNumberDelay, blockTimestampDelay := g.generateBlockDelays()
+	header := g.delayedBlockHeader(blockNumberDelay, blockTimestampDelay)
+
+	// There is a 1/100 chance that a pure method will be invoked or if there are only pure functions that are callable.
+	// Otherwise, we select among state-changing methods, falling back to pure methods if none of their preconditions
+	// are met.
+	candidateMethodSets := [][]contracts.DeployedContractMethod{g.worker.stateChangingMethods, g.worker.pureMethods}
+	if len(g.worker.stateChangingMethods) == 0 || (len(g.worker.pureMethods) > 0 && g.worker.randomProvider.Intn(100) == 0) {
+		candidateMethodSets[0], candidateMethodSets[1] = candidateMethodSets[1], candidateMethodSets[0]
+	}
+
+	// Select a random method whose precondition is met, by checking methods in a random order until we find one. Only
+	// the first method checked was selected for the call, so only its rejection is recorded.
+	rejectionRecorded := false
+	for _, candidateMethods := range candidateMethodSets {
+		for _, i := range g.worker.randomProvider.Perm(len(candidateMethods)) {
+			selectedMethod := &candidateMethods[i]
+			preconditionMet, err := g.checkPrecondition(selectedMethod, selectedSender, header)
+			if err != nil {
+				return nil, err
+			}
+			if preconditionMet {
+				// Generate a new call sequence element for the selected method.
+				return g.generateNewElementForMethod(selectedMethod, selectedSender, blockNumberDelay, blockTimestampDelay)
+			}
+			if !rejectionRecorded {
+				g.recordPreconditionRejection(selectedMethod)
+				rejectionRecorded = true
+			}
+		}
+	}
+	return nil, nil
+}
+
+// checkElementPrecondition checks the precondition of the method targeted by the provided call sequence element
+// against the current state of the CallSequenceGenerator's parent FuzzerWorker chain, for the element's sender, in the
+// block the element will be included in after its delays.
+// Returns the targeted method, a boolean indicating whether the precondition was met (or the method has none), or an
+// error if one occurred.
+func (g *CallSequenceGenerator) checkElementPrecondition(element *calls.CallSequenceElement) (*contracts.DeployedContractMethod, bool, error) {
+	// If we cannot resolve the method targeted by the element, it has no precondition.
+	method, err := element.Method()
+	if err != nil || method == nil || element.Call.To == nil || element.Contract == nil {
+		return nil, true, nil
+	}
+	deployedMethod := &contracts.DeployedContractMethod{
+		Address:      *element.Call.To,
+		Contract:     element.Contract,
+		Method:       *method,
+		Precondition: element.Contract.PreconditionMethod(method),
+	}
+	header := g.delayedBlockHeader(element.BlockNumberDelay, element.BlockTimestampDelay)
+	preconditionMet, err := g.checkPrecondition(deployedMethod, element.Call.From, header)
+	return deployedMethod, preconditionMet, err
+}
+
+// checkPrecondition calls the precondition of the provided method from the provided sender against the current state
+// of the CallSequenceGenerator's parent FuzzerWorker chain, as if it were included in a block with the provided header.
+// Returns a boolean indicating whether the precondition was met (or the method has none), or an error if one occurred.
+func (g *CallSequenceGenerator) checkPrecondition(method *contracts.DeployedContractMethod, sender common.Address, header *types.Header) (bool, error) {
+	// If the method has no precondition, it may always be called.
+	if method.Precondition == nil {
+		return true, nil
+	}
+
+	// Generate our ABI input data for the call. Precondition methods take no arguments, so the variadic argument list
+	// here is empty.
+	data, err := method.Contract.CompiledContract().Abi.Pack(method.Precondition.Name)
+	if err != nil {
+		return false, err
+	}
+
+	// Call the precondition method against the current state.
+	msg := calls.NewCallMessage(sender, &method.Address, 0, big.NewInt(0), g.worker.fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, data)
+	msg.FillFromTestChainProperties(g.worker.chain)
+	executionResult, err := g.worker.chain.CallContractWithHeader(msg.ToCoreMessage(), nil, header)
+	if err != nil {
+		return false, fmt.Errorf("failed to call precondition method: %v", err)
 	}
 
-	// Select a random method
-	// There is a 1/100 chance that a pure method will be invoked or if there are only pure functions that are callable
-	var selectedMethod *contracts.DeployedContractMethod
-	if (len(g.worker.pureMethods) > 0 && g.worker.randomProvider.Intn(100) == 0) || callOnlyPureFunctions {
-		selectedMethod = &g.worker.pureMethods[g.worker.randomProvider.Intn(len(g.worker.pureMethods))]
-	} else {
-		selectedMethod = &g.worker.stateChangingMethods[g.worker.randomProvider.Intn(len(g.worker.stateChangingMethods))]
+	// The precondition is met if it did not revert and returned true.
+	preconditionMet := false
+	if !executionResult.Failed() {
+		retVals, err := method.Precondition.Outputs.Unpack(executionResult.Return())
+		if err != nil {
+			return false, fmt.Errorf("failed to decode precondition method return value: %v", err)
+		}
+		preconditionMet, _ = retVals[0].(bool)
 	}
+	return preconditionMet, nil
+}
 
-	// Select a random sender
-	selectedSender := g.worker.fuzzer.senders[g.worker.randomProvider.Intn(len(g.worker.fuzzer.senders))]
+// recordPreconditionRejection records in the worker's metrics that the provided method was not called because its
+// precondition was not met.
+func (g *CallSequenceGenerator) recordPreconditionRejection(method *contracts.DeployedContractMethod) {
+	workerMetrics := g.worker.workerMetrics()
+	workerMetrics.preconditionRejectionsLock.Lock()
+	workerMetrics.preconditionRejections[method.Contract.Name()+"."+method.Method.Sig]++
+	workerMetrics.preconditionRejectionsLock.Unlock()
+}
 
+// delayedBlockHeader obtains the header of the block a call with the provided block number and timestamp delays will
+// be included in when executed on the CallSequenceGenerator's parent FuzzerWorker chain. A call without a block number
+// delay is included in the pending block if one exists, otherwise a new block is created after the pending block or
+// chain head, in the same manner as calls.ExecuteCallSequenceIteratively.
+// Returns the header of the block the call will be included in.
+func (g *CallSequenceGenerator) delayedBlockHeader(blockNumberDelay uint64, blockTimestampDelay uint64) *types.Header {
+	// Determine the block the new block would follow. If there is a pending block and no delay, the call is added to it.
+	parentHeader := g.worker.chain.Head().Header
+	if pendingBlock := g.worker.chain.PendingBlock(); pendingBlock != nil {
+		if blockNumberDelay == 0 {
+			return pendingBlock.Header
+		}
+		parentHeader = pendingBlock.Header
+	}
+
+	// The minimum step between blocks must be 1 in block number and timestamp, and we cannot jump more block numbers
+	// than time.
+	if blockNumberDelay == 0 {
+		blockNumberDelay = 1
+	}
+	if blockTimestampDelay == 0 {
+		blockTimestampDelay = 1
+	}
+	if blockNumberDelay > blockTimestampDelay {
+		blockNumberDelay = blockTimestampDelay
+	}
+
+	// Derive the header of the new block from its parent.
+	header := types.CopyHeader(parentHeader)
+	header.Number = new(big.Int).SetUint64(parentHeader.Number.Uint64() + blockNumberDelay)
+	header.Time = parentHeader.Time + blockTimestampDelay
+	return header
+}
+
+// generateNewElementForMethod generates a new call sequence element which targets the provided method in a contract
+// deployed to the CallSequenceGenerator's parent FuzzerWorker chain, with fuzzed call data and the provided block
+// number and timestamp delays.
+// Returns the call sequence element, or an error if one was encountered.
+func (g *CallSequenceGenerator) generateNewElementForMethod(selectedMethod *contracts.DeployedContractMethod, selectedSender common.Address, blockNumberDelay uint64, blockTimestampDelay uint64) (*calls.CallSequenceElement, error) {
 	// Generate fuzzed parameters for the function call
 	args := make([]any, len(selectedMethod.Method.Inputs))
 	for i := 0; i < len(args); i++ {
@@ -325,7 +551,19 @@ func (g *CallSequenceGenerator) generateNewElement() (*calls.CallSequenceElement
 		msg.SkipAccountChecks = true
 	}
 
-	// Determine our delay values for this element
+	// Return our call sequence element.
+	return calls.NewCallSequenceElement(selectedMethod.Contract, msg, blockNumberDelay, blockTimestampDelay), nil
+}
+
+// selectSender selects a random sender address to send a new call from.
+// Returns the selected sender address.
+func (g *CallSequenceGenerator) selectSender() common.Address {
+	return g.worker.fuzzer.senders[g.worker.randomProvider.Intn(len(g.worker.fuzzer.senders))]
+}
+
+// generateBlockDelays generates the block number and timestamp delays to use for a new call sequence element.
+// Returns the block number delay and block timestamp delay.
+func (g *CallSequenceGenerator) generateBlockDelays() (uint64, uint64) {
 	blockNumberDelay := uint64(0)
 	blockTimestampDelay := uint64(0)
 	if g.worker.fuzzer.config.Fuzzing.MaxBlockNumberDelay > 0 {
@@ -344,9 +582,19 @@ func (g *CallSequenceGenerator) generateNewElement() (*calls.CallSequenceElement
 			blockNumberDelay %= blockTimestampDelay
 		}
 	}
+	return blockNumberDelay, blockTimestampDelay
+}
 
-	// Return our call sequence element.
-	return calls.NewCallSequenceElement(selectedMethod.Contract, msg, blockNumberDelay, blockTimestampDelay), nil
+// randomMutationTargetSequence obtains a weighted random call sequence from the corpus to derive the current
+// baseSequence from, recording the corpus item it was obtained from in mutationTargets.
+// Returns the call sequence, or an error if one occurs.
+func (g *CallSequenceGenerator) randomMutationTargetSequence() (calls.CallSequence, error) {
+	sequence, fileName, err := g.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	if err != nil {
+		return nil, err
+	}
+	g.mutationTargets = append(g.mutationTargets, fileName)
+	return sequence, nil
 }
 
 // callSeqGenFuncCorpusHead is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate a sequence
@@ -354,7 +602,7 @@ func (g *CallSequenceGenerator) generateNewElement() (*calls.CallSequenceElement
 // Returns an error if one occurs.
 func callSeqGenFuncCorpusHead(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
 	// Obtain a call sequence from the corpus
-	corpusSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	corpusSequence, err := sequenceGenerator.randomMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain corpus call sequence for head mutation: %v", err)
 	}
@@ -371,7 +619,7 @@ func callSeqGenFuncCorpusHead(sequenceGenerator *CallSequenceGenerator, sequence
 // Returns an error if one occurs.
 func callSeqGenFuncCorpusTail(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
 	// Obtain a call sequence from the corpus
-	corpusSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	corpusSequence, err := sequenceGenerator.randomMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain corpus call sequence for tail mutation: %v", err)
 	}
@@ -390,11 +638,11 @@ func callSeqGenFuncCorpusTail(sequenceGenerator *CallSequenceGenerator, sequence
 // Returns an error if one occurs.
 func callSeqGenFuncSpliceAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
 	// Obtain two corpus call sequence entries
-	headSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	headSequence, err := sequenceGenerator.randomMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain head corpus call sequence for splice-at-random corpus mutation: %v", err)
 	}
-	tailSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	tailSequence, err := sequenceGenerator.randomMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain tail corpus call sequence for splice-at-random corpus mutation: %v", err)
 	}
@@ -422,11 +670,11 @@ func callSeqGenFuncSpliceAtRandom(sequenceGenerator *CallSequenceGenerator, sequ
 // Returns an error if one occurs.
 func callSeqGenFuncInterleaveAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
 	// Obtain two corpus call sequence entries
-	firstSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	firstSequence, err := sequenceGenerator.randomMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain first corpus call sequence for interleave-at-random corpus mutation: %v", err)
 	}
-	secondSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	secondSequence, err := sequenceGenerator.randomMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain second corpus call sequence for interleave-at-random corpus mutation: %v", err)
 	}
@@ -458,6 +706,249 @@ func callSeqGenFuncInterleaveAtRandom(sequenceGenerator *CallSequenceGenerator,
 	return nil
 }
 
+// copyCorpusSequenceHead obtains a call sequence from the corpus and copies as much of it as fits into the head of the
+// provided call sequence, for a CallSequenceGeneratorFunc to further modify.
+// Returns the amount of calls copied, or an error if one occurs.
+func copyCorpusSequenceHead(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) (int, error) {
+	// Obtain a call sequence from the corpus
+	corpusSequence, err := sequenceGenerator.randomMutationTargetSequence()
+	if err != nil {
+		return 0, err
+	}
+
+	// Copy as much of it as fits into the head of our destination sequence.
+	return copy(sequence, corpusSequence), nil
+}
+
+// callSeqGenFuncDeleteAtRandom is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate a
+// sequence whose head is based off of an existing corpus call sequence, with a random call deleted from it.
+// Returns an error if one occurs.
+func callSeqGenFuncDeleteAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
+	// Obtain the head of our sequence from the corpus
+	headLength, err := copyCorpusSequenceHead(sequenceGenerator, sequence)
+	if err != nil {
+		return fmt.Errorf("could not obtain corpus call sequence for delete-at-random corpus mutation: %v", err)
+	}
+	deleteCallAtRandom(sequenceGenerator, sequence, headLength)
+	return nil
+}
+
+// deleteCallAtRandom deletes a random call from the head of the provided call sequence, of the provided length.
+func deleteCallAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence, headLength int) {
+	if headLength == 0 {
+		return
+	}
+
+	// Remove a random call by shifting the calls after it down. The vacated position will be filled with a newly
+	// generated call.
+	index := sequenceGenerator.worker.randomProvider.Intn(headLength)
+	copy(sequence[index:], sequence[index+1:headLength])
+	sequence[headLength-1] = nil
+}
+
+// callSeqGenFuncDuplicateAtRandom is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate a
+// sequence whose head is based off of an existing corpus call sequence, with a copy of a random call from it inserted
+// at a random position.
+// Returns an error if one occurs.
+func callSeqGenFuncDuplicateAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
+	// Obtain the head of our sequence from the corpus
+	headLength, err := copyCorpusSequenceHead(sequenceGenerator, sequence)
+	if err != nil {
+		return fmt.Errorf("could not obtain corpus call sequence for duplicate-at-random corpus mutation: %v", err)
+	}
+	return duplicateCallAtRandom(sequenceGenerator, sequence, headLength)
+}
+
+// duplicateCallAtRandom inserts a copy of a random call from the head of the provided call sequence, of the provided
+// length, at a random position within it.
+// Returns an error if one occurs.
+func duplicateCallAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence, headLength int) error {
+	if headLength == 0 {
+		return nil
+	}
+
+	// Copy a random call, so the duplicate can be modified independently of the original.
+	duplicate, err := sequence[sequenceGenerator.worker.randomProvider.Intn(headLength)].Clone()
+	if err != nil {
+		return fmt.Errorf("could not clone call sequence element for duplicate-at-random corpus mutation: %v", err)
+	}
+
+	// Insert the duplicate at a random position, shifting the calls after it up. If our sequence is full, the last
+	// call is discarded.
+	index := sequenceGenerator.worker.randomProvider.Intn(headLength + 1)
+	if index >= len(sequence) {
+		return nil
+	}
+	copy(sequence[index+1:], sequence[index:headLength])
+	sequence[index] = duplicate
+	return nil
+}
+
+// callSeqGenFuncSwapAtRandom is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate a
+// sequence whose head is based off of an existing corpus call sequence, with the positions of two random calls in it
+// swapped.
+// Returns an error if one occurs.
+func callSeqGenFuncSwapAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
+	// Obtain the head of our sequence from the corpus
+	headLength, err := copyCorpusSequenceHead(sequenceGenerator, sequence)
+	if err != nil {
+		return fmt.Errorf("could not obtain corpus call sequence for swap-at-random corpus mutation: %v", err)
+	}
+	swapCallsAtRandom(sequenceGenerator, sequence, headLength)
+	return nil
+}
+
+// swapCallsAtRandom swaps the positions of two distinct random calls in the head of the provided call sequence, of the
+// provided length.
+func swapCallsAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence, headLength int) {
+	if headLength < 2 {
+		return
+	}
+
+	// Select two distinct calls and swap them.
+	i := sequenceGenerator.worker.randomProvider.Intn(headLength)
+	j := sequenceGenerator.worker.randomProvider.Intn(headLength - 1)
+	if j >= i {
+		j++
+	}
+	sequence[i], sequence[j] = sequence[j], sequence[i]
+}
+
+// callSeqGenFuncReplaceSenderAtRandom is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate
+// a sequence whose head is based off of an existing corpus call sequence, with the sender of a random call in it
+// replaced by a random sender.
+// Returns an error if one occurs.
+func callSeqGenFuncReplaceSenderAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
+	// Obtain the head of our sequence from the corpus
+	headLength, err := copyCorpusSequenceHead(sequenceGenerator, sequence)
+	if err != nil {
+		return fmt.Errorf("could not obtain corpus call sequence for replace-sender-at-random corpus mutation: %v", err)
+	}
+	replaceSenderAtRandom(sequenceGenerator, sequence, headLength)
+	return nil
+}
+
+// replaceSenderAtRandom replaces the sender of a random call in the head of the provided call sequence, of the provided
+// length, with a random sender.
+func replaceSenderAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence, headLength int) {
+	if headLength == 0 {
+		return
+	}
+
+	// Replace the sender of a random call. Its nonce will be updated prior to it being fetched.
+	senders := sequenceGenerator.worker.fuzzer.senders
+	element := sequence[sequenceGenerator.worker.randomProvider.Intn(headLength)]
+	element.Call.From = senders[sequenceGenerator.worker.randomProvider.Intn(len(senders))]
+}
+
+// callSeqGenFuncReplaceDelayAtRandom is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate
+// a sequence whose head is based off of an existing corpus call sequence, with the block number and timestamp delays
+// of a random call in it replaced by newly generated ones.
+// Returns an error if one occurs.
+func callSeqGenFuncReplaceDelayAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
+	// Obtain the head of our sequence from the corpus
+	headLength, err := copyCorpusSequenceHead(sequenceGenerator, sequence)
+	if err != nil {
+		return fmt.Errorf("could not obtain corpus call sequence for replace-delay-at-random corpus mutation: %v", err)
+	}
+	replaceDelayAtRandom(sequenceGenerator, sequence, headLength)
+	return nil
+}
+
+// replaceDelayAtRandom replaces the block number and timestamp delays of a random call in the head of the provided call
+// sequence, of the provided length, with newly generated ones.
+func replaceDelayAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence, headLength int) {
+	if headLength == 0 {
+		return
+	}
+
+	// Replace the delays of a random call.
+	element := sequence[sequenceGenerator.worker.randomProvider.Intn(headLength)]
+	element.BlockNumberDelay, element.BlockTimestampDelay = sequenceGenerator.generateBlockDelays()
+}
+
+// callSeqGenFuncCopyArgumentAtRandom is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate
+// a sequence whose head is based off of an existing corpus call sequence, with a random argument of one call in it
+// copied to an argument of the same type of another call in it.
+// Returns an error if one occurs.
+func callSeqGenFuncCopyArgumentAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
+	// Obtain the head of our sequence from the corpus
+	headLength, err := copyCorpusSequenceHead(sequenceGenerator, sequence)
+	if err != nil {
+		return fmt.Errorf("could not obtain corpus call sequence for copy-argument-at-random corpus mutation: %v", err)
+	}
+	return copyArgumentAtRandom(sequenceGenerator, sequence, headLength)
+}
+
+// copyArgumentAtRandom copies a random argument of one call in the head of the provided call sequence, of the provided
+// length, to an argument of the same type of another call in it. Tuple arguments are excluded.
+// Returns an error if one occurs.
+func copyArgumentAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence, headLength int) error {
+	// Collect every argument of the calls in our sequence. Tuple arguments are excluded, as values of the same tuple
+	// type may not be interchangeable between methods.
+	type callArgument struct {
+		element *calls.CallSequenceElement
+		index   int
+	}
+	arguments := make([]callArgument, 0)
+	for _, element := range sequence[:headLength] {
+		if element.Call == nil || element.Call.DataAbiValues == nil || element.Call.DataAbiValues.Method == nil {
+			continue
+		}
+		for i, input := range element.Call.DataAbiValues.Method.Inputs {
+			if !abiTypeContainsTuple(&input.Type) {
+				arguments = append(arguments, callArgument{element: element, index: i})
+			}
+		}
+	}
+	if len(arguments) < 2 {
+		return nil
+	}
+
+	// Select a random argument to replace, then a random argument of the same type from another call to copy.
+	destination := arguments[sequenceGenerator.worker.randomProvider.Intn(len(arguments))]
+	destinationType := destination.element.Call.DataAbiValues.Method.Inputs[destination.index].Type.String()
+	sources := make([]callArgument, 0)
+	for _, argument := range arguments {
+		if argument.element != destination.element && argument.element.Call.DataAbiValues.Method.Inputs[argument.index].Type.String() == destinationType {
+			sources = append(sources, argument)
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	source := sources[sequenceGenerator.worker.randomProvider.Intn(len(sources))]
+
+	// Clone the source call's values, so the copied value is not shared between calls.
+	sourceAbiValues, err := source.element.Call.DataAbiValues.Clone()
+	if err != nil {
+		return fmt.Errorf("could not clone call arguments for copy-argument-at-random corpus mutation: %v", err)
+	}
+
+	// Copy the value, restoring the original if it cannot be encoded for the destination method.
+	destinationAbiValues := destination.element.Call.DataAbiValues
+	originalValue := destinationAbiValues.InputValues[destination.index]
+	destinationAbiValues.InputValues[destination.index] = sourceAbiValues.InputValues[source.index]
+	if _, err = destinationAbiValues.Pack(); err != nil {
+		destinationAbiValues.InputValues[destination.index] = originalValue
+		return nil
+	}
+
+	// Re-encode the message's calldata
+	destination.element.Call.WithDataAbiValues(destinationAbiValues)
+	return nil
+}
+
+// abiTypeContainsTuple indicates whether the provided ABI type is a tuple, or an array or slice of tuples.
+func abiTypeContainsTuple(abiType *abi.Type) bool {
+	for t := abiType; t != nil; t = t.Elem {
+		if t.T == abi.TupleTy {
+			return true
+		}
+	}
+	return false
+}
+
 // prefetchModifyCallFuncMutate is a PrefetchModifyCallFunc, called by a CallSequenceGenerator to apply mutations
 // to a call sequence element, prior to it being fetched.
 // Returns an error if one occurs.
@@ -0,0 +1,206 @@
+package fuzzing
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// mutationTestSeeds describes the seeds of the random providers used when testing call sequence mutations, so each
+// mutation is exercised with a variety of random choices.
+var mutationTestSeeds = []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+// newTestCallSequenceGenerator creates a CallSequenceGenerator, backed by a minimal FuzzerWorker and Fuzzer, which can
+// be used to test call sequence mutations without a test chain or corpus.
+func newTestCallSequenceGenerator(seed int64, senders []common.Address) *CallSequenceGenerator {
+	fuzzer := &Fuzzer{senders: senders}
+	fuzzer.config.Fuzzing.MaxBlockNumberDelay = 10
+	fuzzer.config.Fuzzing.MaxBlockTimestampDelay = 10
+	randomProvider := rand.New(rand.NewSource(seed))
+	return &CallSequenceGenerator{
+		worker: &FuzzerWorker{fuzzer: fuzzer, randomProvider: randomProvider},
+		config: &CallSequenceGeneratorConfig{
+			ValueGenerator: valuegeneration.NewRandomValueGenerator(&valuegeneration.RandomValueGeneratorConfig{}, randomProvider),
+		},
+	}
+}
+
+// newTestCallSequence creates a call sequence of the provided length, whose head of the provided length contains calls
+// to a method taking a single uint256 argument. Each call's nonce and argument are set to its index, so calls can be
+// identified once mutated.
+func newTestCallSequence(t *testing.T, length int, headLength int) calls.CallSequence {
+	uint256Type, err := abi.NewType("uint256", "", nil)
+	assert.NoError(t, err)
+	method := abi.NewMethod("f", "f", abi.Function, "nonpayable", false, false, abi.Arguments{{Name: "x", Type: uint256Type}}, nil)
+
+	to := common.HexToAddress("0x1234")
+	sequence := make(calls.CallSequence, length)
+	for i := 0; i < headLength; i++ {
+		abiValues := &calls.CallMessageDataAbiValues{Method: &method, InputValues: []any{big.NewInt(int64(i))}}
+		msg := calls.NewCallMessageWithAbiValueData(common.HexToAddress("0x10000"), &to, uint64(i), big.NewInt(0), 0, big.NewInt(0), big.NewInt(0), big.NewInt(0), abiValues)
+		sequence[i] = calls.NewCallSequenceElement(nil, msg, 1000, 1000)
+	}
+	return sequence
+}
+
+// getCallNonces obtains the nonce of each call in the provided call sequence, or -1 for calls which are nil.
+func getCallNonces(sequence calls.CallSequence) []int {
+	nonces := make([]int, len(sequence))
+	for i, element := range sequence {
+		nonces[i] = -1
+		if element != nil {
+			nonces[i] = int(element.Call.Nonce)
+		}
+	}
+	return nonces
+}
+
+// TestDeleteCallAtRandom ensures a random call is deleted from the head of a call sequence, with the calls after it
+// shifted down, leaving the position vacated at the end of the head for a newly generated call.
+func TestDeleteCallAtRandom(t *testing.T) {
+	for _, seed := range mutationTestSeeds {
+		sequence := newTestCallSequence(t, 5, 4)
+		deleteCallAtRandom(newTestCallSequenceGenerator(seed, nil), sequence, 4)
+
+		// Check the remaining calls preserved their order, and the vacated positions are nil.
+		nonces := getCallNonces(sequence)
+		assert.EqualValues(t, []int{-1, -1}, nonces[3:])
+		assert.True(t, nonces[0] < nonces[1] && nonces[1] < nonces[2])
+	}
+
+	// Check an empty head is left untouched.
+	sequence := newTestCallSequence(t, 2, 0)
+	deleteCallAtRandom(newTestCallSequenceGenerator(0, nil), sequence, 0)
+	assert.EqualValues(t, []int{-1, -1}, getCallNonces(sequence))
+}
+
+// TestDuplicateCallAtRandom ensures a copy of a random call in the head of a call sequence is inserted at a random
+// position within it, discarding the last call if the sequence is full.
+func TestDuplicateCallAtRandom(t *testing.T) {
+	for _, seed := range mutationTestSeeds {
+		// Check duplicating a call in a sequence with space remaining extends its head.
+		sequence := newTestCallSequence(t, 4, 2)
+		err := duplicateCallAtRandom(newTestCallSequenceGenerator(seed, nil), sequence, 2)
+		assert.NoError(t, err)
+		nonces := getCallNonces(sequence)
+		assert.Contains(t, [][]int{{0, 0, 1, -1}, {0, 1, 1, -1}, {1, 0, 1, -1}, {0, 1, 0, -1}}, nonces)
+
+		// Check the duplicate is a copy, rather than the same call.
+		assert.NotSame(t, sequence[0], sequence[1])
+		assert.NotSame(t, sequence[1], sequence[2])
+		assert.NotSame(t, sequence[0], sequence[2])
+
+		// Check duplicating a call in a full sequence discards its last call, or leaves it untouched if the duplicate
+		// would be inserted past its end.
+		sequence = newTestCallSequence(t, 3, 3)
+		err = duplicateCallAtRandom(newTestCallSequenceGenerator(seed, nil), sequence, 3)
+		assert.NoError(t, err)
+		nonces = getCallNonces(sequence)
+		assert.Len(t, sequence, 3)
+		assert.Contains(t, [][]int{{0, 1, 2}, {0, 0, 1}, {1, 0, 1}, {2, 0, 1}, {0, 1, 1}, {0, 2, 1}, {0, 1, 0}}, nonces)
+	}
+}
+
+// TestSwapCallsAtRandom ensures the positions of two distinct random calls in the head of a call sequence are swapped,
+// and that a head with a single call is left untouched.
+func TestSwapCallsAtRandom(t *testing.T) {
+	for _, seed := range mutationTestSeeds {
+		// Check exactly two calls changed positions.
+		sequence := newTestCallSequence(t, 4, 3)
+		swapCallsAtRandom(newTestCallSequenceGenerator(seed, nil), sequence, 3)
+		nonces := getCallNonces(sequence)
+		assert.ElementsMatch(t, []int{0, 1, 2, -1}, nonces)
+		assert.Equal(t, -1, nonces[3])
+		changed := 0
+		for i, nonce := range nonces[:3] {
+			if nonce != i {
+				changed++
+			}
+		}
+		assert.Equal(t, 2, changed)
+
+		// Check a head with a single call is left untouched.
+		sequence = newTestCallSequence(t, 2, 1)
+		swapCallsAtRandom(newTestCallSequenceGenerator(seed, nil), sequence, 1)
+		assert.EqualValues(t, []int{0, -1}, getCallNonces(sequence))
+	}
+}
+
+// TestReplaceSenderAtRandom ensures the sender of a random call in the head of a call sequence is replaced with one of
+// the fuzzer's senders.
+func TestReplaceSenderAtRandom(t *testing.T) {
+	sender := common.HexToAddress("0x20000")
+	for _, seed := range mutationTestSeeds {
+		sequence := newTestCallSequence(t, 3, 2)
+		replaceSenderAtRandom(newTestCallSequenceGenerator(seed, []common.Address{sender}), sequence, 2)
+
+		// Check exactly one call had its sender replaced, and the sequence is otherwise untouched.
+		replaced := 0
+		for _, element := range sequence[:2] {
+			if element.Call.From == sender {
+				replaced++
+			}
+		}
+		assert.Equal(t, 1, replaced)
+		assert.EqualValues(t, []int{0, 1, -1}, getCallNonces(sequence))
+	}
+}
+
+// TestReplaceDelayAtRandom ensures the block number and timestamp delays of a random call in the head of a call
+// sequence are replaced with newly generated ones, within the configured bounds.
+func TestReplaceDelayAtRandom(t *testing.T) {
+	for _, seed := range mutationTestSeeds {
+		sequence := newTestCallSequence(t, 3, 2)
+		replaceDelayAtRandom(newTestCallSequenceGenerator(seed, nil), sequence, 2)
+
+		// Check exactly one call had its delays replaced, within the configured bounds.
+		replaced := 0
+		for _, element := range sequence[:2] {
+			if element.BlockNumberDelay == 1000 && element.BlockTimestampDelay == 1000 {
+				continue
+			}
+			replaced++
+			assert.LessOrEqual(t, element.BlockNumberDelay, uint64(10))
+			assert.LessOrEqual(t, element.BlockTimestampDelay, uint64(10))
+		}
+		assert.Equal(t, 1, replaced)
+		assert.EqualValues(t, []int{0, 1, -1}, getCallNonces(sequence))
+	}
+}
+
+// TestCopyArgumentAtRandom ensures a random argument of one call in the head of a call sequence is copied to an
+// argument of the same type of another call, with the call data of the destination call re-encoded, and that a head
+// with a single call is left untouched.
+func TestCopyArgumentAtRandom(t *testing.T) {
+	for _, seed := range mutationTestSeeds {
+		// Check both calls' arguments are equal after copying, but not shared.
+		sequence := newTestCallSequence(t, 3, 2)
+		err := copyArgumentAtRandom(newTestCallSequenceGenerator(seed, nil), sequence, 2)
+		assert.NoError(t, err)
+		first := sequence[0].Call.DataAbiValues.InputValues[0].(*big.Int)
+		second := sequence[1].Call.DataAbiValues.InputValues[0].(*big.Int)
+		assert.Zero(t, first.Cmp(second))
+		assert.NotSame(t, first, second)
+
+		// Check the call data of both calls matches their arguments.
+		for _, element := range sequence[:2] {
+			data, err := element.Call.DataAbiValues.Pack()
+			assert.NoError(t, err)
+			assert.EqualValues(t, data, element.Call.Data)
+		}
+
+		// Check a head with a single call is left untouched.
+		sequence = newTestCallSequence(t, 2, 1)
+		originalData := append([]byte{}, sequence[0].Call.Data...)
+		err = copyArgumentAtRandom(newTestCallSequenceGenerator(seed, nil), sequence, 1)
+		assert.NoError(t, err)
+		assert.EqualValues(t, originalData, sequence[0].Call.Data)
+		assert.Zero(t, sequence[0].Call.DataAbiValues.InputValues[0].(*big.Int).Sign())
+	}
+}
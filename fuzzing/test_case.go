@@ -18,6 +18,12 @@ const (
 	TestCaseStatusPassed TestCaseStatus = "PASSED"
 	// TestCaseStatusFailed describes a test status where testing has concluded and the test failed.
 	TestCaseStatusFailed TestCaseStatus = "FAILED"
+	// TestCaseStatusErrored describes a test status where testing has concluded and the test could not be evaluated
+	// (e.g. the test method reverted unexpectedly), rather than having failed.
+	TestCaseStatusErrored TestCaseStatus = "ERRORED"
+	// TestCaseStatusFlaky describes a test status where testing has concluded and the test failed or errored, but the
+	// result did not reproduce when its call sequence was replayed.
+	TestCaseStatusFlaky TestCaseStatus = "FLAKY"
 )
 
 // TestCase describes a test which is being conducted by a test provider attached to the Fuzzer.
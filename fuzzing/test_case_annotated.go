@@ -0,0 +1,120 @@
+package fuzzing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// annotatableTestCase describes a TestCase for a contract method, which may be configured through NatSpec
+// annotations on that method.
+type annotatableTestCase interface {
+	TestCase
+
+	// methodAnnotations obtains the annotations provided for the test method, or nil if it was not annotated.
+	methodAnnotations() *contracts.MethodAnnotations
+}
+
+// methodAnnotations obtains the annotations provided for the test method, or nil if it was not annotated.
+func (t *PropertyTestCase) methodAnnotations() *contracts.MethodAnnotations {
+	return t.targetContract.MethodAnnotations(&t.targetMethod)
+}
+
+// methodAnnotations obtains the annotations provided for the test method, or nil if it was not annotated.
+func (t *AssertionTestCase) methodAnnotations() *contracts.MethodAnnotations {
+	return t.targetContract.MethodAnnotations(&t.targetMethod)
+}
+
+// methodAnnotations obtains the annotations provided for the test method, or nil if it was not annotated.
+func (t *OptimizationTestCase) methodAnnotations() *contracts.MethodAnnotations {
+	return t.targetContract.MethodAnnotations(&t.targetMethod)
+}
+
+// AnnotatedTestCase describes a TestCase whose test method was configured through NatSpec annotations. It replaces
+// the underlying TestCase in the Fuzzer's results, so the annotations applied are reported alongside the result.
+type AnnotatedTestCase struct {
+	// testCase describes the underlying test case which was annotated.
+	testCase TestCase
+	// annotations describes the annotations applied to the test case.
+	annotations *contracts.MethodAnnotations
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test. If the test was expected to fail,
+// a failed result is reported as passed and vice versa.
+func (t *AnnotatedTestCase) Status() TestCaseStatus {
+	status := t.testCase.Status()
+	if t.annotations.ExpectFail {
+		if status == TestCaseStatusFailed {
+			return TestCaseStatusPassed
+		} else if status == TestCaseStatusPassed {
+			return TestCaseStatusFailed
+		}
+	}
+	return status
+}
+
+// CallSequence describes the calls.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *AnnotatedTestCase) CallSequence() *calls.CallSequence {
+	return t.testCase.CallSequence()
+}
+
+// Name describes the name of the test case.
+func (t *AnnotatedTestCase) Name() string {
+	return t.testCase.Name()
+}
+
+// LogMessage obtains a buffer that represents the result of the AnnotatedTestCase. This buffer can be passed to a logger
+// for console or file logging.
+func (t *AnnotatedTestCase) LogMessage() *logging.LogBuffer {
+	buffer := logging.NewLogBuffer()
+
+	// If the status was not inverted, we simply list the annotations after the original result.
+	status := t.Status()
+	if status == t.testCase.Status() {
+		buffer.Append(t.testCase.LogMessage().Elements()...)
+		if !strings.HasSuffix(t.testCase.LogMessage().String(), "\n") {
+			buffer.Append("\n")
+		}
+		buffer.Append(colors.Bold, "[Annotations] ", colors.Reset, t.annotations.String(), "\n")
+		return buffer
+	}
+
+	// Otherwise, report the test was expected to fail, alongside its original result.
+	if status == TestCaseStatusPassed {
+		buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", status), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append("Test failed as expected.\n")
+	} else {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", status), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append("Test was expected to fail, but did not.\n")
+	}
+	buffer.Append(colors.Bold, "[Annotations] ", colors.Reset, t.annotations.String(), "\n")
+	buffer.Append(colors.Bold, "[Original Result]", colors.Reset, "\n")
+	buffer.Append(t.testCase.LogMessage().Elements()...)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the AnnotatedTestCase.
+func (t *AnnotatedTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *AnnotatedTestCase) ID() string {
+	return t.testCase.ID()
+}
+
+// OriginalTestCase obtains the underlying TestCase which was annotated.
+func (t *AnnotatedTestCase) OriginalTestCase() TestCase {
+	return t.testCase
+}
+
+// Annotations obtains the annotations applied to the test case.
+func (t *AnnotatedTestCase) Annotations() *contracts.MethodAnnotations {
+	return t.annotations
+}
@@ -21,6 +21,9 @@ type AssertionTestCase struct {
 	targetMethod abi.Method
 	// callSequence describes the call sequence that broke the assertion
 	callSequence *calls.CallSequence
+	// failureReason describes the reason the assertion failed, if it was not signalled by a panic (e.g. an event or
+	// custom error signalling an assertion failure was encountered).
+	failureReason string
 }
 
 // Status describes the TestCaseStatus used to define the current state of the test.
@@ -47,6 +50,9 @@ func (t *AssertionTestCase) LogMessage() *logging.LogBuffer {
 	if t.Status() == TestCaseStatusFailed {
 		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
 		buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" resulted in an assertion failure after the following call sequence:\n", t.targetContract.Name(), t.targetMethod.Sig))
+		if t.failureReason != "" {
+			buffer.Append(colors.Bold, "[Failure Reason]", colors.Reset, " ", t.failureReason, "\n")
+		}
 		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
 		buffer.Append(t.CallSequence().Log().Elements()...)
 		return buffer
@@ -1,6 +1,8 @@
 package fuzzing
 
 import (
+	"encoding/hex"
+	"fmt"
 	"math/big"
 	"sync"
 
@@ -8,6 +10,9 @@ import (
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/config"
 	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/common"
+	coreTypes "github.com/ethereum/go-ethereum/core/types"
 
 	"golang.org/x/exp/slices"
 )
@@ -24,13 +29,33 @@ type AssertionTestCaseProvider struct {
 
 	// testCasesLock is used for thread-synchronization when updating testCases
 	testCasesLock sync.Mutex
+
+	// failOnEventIds is a mapping of event IDs (the first topic of an event log) to the event signatures which, if
+	// emitted during a call, signal an assertion failure.
+	failOnEventIds map[common.Hash]string
+
+	// failOnCustomErrorSelectors is a mapping of custom error selectors to the custom error signatures which, if a
+	// call reverts with them, signal an assertion failure.
+	failOnCustomErrorSelectors map[[4]byte]string
 }
 
 // attachAssertionTestCaseProvider attaches a new AssertionTestCaseProvider to the Fuzzer and returns it.
 func attachAssertionTestCaseProvider(fuzzer *Fuzzer) *AssertionTestCaseProvider {
 	// Create a test case provider
 	t := &AssertionTestCaseProvider{
-		fuzzer: fuzzer,
+		fuzzer:                     fuzzer,
+		failOnEventIds:             make(map[common.Hash]string),
+		failOnCustomErrorSelectors: make(map[[4]byte]string),
+	}
+
+	// Compute the IDs of any events which signal an assertion failure.
+	for _, eventSignature := range fuzzer.config.Fuzzing.Testing.AssertionTesting.FailOnEvents {
+		t.failOnEventIds[abiutils.GetSignatureHash(eventSignature)] = eventSignature
+	}
+
+	// Compute the selectors of any custom errors which signal an assertion failure.
+	for _, errorSignature := range fuzzer.config.Fuzzing.Testing.AssertionTesting.FailOnCustomErrors {
+		t.failOnCustomErrorSelectors[[4]byte(abiutils.GetSignatureHash(errorSignature).Bytes()[:4])] = errorSignature
 	}
 
 	// Subscribe the provider to relevant events the fuzzer emits.
@@ -44,18 +69,19 @@ func attachAssertionTestCaseProvider(fuzzer *Fuzzer) *AssertionTestCaseProvider
 }
 
 // checkAssertionFailures checks the results of the last call for assertion failures.
-// Returns the method ID, a boolean indicating if an assertion test failed, or an error if one occurs.
-func (t *AssertionTestCaseProvider) checkAssertionFailures(callSequence calls.CallSequence) (*contracts.ContractMethodID, bool, error) {
+// Returns the method ID, a boolean indicating if an assertion test failed, a description of the failure reason if it
+// is not a panic, or an error if one occurs.
+func (t *AssertionTestCaseProvider) checkAssertionFailures(callSequence calls.CallSequence) (*contracts.ContractMethodID, bool, string, error) {
 	// If we have an empty call sequence, we cannot have an assertion failure
 	if len(callSequence) == 0 {
-		return nil, false, nil
+		return nil, false, "", nil
 	}
 
 	// Obtain the contract and method from the last call made in our sequence
 	lastCall := callSequence[len(callSequence)-1]
 	lastCallMethod, err := lastCall.Method()
 	if err != nil {
-		return nil, false, err
+		return nil, false, "", err
 	}
 	methodId := contracts.GetContractMethodID(lastCall.Contract, lastCallMethod)
 
@@ -70,8 +96,70 @@ func (t *AssertionTestCaseProvider) checkAssertionFailures(callSequence calls.Ca
 	if panicCode != nil {
 		failure = encounteredAssertionFailure(panicCode.Uint64(), t.fuzzer.config.Fuzzing.Testing.AssertionTesting.PanicCodeConfig)
 	}
+	if failure {
+		return &methodId, true, "", nil
+	}
+
+	// Check if the call reverted with a custom error signalling an assertion failure.
+	if len(t.failOnCustomErrorSelectors) > 0 && lastExecutionResult.Failed() && len(lastExecutionResult.ReturnData) >= 4 {
+		if errorSignature, isFailureError := t.failOnCustomErrorSelectors[[4]byte(lastExecutionResult.ReturnData[:4])]; isFailureError {
+			return &methodId, true, fmt.Sprintf("reverted with error %v", t.describeCustomError(errorSignature, lastCall.Contract, lastExecutionResult.Err, lastExecutionResult.ReturnData)), nil
+		}
+	}
+
+	// Check if any event signalling an assertion failure was emitted. The receipt contains logs emitted by all call
+	// frames which did not revert.
+	if len(t.failOnEventIds) > 0 {
+		lastReceipt := lastCall.ChainReference.MessageResults().Receipt
+		if lastReceipt != nil {
+			for _, eventLog := range lastReceipt.Logs {
+				if len(eventLog.Topics) == 0 {
+					continue
+				}
+				if eventSignature, isFailureEvent := t.failOnEventIds[eventLog.Topics[0]]; isFailureEvent {
+					return &methodId, true, fmt.Sprintf("emitted event %v", t.describeEventLog(eventSignature, eventLog)), nil
+				}
+			}
+		}
+	}
 
-	return &methodId, failure, nil
+	return &methodId, false, "", nil
+}
+
+// describeCustomError obtains a string describing the custom error in the provided return data with its decoded
+// arguments, resolving the error definition from the called contract, or any contract definition known to the fuzzer.
+// If it cannot be resolved, the provided error signature is returned alongside the raw return data.
+func (t *AssertionTestCaseProvider) describeCustomError(errorSignature string, calledContract *contracts.Contract, returnError error, returnData []byte) string {
+	candidateContracts := t.fuzzer.ContractDefinitions()
+	if calledContract != nil {
+		candidateContracts = append(contracts.Contracts{calledContract}, candidateContracts...)
+	}
+	for _, contract := range candidateContracts {
+		customError, customErrorArgs := abiutils.GetSolidityCustomRevertError(&contract.CompiledContract().Abi, returnError, returnData)
+		if customError != nil && abiutils.GetSignatureHash(customError.Sig) == abiutils.GetSignatureHash(errorSignature) {
+			customErrorArgsString, err := valuegeneration.EncodeABIArgumentsToString(customError.Inputs, customErrorArgs)
+			if err == nil {
+				return fmt.Sprintf("%v(%v)", customError.Name, customErrorArgsString)
+			}
+		}
+	}
+	return fmt.Sprintf("%v <unresolved(data=%v)>", errorSignature, hex.EncodeToString(returnData))
+}
+
+// describeEventLog obtains a string describing the provided event log with its decoded arguments, resolving the event
+// definition from the contract definitions known to the fuzzer. If it cannot be resolved, the provided event signature
+// is returned alongside the raw event data.
+func (t *AssertionTestCaseProvider) describeEventLog(eventSignature string, eventLog *coreTypes.Log) string {
+	for _, contract := range t.fuzzer.ContractDefinitions() {
+		event, eventInputValues := abiutils.UnpackEventAndValues(&contract.CompiledContract().Abi, eventLog)
+		if event != nil {
+			encodedEventValuesString, err := valuegeneration.EncodeABIArgumentsToString(event.Inputs, eventInputValues)
+			if err == nil {
+				return fmt.Sprintf("%v(%v)", event.Name, encodedEventValuesString)
+			}
+		}
+	}
+	return fmt.Sprintf("%v <unresolved(data=%v)>", eventSignature, hex.EncodeToString(eventLog.Data))
 }
 
 // onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
@@ -166,7 +254,7 @@ func (t *AssertionTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorke
 	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
 
 	// Obtain the method ID for the last call and check if it encountered assertion failures.
-	methodId, testFailed, err := t.checkAssertionFailures(callSequence)
+	methodId, testFailed, _, err := t.checkAssertionFailures(callSequence)
 	if err != nil {
 		return nil, err
 	}
@@ -181,8 +269,8 @@ func (t *AssertionTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorke
 		return shrinkRequests, nil
 	}
 
-	// If the test case already failed, skip it
-	if testCase.Status() == TestCaseStatusFailed {
+	// If the test case already failed, or the call sequence does not apply to it, skip it
+	if testCase.Status() == TestCaseStatusFailed || !t.fuzzer.callSequenceAllowed(testCase.targetContract, &testCase.targetMethod, callSequence) {
 		return shrinkRequests, nil
 	}
 
@@ -193,7 +281,7 @@ func (t *AssertionTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorke
 		shrinkRequest := ShrinkCallSequenceRequest{
 			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
 				// Obtain the method ID for the last call and check if it encountered assertion failures.
-				shrunkSeqMethodId, shrunkSeqTestFailed, err := t.checkAssertionFailures(shrunkenCallSequence)
+				shrunkSeqMethodId, shrunkSeqTestFailed, _, err := t.checkAssertionFailures(shrunkenCallSequence)
 				if err != nil {
 					return false, err
 				}
@@ -201,7 +289,7 @@ func (t *AssertionTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorke
 				// If we encountered assertion failures on the same method, this shrunk sequence is satisfactory.
 				return shrunkSeqTestFailed && *methodId == *shrunkSeqMethodId, nil
 			},
-			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error {
 				// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
 				if len(shrunkenCallSequence) > 0 {
 					_, err = calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
@@ -210,14 +298,22 @@ func (t *AssertionTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorke
 					}
 				}
 
+				// Obtain the reason for the failure from the final execution of the shrunken sequence.
+				_, _, failureReason, err := t.checkAssertionFailures(shrunkenCallSequence)
+				if err != nil {
+					return err
+				}
+
 				// Update our test state and report it finalized.
 				testCase.status = TestCaseStatusFailed
 				testCase.callSequence = &shrunkenCallSequence
+				testCase.failureReason = failureReason
 				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
-				worker.Fuzzer().ReportTestCaseFinished(testCase)
+				worker.reportTestCaseFinished(testCase, flakiness)
 				return nil
 			},
 			RecordResultInCorpus: true,
+			CheckReproducibility: true,
 		}
 
 		// Add our shrink request to our list.
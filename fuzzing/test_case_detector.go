@@ -0,0 +1,75 @@
+package fuzzing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/detectors"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// DetectorTestCase describes a test being run by a DetectorTestCaseProvider.
+type DetectorTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// detector describes the detector which inspects execution traces for the test case
+	detector detectors.Detector
+	// callSequence describes the call sequence whose last call triggered the detector
+	callSequence *calls.CallSequence
+	// finding describes the vulnerable pattern reported by the detector for the last call in the call sequence. Its
+	// call frame is highlighted in the execution trace of that call.
+	finding *detectors.Finding
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *DetectorTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *DetectorTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// Name describes the name of the test case.
+func (t *DetectorTestCase) Name() string {
+	return fmt.Sprintf("Detector: %s", t.detector.Name())
+}
+
+// LogMessage obtains a buffer that represents the result of the DetectorTestCase. This buffer can be passed to a logger
+// for console or file logging.
+func (t *DetectorTestCase) LogMessage() *logging.LogBuffer {
+	// If the test failed, return a failure message.
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Detector \"%s\" was triggered by the last call in the following call sequence:\n", t.detector.Name()))
+		if t.finding != nil {
+			buffer.Append(colors.Bold, "[Finding]", colors.Reset, " ", t.finding.Description, "\n")
+		}
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(t.CallSequence().Log().Elements()...)
+		return buffer
+	}
+	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the DetectorTestCase.
+func (t *DetectorTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *DetectorTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("DETECTOR-%s", t.detector.ID()), "_", "-", -1)
+}
+
+// Finding describes the vulnerable pattern reported by the detector, if the test failed.
+func (t *DetectorTestCase) Finding() *detectors.Finding {
+	return t.finding
+}
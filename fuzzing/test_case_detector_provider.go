@@ -0,0 +1,238 @@
+package fuzzing
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/detectors"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+)
+
+// DetectorTestCaseProvider is a DetectorTestCase provider which spawns a test case for every enabled detector. It
+// records an execution trace for every call the fuzzer makes, and reports the call sequence if a detector finds a
+// vulnerable pattern in the trace of its last call.
+type DetectorTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// detectors describes the enabled detectors.
+	detectors []detectors.Detector
+
+	// testCases is a list of detector test cases, one for each enabled detector.
+	testCases []*DetectorTestCase
+
+	// testCasesLock is used for thread-synchronization when updating testCases
+	testCasesLock sync.Mutex
+}
+
+// attachDetectorTestCaseProvider attaches a new DetectorTestCaseProvider to the Fuzzer and returns it.
+func attachDetectorTestCaseProvider(fuzzer *Fuzzer) *DetectorTestCaseProvider {
+	// Create a test case provider
+	t := &DetectorTestCaseProvider{
+		fuzzer: fuzzer,
+	}
+
+	// Determine which detectors are enabled.
+	detectorConfig := fuzzer.config.Fuzzing.Testing.DetectorTesting
+	if detectorConfig.DetectReentrancy {
+		t.detectors = append(t.detectors, &detectors.ReentrancyDetector{})
+	}
+	if detectorConfig.DetectArbitraryCall {
+		t.detectors = append(t.detectors, &detectors.ArbitraryCallDetector{})
+	}
+	if detectorConfig.DetectSelfDestruct {
+		t.detectors = append(t.detectors, &detectors.SelfDestructDetector{})
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.Subscribe(t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.Subscribe(t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.Subscribe(t.onWorkerCreated)
+
+	// Add the provider's call sequence test function to the fuzzer, and attach execution tracers to the chains call
+	// sequences are replayed on, as our verifiers rely on them.
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.callSequencePostCallTest)
+	fuzzer.replayChainTracerFuncs = append(fuzzer.replayChainTracerFuncs, t.attachExecutionTracer)
+	return t
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
+// in a "not started" state for every enabled detector.
+func (t *DetectorTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make([]*DetectorTestCase, 0)
+
+	// Create a test case for every detector and register it with the fuzzer.
+	for _, detector := range t.detectors {
+		testCase := &DetectorTestCase{
+			status:       TestCaseStatusNotStarted,
+			detector:     detector,
+			callSequence: nil,
+		}
+		t.testCases = append(t.testCases, testCase)
+		t.fuzzer.RegisterTestCase(testCase)
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *DetectorTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			testCase.status = TestCaseStatusPassed
+		}
+	}
+	return nil
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It subscribes to
+// relevant worker events.
+func (t *DetectorTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	// Subscribe to relevant worker events.
+	event.Worker.Events.FuzzerWorkerChainCreated.Subscribe(t.onWorkerChainCreated)
+	event.Worker.Events.ContractAdded.Subscribe(t.onWorkerDeployedContractAdded)
+	return nil
+}
+
+// onWorkerChainCreated is the event handler triggered when a FuzzerWorker creates its underlying chain. If any detector
+// is enabled, it attaches an execution tracer to the chain, so an execution trace is recorded for every call the worker
+// makes.
+func (t *DetectorTestCaseProvider) onWorkerChainCreated(event FuzzerWorkerChainCreatedEvent) error {
+	t.attachExecutionTracer(event.Worker, event.Chain)
+	return nil
+}
+
+// attachExecutionTracer attaches an execution tracer to the provided chain of a FuzzerWorker if any detector is
+// enabled, so an execution trace is recorded for every call made on it.
+func (t *DetectorTestCaseProvider) attachExecutionTracer(worker *FuzzerWorker, testChain *chain.TestChain) {
+	// If no detector is enabled, there are no traces to inspect, so we avoid the cost of recording them.
+	if len(t.detectors) == 0 {
+		return
+	}
+	executionTracer := executiontracer.NewExecutionTracer(t.fuzzer.contractDefinitions, testChain.CheatCodeContracts())
+	testChain.AddTracer(executionTracer.NativeTracer(), true, false)
+}
+
+// onWorkerDeployedContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment
+// on its underlying chain. Any test cases which are in a "not started" state are put into a "running" state, as
+// there are now contracts to test.
+func (t *DetectorTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	t.testCasesLock.Lock()
+	defer t.testCasesLock.Unlock()
+	for _, testCase := range t.testCases {
+		if testCase.Status() == TestCaseStatusNotStarted {
+			testCase.status = TestCaseStatusRunning
+		}
+	}
+	return nil
+}
+
+// detect runs the provided detector against the execution trace recorded for the last call in the provided call
+// sequence.
+// Returns a Finding describing the vulnerable pattern found, or nil if none was found.
+func (t *DetectorTestCaseProvider) detect(detector detectors.Detector, callSequence calls.CallSequence) *detectors.Finding {
+	// If we have an empty call sequence, there is no trace to inspect.
+	if len(callSequence) == 0 {
+		return nil
+	}
+
+	// Obtain the execution trace recorded for the last call, if any.
+	lastCall := callSequence[len(callSequence)-1]
+	if lastCall.ChainReference == nil {
+		return nil
+	}
+	trace := executiontracer.GetExecutionTracerResults(lastCall.ChainReference.MessageResults())
+	return detector.Detect(trace)
+}
+
+// removeExecutionTraces removes the execution traces recorded for the calls in the provided call sequence, once the
+// detectors have inspected them, so they are not retained in the message results of the worker's chain.
+func removeExecutionTraces(callSequence calls.CallSequence) {
+	for _, element := range callSequence {
+		if element.ChainReference != nil {
+			executiontracer.RemoveExecutionTracerResults(element.ChainReference.MessageResults())
+		}
+	}
+}
+
+// callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached
+// Fuzzer and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether
+// any enabled detector finds a vulnerable pattern in the execution trace of the last call.
+func (t *DetectorTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate for each failed test we want a call sequence
+	// shrunk for.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	// Obtain a list of test cases to check.
+	t.testCasesLock.Lock()
+	testCases := append([]*DetectorTestCase{}, t.testCases...)
+	t.testCasesLock.Unlock()
+
+	for _, testCase := range testCases {
+		// Create local variables to avoid pointer types in the loop being overridden.
+		testCase := testCase
+
+		// If the test case already failed, or the detector found nothing, skip it
+		if testCase.Status() == TestCaseStatusFailed || t.detect(testCase.detector, callSequence) == nil {
+			continue
+		}
+
+		// We provide a shrink verifier which will update the call sequence for each shrunken sequence provided that
+		// still triggers the detector.
+		shrinkRequest := ShrinkCallSequenceRequest{
+			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+				detected := t.detect(testCase.detector, shrunkenCallSequence) != nil
+				removeExecutionTraces(shrunkenCallSequence)
+				return detected, nil
+			},
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error {
+				// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
+				if len(shrunkenCallSequence) > 0 {
+					_, err := calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
+					if err != nil {
+						return err
+					}
+				}
+
+				// Run the detector against the attached execution trace, so the offending call frame can be
+				// highlighted within it.
+				var finding *detectors.Finding
+				if len(shrunkenCallSequence) > 0 {
+					lastCallTrace := shrunkenCallSequence[len(shrunkenCallSequence)-1].ExecutionTrace
+					finding = testCase.detector.Detect(lastCallTrace)
+					if finding != nil {
+						lastCallTrace.HighlightedCallFrame = finding.CallFrame
+					}
+				}
+				if finding == nil {
+					finding = t.detect(testCase.detector, shrunkenCallSequence)
+				}
+				removeExecutionTraces(shrunkenCallSequence)
+
+				// Update our test state and report it finalized.
+				testCase.status = TestCaseStatusFailed
+				testCase.callSequence = &shrunkenCallSequence
+				testCase.finding = finding
+				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+				worker.reportTestCaseFinished(testCase, flakiness)
+				return nil
+			},
+			RecordResultInCorpus: true,
+			CheckReproducibility: true,
+		}
+
+		// Add our shrink request to our list.
+		shrinkRequests = append(shrinkRequests, shrinkRequest)
+	}
+
+	// Every detector has inspected the execution trace of the last call, so we remove it. The traces of prior calls
+	// were removed when they were tested.
+	if len(callSequence) > 0 {
+		removeExecutionTraces(callSequence[len(callSequence)-1:])
+	}
+	return shrinkRequests, nil
+}
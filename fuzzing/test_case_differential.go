@@ -0,0 +1,85 @@
+package fuzzing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// DifferentialTestCase describes a test being run by a DifferentialTestCaseProvider.
+type DifferentialTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// pair describes the reference and candidate contracts or methods whose behavior is compared by the test case.
+	pair config.DifferentialTestPairConfig
+	// callSequence describes the call sequence which resulted in divergent behavior
+	callSequence *calls.CallSequence
+	// divergence describes the behavior which differed between the reference and candidate.
+	divergence string
+	// referenceTrace describes the execution trace of the final call in the call sequence against the reference.
+	referenceTrace *executiontracer.ExecutionTrace
+	// candidateTrace describes the execution trace of the final call in the call sequence against the candidate.
+	candidateTrace *executiontracer.ExecutionTrace
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *DifferentialTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *DifferentialTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// Name describes the name of the test case.
+func (t *DifferentialTestCase) Name() string {
+	return fmt.Sprintf("Differential Test: %s vs %s", t.pair.Reference, t.pair.Candidate)
+}
+
+// LogMessage obtains a buffer that represents the result of the DifferentialTestCase. This buffer can be passed to a
+// logger for console or file logging.
+func (t *DifferentialTestCase) LogMessage() *logging.LogBuffer {
+	// If the test failed, return a failure message.
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Behavior of \"%s\" diverged from \"%s\" after the following call sequence:\n", t.pair.Candidate, t.pair.Reference))
+		buffer.Append(colors.Bold, "[Divergence]", colors.Reset, " ", t.divergence, "\n")
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(t.CallSequence().Log().Elements()...)
+		if t.referenceTrace != nil {
+			buffer.Append(colors.Bold, "[Reference Execution Trace]", colors.Reset, "\n")
+			buffer.Append(t.referenceTrace.Log().Elements()...)
+		}
+		if t.candidateTrace != nil {
+			buffer.Append(colors.Bold, "[Candidate Execution Trace]", colors.Reset, "\n")
+			buffer.Append(t.candidateTrace.Log().Elements()...)
+		}
+		return buffer
+	}
+	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the DifferentialTestCase.
+func (t *DifferentialTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *DifferentialTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("DIFFERENTIAL-%s-%s", t.pair.Reference, t.pair.Candidate), "_", "-", -1)
+}
+
+// Divergence describes the behavior which differed between the reference and candidate, if the test failed.
+func (t *DifferentialTestCase) Divergence() string {
+	return t.divergence
+}
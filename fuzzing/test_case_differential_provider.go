@@ -0,0 +1,673 @@
+package fuzzing
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/crytic/medusa/chain"
+	chainTypes "github.com/crytic/medusa/chain/types"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	coreTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// maxDifferentialLogDataSize describes the maximum size of event data captured when comparing the events emitted by a
+// reference and candidate. Any LOG instruction with a larger data size would exhaust the gas available to a call.
+const maxDifferentialLogDataSize = 1 << 24
+
+// DifferentialTestCaseProvider is a DifferentialTestCase provider which spawns a test case for every pair of
+// contracts or methods configured for differential testing. Every call the fuzzer makes to a reference is executed
+// against the reference and its candidate from the state prior to the call, and the return data, revert status,
+// emitted events and selected storage slots of both executions are compared. Every call made to a reference contract
+// is also mirrored onto its candidate, so the storage of both evolves identically throughout a call sequence. The
+// fuzzer does not call candidates directly.
+type DifferentialTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// testCases is a list of differential test cases, one for each configured pair.
+	testCases []*DifferentialTestCase
+
+	// testCasesLock is used for thread-synchronization when updating testCases
+	testCasesLock sync.Mutex
+
+	// storageSlots describes the storage keys to compare for each configured pair, indexed the same as testCases.
+	storageSlots [][]common.Hash
+
+	// shadowStates describes the state each worker's call sequence and its mirrored calls were executed over, for
+	// each configured pair. It is indexed by worker index, then the same as testCases.
+	shadowStates [][]*differentialShadowState
+}
+
+// differentialShadowState describes a copy of the state a call sequence was executed over, detached from the chain,
+// in which every call made to the reference of a differential test pair was also made to its candidate.
+type differentialShadowState struct {
+	// state describes the state after executing elements and their mirrored calls.
+	state *state.StateDB
+
+	// elements describes the call sequence elements executed over state.
+	elements calls.CallSequence
+
+	// blocks describes the block each of the elements was included in when state was updated, used to detect whether
+	// the elements were executed again since.
+	blocks []*chainTypes.Block
+
+	// diverged indicates whether any of the elements changed the state of the reference through a method which could
+	// not be mirrored onto the candidate, so the state of both sides may no longer be compared.
+	diverged bool
+}
+
+// differentialCallTarget describes the message and method for a call to one side of a differential test pair.
+type differentialCallTarget struct {
+	// msg describes the message used to call the reference or candidate.
+	msg *core.Message
+
+	// method describes the method called on the reference or candidate.
+	method *abi.Method
+}
+
+// differentialCallResult describes the behavior of a call to one side of a differential test pair which is compared
+// against the other side.
+type differentialCallResult struct {
+	// executionResult describes the result of the call.
+	executionResult *core.ExecutionResult
+
+	// logs describes the events emitted by call frames which did not revert.
+	logs []*coreTypes.Log
+
+	// storage describes the values of the compared storage slots in the called contract after the call.
+	storage []common.Hash
+}
+
+// attachDifferentialTestCaseProvider attaches a new DifferentialTestCaseProvider to the Fuzzer and returns it.
+func attachDifferentialTestCaseProvider(fuzzer *Fuzzer) (*DifferentialTestCaseProvider, error) {
+	// Create a test case provider
+	t := &DifferentialTestCaseProvider{
+		fuzzer: fuzzer,
+	}
+
+	// Parse the storage slots to compare for each pair.
+	for _, pair := range fuzzer.config.Fuzzing.Testing.DifferentialTesting.Pairs {
+		storageSlots, err := pair.StorageSlotKeys()
+		if err != nil {
+			return nil, err
+		}
+		t.storageSlots = append(t.storageSlots, storageSlots)
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.Subscribe(t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.Subscribe(t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.Subscribe(t.onWorkerCreated)
+
+	// Add the provider's call sequence test function to the fuzzer.
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.callSequencePostCallTest)
+	return t, nil
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
+// in a "not started" state for every configured differential test pair.
+func (t *DifferentialTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make([]*DifferentialTestCase, 0)
+	t.shadowStates = make([][]*differentialShadowState, t.fuzzer.config.Fuzzing.Workers)
+
+	// Create a test case for every pair and register it with the fuzzer.
+	for _, pair := range t.fuzzer.config.Fuzzing.Testing.DifferentialTesting.Pairs {
+		testCase := &DifferentialTestCase{
+			status:       TestCaseStatusNotStarted,
+			pair:         pair,
+			callSequence: nil,
+		}
+		t.testCases = append(t.testCases, testCase)
+		t.fuzzer.RegisterTestCase(testCase)
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *DifferentialTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			testCase.status = TestCaseStatusPassed
+		}
+	}
+	return nil
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It subscribes to
+// relevant worker events.
+func (t *DifferentialTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	// Subscribe to relevant worker events.
+	event.Worker.Events.ContractAdded.Subscribe(t.onWorkerDeployedContractAdded)
+	return nil
+}
+
+// onWorkerDeployedContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment
+// on its underlying chain. Any test cases whose reference was deployed which are in a "not started" state are put into
+// a "running" state, as they are now potentially reachable for testing.
+func (t *DifferentialTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	// If we don't have a contract definition, we can't run tests against the contract.
+	if event.ContractDefinition == nil {
+		return nil
+	}
+
+	t.testCasesLock.Lock()
+	defer t.testCasesLock.Unlock()
+	for _, testCase := range t.testCases {
+		referenceContractName, _ := testCase.pair.ReferenceContractAndMethod()
+		if referenceContractName == event.ContractDefinition.Name() && testCase.Status() == TestCaseStatusNotStarted {
+			testCase.status = TestCaseStatusRunning
+		}
+	}
+	return nil
+}
+
+// resolveCallTargets resolves the calls to the reference and candidate of the differential test pair at the provided
+// index, which mirror the provided call sequence element.
+// Returns the reference and candidate call targets, or nil targets if the element does not call the reference or the
+// candidate is not deployed. Returns an error if one occurs.
+func (t *DifferentialTestCaseProvider) resolveCallTargets(worker *FuzzerWorker, pairIndex int, element *calls.CallSequenceElement) (*differentialCallTarget, *differentialCallTarget, error) {
+	// Obtain the reference method called by the element, if any.
+	elementMethod, err := t.referenceMethodCalled(pairIndex, element)
+	if err != nil || elementMethod == nil {
+		return nil, nil, err
+	}
+
+	// Verify the element calls the reference method of the pair, if one was configured.
+	pair := t.testCases[pairIndex].pair
+	_, referenceMethodSig := pair.ReferenceContractAndMethod()
+	if referenceMethodSig != "" && referenceMethodSig != elementMethod.Sig {
+		return nil, nil, nil
+	}
+
+	// Resolve the candidate method, which shares the signature of the reference method unless one was configured.
+	_, candidateMethodSig := pair.CandidateContractAndMethod()
+	if candidateMethodSig == "" {
+		candidateMethodSig = elementMethod.Sig
+	}
+	candidateTarget, err := t.resolveCandidateTarget(worker, pairIndex, element, elementMethod, candidateMethodSig)
+	if err != nil || candidateTarget == nil {
+		return nil, nil, err
+	}
+
+	// The reference is called with the message of the element itself. Neither message is subject to nonce checks, as
+	// mirrored calls advance the sender's nonce only in the shadow state.
+	referenceMsg := element.Call.ToCoreMessage()
+	referenceMsg.SkipAccountChecks = true
+	return &differentialCallTarget{msg: referenceMsg, method: elementMethod}, candidateTarget, nil
+}
+
+// resolveMirroredCall resolves the call to the candidate of the differential test pair at the provided index which
+// mirrors the provided call sequence element, so the state of both sides evolves identically. Calls to the configured
+// reference method are mirrored onto the configured candidate method, while calls to any other method of the reference
+// contract are mirrored onto the candidate method with the same signature.
+// Returns the candidate call target, or nil if the element does not call the reference or the candidate is not
+// deployed. Also returns a boolean indicating whether the element may have changed the state of the reference without
+// being mirrored. Returns an error if one occurs.
+func (t *DifferentialTestCaseProvider) resolveMirroredCall(worker *FuzzerWorker, pairIndex int, element *calls.CallSequenceElement) (*differentialCallTarget, bool, error) {
+	// Obtain the reference method called by the element, if any.
+	elementMethod, err := t.referenceMethodCalled(pairIndex, element)
+	if err != nil || elementMethod == nil {
+		return nil, false, err
+	}
+
+	// Resolve the candidate method to mirror the call onto.
+	pair := t.testCases[pairIndex].pair
+	_, referenceMethodSig := pair.ReferenceContractAndMethod()
+	candidateMethodSig := elementMethod.Sig
+	if _, configuredCandidateMethodSig := pair.CandidateContractAndMethod(); referenceMethodSig == elementMethod.Sig && configuredCandidateMethodSig != "" {
+		candidateMethodSig = configuredCandidateMethodSig
+	}
+	candidateTarget, err := t.resolveCandidateTarget(worker, pairIndex, element, elementMethod, candidateMethodSig)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// If the call could not be mirrored, it may have changed the state of the reference unless the method is constant.
+	return candidateTarget, candidateTarget == nil && !elementMethod.IsConstant(), nil
+}
+
+// referenceMethodCalled obtains the method the provided call sequence element calls on the reference contract of the
+// differential test pair at the provided index.
+// Returns the method called, or nil if the element does not call a known method of the reference contract. Returns an
+// error if one occurs.
+func (t *DifferentialTestCaseProvider) referenceMethodCalled(pairIndex int, element *calls.CallSequenceElement) (*abi.Method, error) {
+	// Calls which were not made to a known contract method cannot be mirrored.
+	if element.Contract == nil || element.Call.To == nil || element.Call.DataAbiValues == nil {
+		return nil, nil
+	}
+	referenceContractName, _ := t.testCases[pairIndex].pair.ReferenceContractAndMethod()
+	if element.Contract.Name() != referenceContractName {
+		return nil, nil
+	}
+	return element.Method()
+}
+
+// resolveCandidateTarget resolves a call to the method with the provided signature on the candidate of the
+// differential test pair at the provided index, with the same arguments as the provided call sequence element which
+// calls the provided reference method.
+// Returns the candidate call target, or nil if the candidate or the method is not deployed. Returns an error if one
+// occurs.
+func (t *DifferentialTestCaseProvider) resolveCandidateTarget(worker *FuzzerWorker, pairIndex int, element *calls.CallSequenceElement, elementMethod *abi.Method, candidateMethodSig string) (*differentialCallTarget, error) {
+	// Find the candidate contract deployment.
+	pair := t.testCases[pairIndex].pair
+	candidateContractName, _ := pair.CandidateContractAndMethod()
+	candidateAddress, candidateContract := worker.deployedContractByName(candidateContractName)
+	if candidateAddress == nil {
+		return nil, nil
+	}
+
+	// Find the candidate method.
+	var candidateMethod *abi.Method
+	for _, method := range candidateContract.CompiledContract().Abi.Methods {
+		if method.Sig == candidateMethodSig {
+			method := method
+			candidateMethod = &method
+			break
+		}
+	}
+	if candidateMethod == nil {
+		return nil, nil
+	}
+
+	// Pack the input values of the element for the candidate method.
+	candidateArgs, err := candidateMethod.Inputs.Pack(element.Call.DataAbiValues.InputValues...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack arguments of '%s' for differential testing candidate '%s': %v", elementMethod.Sig, pair.Candidate, err)
+	}
+	candidateData := append(append([]byte{}, candidateMethod.ID...), candidateArgs...)
+
+	// The candidate is called with the same sender, value and gas limit as the element, but without a gas price, so
+	// mirrored calls do not spend the sender's funds on gas. It is not subject to nonce checks, as mirrored calls
+	// advance the sender's nonce only in the shadow state.
+	candidateCallMsg := calls.NewCallMessage(element.Call.From, candidateAddress, 0, element.Call.Value, element.Call.GasLimit, big.NewInt(0), big.NewInt(0), big.NewInt(0), candidateData)
+	candidateCallMsg.SkipAccountChecks = true
+	return &differentialCallTarget{msg: candidateCallMsg.ToCoreMessage(), method: candidateMethod}, nil
+}
+
+// shadowStateBeforeLastCall obtains the shadow state of the provided worker for the differential test pair at the
+// provided index, prior to the last call in the provided call sequence. The shadow state is built by executing the
+// preceding calls of the call sequence from the state prior to its first call, mirroring every call to the reference
+// contract onto the candidate. Calls which were already executed over a worker's shadow state are not executed again,
+// unless they were executed on chain again since.
+// Returns the shadow state prior to the last call, or an error if one occurs.
+func (t *DifferentialTestCaseProvider) shadowStateBeforeLastCall(worker *FuzzerWorker, pairIndex int, callSequence calls.CallSequence) (*differentialShadowState, error) {
+	// Initialize the shadow states for this worker if they are not yet.
+	if t.shadowStates[worker.WorkerIndex()] == nil {
+		t.shadowStates[worker.WorkerIndex()] = make([]*differentialShadowState, len(t.testCases))
+	}
+
+	// Determine whether the existing shadow state was built from a prefix of the calls preceding the last one, as
+	// they were last executed on chain.
+	precedingCalls := callSequence[:len(callSequence)-1]
+	shadow := t.shadowStates[worker.WorkerIndex()][pairIndex]
+	reusable := shadow != nil && len(shadow.elements) <= len(precedingCalls)
+	for i := 0; reusable && i < len(shadow.elements); i++ {
+		reusable = shadow.elements[i] == precedingCalls[i] && shadow.blocks[i] == precedingCalls[i].ChainReference.Block
+	}
+
+	// If it was not, we start over from the state prior to the block of the first call.
+	if !reusable {
+		firstBlockNumber := callSequence[0].ChainReference.Block.Header.Number.Uint64()
+		baseState, err := worker.chain.StateAfterBlockNumber(firstBlockNumber - 1)
+		if err != nil {
+			return nil, err
+		}
+		shadow = &differentialShadowState{
+			state:    baseState,
+			elements: make(calls.CallSequence, 0),
+			blocks:   make([]*chainTypes.Block, 0),
+		}
+		t.shadowStates[worker.WorkerIndex()][pairIndex] = shadow
+	}
+
+	// Execute every preceding call which was not yet executed over the shadow state.
+	for _, element := range precedingCalls[len(shadow.elements):] {
+		err := t.applyShadowCall(worker, pairIndex, shadow, element)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return shadow, nil
+}
+
+// applyShadowCall executes the provided call sequence element over the provided shadow state, committing its changes.
+// If the element calls the reference contract of the differential test pair at the provided index, the call is then
+// mirrored onto the candidate. If it could not be mirrored, the shadow state is marked as diverged.
+// Returns an error if one occurs.
+func (t *DifferentialTestCaseProvider) applyShadowCall(worker *FuzzerWorker, pairIndex int, shadow *differentialShadowState, element *calls.CallSequenceElement) error {
+	// Execute the element as it was executed on chain.
+	header := element.ChainReference.Block.Header
+	msg := element.Call.ToCoreMessage()
+	msg.SkipAccountChecks = true
+	_, err := worker.chain.ApplyMessage(msg, shadow.state, header)
+	if err != nil {
+		return fmt.Errorf("failed to execute call in differential testing shadow state: %v", err)
+	}
+
+	// If the element called the reference, mirror it onto the candidate. The sender's balance is restored
+	// afterwards, so mirrored calls do not affect the funds available to later calls.
+	candidateTarget, unmirrored, err := t.resolveMirroredCall(worker, pairIndex, element)
+	if err != nil {
+		return err
+	}
+	shadow.diverged = shadow.diverged || unmirrored
+	if candidateTarget != nil {
+		senderBalance := shadow.state.GetBalance(candidateTarget.msg.From).Clone()
+		_, err = worker.chain.ApplyMessage(candidateTarget.msg, shadow.state, header)
+		if err != nil {
+			return fmt.Errorf("failed to mirror call onto differential testing candidate: %v", err)
+		}
+		shadow.state.SetBalance(candidateTarget.msg.From, senderBalance, tracing.BalanceChangeUnspecified)
+	}
+
+	// Record the element as executed over the shadow state.
+	shadow.elements = append(shadow.elements, element)
+	shadow.blocks = append(shadow.blocks, element.ChainReference.Block)
+	return nil
+}
+
+// executeCallTarget executes the provided call target over a copy of the provided state, as if it were included in a
+// block with the provided header, without committing any changes.
+// Returns the behavior of the call which should be compared, or an error if one occurs.
+func (t *DifferentialTestCaseProvider) executeCallTarget(worker *FuzzerWorker, pairIndex int, preCallState *state.StateDB, header *coreTypes.Header, target *differentialCallTarget) (*differentialCallResult, error) {
+	tracer := newDifferentialCallTracer(*target.msg.To, t.storageSlots[pairIndex])
+	executionResult, err := worker.chain.ApplyMessage(target.msg, preCallState.Copy(), header, tracer.nativeTracer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call differential testing target: %v", err)
+	}
+	return &differentialCallResult{
+		executionResult: executionResult,
+		logs:            tracer.logs,
+		storage:         tracer.storage,
+	}, nil
+}
+
+// checkDifferentialTestFailed executes the last call in the provided call sequence against both the reference and
+// candidate of the differential test pair at the provided index, and compares their behavior.
+// Returns a description of the divergence between both sides, or an empty string if there was none, the last call
+// was not made to the reference, or a preceding call changed the state of the reference without being mirrored onto
+// the candidate. Returns an error if one occurs.
+func (t *DifferentialTestCaseProvider) checkDifferentialTestFailed(worker *FuzzerWorker, pairIndex int, callSequence calls.CallSequence) (string, error) {
+	// If we have an empty call sequence, there is no call to compare.
+	if len(callSequence) == 0 {
+		return "", nil
+	}
+
+	// Resolve the calls to make to both sides of the pair.
+	lastCall := callSequence[len(callSequence)-1]
+	referenceTarget, candidateTarget, err := t.resolveCallTargets(worker, pairIndex, lastCall)
+	if err != nil || referenceTarget == nil {
+		return "", err
+	}
+
+	// Execute both sides from the state prior to the last call, unless their state may no longer be compared.
+	shadow, err := t.shadowStateBeforeLastCall(worker, pairIndex, callSequence)
+	if err != nil || shadow.diverged {
+		return "", err
+	}
+	header := lastCall.ChainReference.Block.Header
+	referenceResult, err := t.executeCallTarget(worker, pairIndex, shadow.state, header, referenceTarget)
+	if err != nil {
+		return "", err
+	}
+	candidateResult, err := t.executeCallTarget(worker, pairIndex, shadow.state, header, candidateTarget)
+	if err != nil {
+		return "", err
+	}
+
+	// Compare the revert status and return data.
+	if referenceResult.executionResult.Failed() != candidateResult.executionResult.Failed() {
+		return fmt.Sprintf("revert status differs (reference reverted: %t, candidate reverted: %t)", referenceResult.executionResult.Failed(), candidateResult.executionResult.Failed()), nil
+	}
+	referenceReturnData, candidateReturnData := referenceResult.executionResult.ReturnData, candidateResult.executionResult.ReturnData
+	if !bytes.Equal(referenceReturnData, candidateReturnData) {
+		return fmt.Sprintf("return data differs (reference: 0x%x, candidate: 0x%x)", referenceReturnData, candidateReturnData), nil
+	}
+
+	// Compare the events emitted. The emitting address is ignored, as it differs by definition.
+	if len(referenceResult.logs) != len(candidateResult.logs) {
+		return fmt.Sprintf("number of events emitted differs (reference: %d, candidate: %d)", len(referenceResult.logs), len(candidateResult.logs)), nil
+	}
+	for i := 0; i < len(referenceResult.logs); i++ {
+		referenceLog, candidateLog := referenceResult.logs[i], candidateResult.logs[i]
+		topicsEqual := len(referenceLog.Topics) == len(candidateLog.Topics)
+		for j := 0; topicsEqual && j < len(referenceLog.Topics); j++ {
+			topicsEqual = referenceLog.Topics[j] == candidateLog.Topics[j]
+		}
+		if !topicsEqual || !bytes.Equal(referenceLog.Data, candidateLog.Data) {
+			return fmt.Sprintf("event %d differs (reference: topics=%v data=0x%x, candidate: topics=%v data=0x%x)", i, referenceLog.Topics, referenceLog.Data, candidateLog.Topics, candidateLog.Data), nil
+		}
+	}
+
+	// Compare the selected storage slots.
+	for i, slot := range t.storageSlots[pairIndex] {
+		if referenceResult.storage[i] != candidateResult.storage[i] {
+			return fmt.Sprintf("storage slot %s differs (reference: %s, candidate: %s)", slot.Hex(), referenceResult.storage[i].Hex(), candidateResult.storage[i].Hex()), nil
+		}
+	}
+	return "", nil
+}
+
+// callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached
+// Fuzzer and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether
+// the last call made to the reference of any differential test pair behaves identically against the candidate.
+func (t *DifferentialTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate for each failed test we want a call sequence
+	// shrunk for.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	// Obtain a list of test cases to check.
+	t.testCasesLock.Lock()
+	testCases := append([]*DifferentialTestCase{}, t.testCases...)
+	t.testCasesLock.Unlock()
+
+	for pairIndex, testCase := range testCases {
+		// Create local variables to avoid pointer types in the loop being overridden.
+		pairIndex := pairIndex
+		testCase := testCase
+
+		// If the test case already failed, skip it
+		if testCase.Status() == TestCaseStatusFailed {
+			continue
+		}
+
+		// Check whether the last call diverged between both sides of the pair.
+		divergence, err := t.checkDifferentialTestFailed(worker, pairIndex, callSequence)
+		if err != nil {
+			return nil, err
+		}
+		if divergence == "" {
+			continue
+		}
+
+		// We provide a shrink verifier which will update the call sequence for each shrunken sequence provided that
+		// still diverges.
+		shrinkRequest := ShrinkCallSequenceRequest{
+			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+				// Check whether the last call in the shrunken sequence diverged.
+				shrunkenDivergence, err := t.checkDifferentialTestFailed(worker, pairIndex, shrunkenCallSequence)
+				if err != nil {
+					return false, err
+				}
+				return shrunkenDivergence != "", nil
+			},
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error {
+				// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
+				if len(shrunkenCallSequence) > 0 {
+					_, err = calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
+					if err != nil {
+						return err
+					}
+				}
+
+				// Obtain the divergence from the final execution of the shrunken sequence.
+				divergence, err := t.checkDifferentialTestFailed(worker, pairIndex, shrunkenCallSequence)
+				if err != nil {
+					return err
+				}
+
+				// Capture execution traces of both sides from the state prior to the last call, so they can be
+				// displayed side by side.
+				if divergence != "" {
+					referenceTarget, candidateTarget, err := t.resolveCallTargets(worker, pairIndex, shrunkenCallSequence[len(shrunkenCallSequence)-1])
+					if err != nil {
+						return err
+					}
+					shadow, err := t.shadowStateBeforeLastCall(worker, pairIndex, shrunkenCallSequence)
+					if err != nil {
+						return err
+					}
+					_, testCase.referenceTrace, err = executiontracer.CallWithExecutionTrace(worker.chain, worker.fuzzer.contractDefinitions, referenceTarget.msg, shadow.state.Copy())
+					if err != nil {
+						return err
+					}
+					_, testCase.candidateTrace, err = executiontracer.CallWithExecutionTrace(worker.chain, worker.fuzzer.contractDefinitions, candidateTarget.msg, shadow.state.Copy())
+					if err != nil {
+						return err
+					}
+				}
+
+				// Update our test state and report it finalized.
+				testCase.status = TestCaseStatusFailed
+				testCase.callSequence = &shrunkenCallSequence
+				testCase.divergence = divergence
+				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+				worker.reportTestCaseFinished(testCase, flakiness)
+				return nil
+			},
+			RecordResultInCorpus: true,
+			CheckReproducibility: true,
+		}
+
+		// Add our shrink request to our list.
+		shrinkRequests = append(shrinkRequests, shrinkRequest)
+	}
+
+	return shrinkRequests, nil
+}
+
+// differentialCallTracer is a tracer which captures the events emitted by call frames which did not revert, and the
+// values of selected storage slots of the called contract once the call has completed.
+type differentialCallTracer struct {
+	// address describes the address of the called contract whose storage slots should be captured.
+	address common.Address
+
+	// storageSlots describes the storage keys to capture the values of once the call completes.
+	storageSlots []common.Hash
+
+	// vmContext refers to the EVM context last captured.
+	vmContext *tracing.VMContext
+
+	// callFrameLogs describes the events emitted by each call frame which has been entered but not yet exited.
+	callFrameLogs [][]*coreTypes.Log
+
+	// logs describes the events emitted by call frames which did not revert, once the call completes.
+	logs []*coreTypes.Log
+
+	// storage describes the values of the storageSlots once the call completes.
+	storage []common.Hash
+
+	// nativeTracer is the underlying tracer used to capture execution.
+	nativeTracer *chain.TestChainTracer
+}
+
+// newDifferentialCallTracer creates a differentialCallTracer for a call to the provided address and returns it.
+func newDifferentialCallTracer(address common.Address, storageSlots []common.Hash) *differentialCallTracer {
+	tracer := &differentialCallTracer{
+		address:      address,
+		storageSlots: storageSlots,
+	}
+	innerTracer := &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: tracer.OnTxStart,
+			OnEnter:   tracer.OnEnter,
+			OnExit:    tracer.OnExit,
+			OnOpcode:  tracer.OnOpcode,
+		},
+	}
+	tracer.nativeTracer = &chain.TestChainTracer{Tracer: innerTracer, CaptureTxEndSetAdditionalResults: nil}
+	return tracer
+}
+
+// OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
+func (t *differentialCallTracer) OnTxStart(vm *tracing.VMContext, tx *coreTypes.Transaction, from common.Address) {
+	// Reset our capture state
+	t.vmContext = vm
+	t.callFrameLogs = nil
+	t.logs = nil
+	t.storage = nil
+}
+
+// OnEnter is called upon entering of the call frame, as defined by tracers.Tracer.
+func (t *differentialCallTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.callFrameLogs = append(t.callFrameLogs, nil)
+}
+
+// OnExit is called after a call to finalize tracing completes for the top of a call frame, as defined by tracers.Tracer.
+func (t *differentialCallTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	// Pop the logs for the exited call frame, discarding them if it reverted, or passing them to the parent frame.
+	frameLogs := t.callFrameLogs[len(t.callFrameLogs)-1]
+	t.callFrameLogs = t.callFrameLogs[:len(t.callFrameLogs)-1]
+	if err != nil || reverted {
+		frameLogs = nil
+	}
+	if len(t.callFrameLogs) > 0 {
+		t.callFrameLogs[len(t.callFrameLogs)-1] = append(t.callFrameLogs[len(t.callFrameLogs)-1], frameLogs...)
+		return
+	}
+
+	// The call completed, so we record its logs and the values of our storage slots.
+	t.logs = frameLogs
+	t.storage = make([]common.Hash, len(t.storageSlots))
+	for i, slot := range t.storageSlots {
+		t.storage[i] = t.vmContext.StateDB.GetState(t.address, slot)
+	}
+}
+
+// OnOpcode records data from an EVM state update, as defined by tracers.Tracer.
+func (t *differentialCallTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	// If this is not a log operation, there is nothing to capture.
+	if op < byte(vm.LOG0) || op > byte(vm.LOG4) || len(t.callFrameLogs) == 0 {
+		return
+	}
+
+	// Obtain the memory offset, size and topics from the stack.
+	stack := scope.StackData()
+	topicCount := int(op - byte(vm.LOG0))
+	if len(stack) < 2+topicCount {
+		return
+	}
+	offset, size := stack[len(stack)-1], stack[len(stack)-2]
+	if !offset.IsUint64() || !size.IsUint64() || size.Uint64() > maxDifferentialLogDataSize {
+		return
+	}
+	topics := make([]common.Hash, topicCount)
+	for i := 0; i < topicCount; i++ {
+		topics[i] = stack[len(stack)-3-i].Bytes32()
+	}
+
+	// Obtain the event data from memory. Memory has not yet been expanded for this operation, so any data beyond
+	// the current memory size is zero.
+	data := make([]byte, size.Uint64())
+	memory := scope.MemoryData()
+	if offset.Uint64() < uint64(len(memory)) {
+		copy(data, memory[offset.Uint64():])
+	}
+
+	// Record the event in the current call frame.
+	eventLog := &coreTypes.Log{Address: scope.Address(), Topics: topics, Data: data}
+	t.callFrameLogs[len(t.callFrameLogs)-1] = append(t.callFrameLogs[len(t.callFrameLogs)-1], eventLog)
+}
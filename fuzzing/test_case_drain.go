@@ -0,0 +1,88 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DrainTestCase describes a test being run by a DrainTestCaseProvider.
+type DrainTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// tokenContract describes the name of the ERC20 token contract whose balances are tracked by the test case. If
+	// empty, the test case tracks ETH balances.
+	tokenContract string
+	// callSequence describes the call sequence which resulted in a sender profiting
+	callSequence *calls.CallSequence
+	// sender describes the sender which profited from the call sequence.
+	sender common.Address
+	// profit describes the amount of the asset the sender gained over the course of the call sequence.
+	profit *big.Int
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *DrainTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *DrainTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// assetName describes the name of the asset tracked by the test case.
+func (t *DrainTestCase) assetName() string {
+	if t.tokenContract == "" {
+		return "ETH"
+	}
+	return t.tokenContract
+}
+
+// Name describes the name of the test case.
+func (t *DrainTestCase) Name() string {
+	return fmt.Sprintf("Drain Test: %s", t.assetName())
+}
+
+// LogMessage obtains a buffer that represents the result of the DrainTestCase. This buffer can be passed to a logger for
+// console or file logging.
+func (t *DrainTestCase) LogMessage() *logging.LogBuffer {
+	// If the test failed, return a failure message.
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Sender %s gained %v %s after the following call sequence:\n", t.sender.String(), t.profit, t.assetName()))
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(t.CallSequence().Log().Elements()...)
+		return buffer
+	}
+	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the DrainTestCase.
+func (t *DrainTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *DrainTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("DRAIN-%s", t.assetName()), "_", "-", -1)
+}
+
+// Sender describes the sender which profited from the call sequence, if the test failed.
+func (t *DrainTestCase) Sender() common.Address {
+	return t.sender
+}
+
+// Profit describes the amount of the asset the sender gained over the course of the call sequence, if the test failed.
+func (t *DrainTestCase) Profit() *big.Int {
+	return t.profit
+}
@@ -0,0 +1,305 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// erc20BalanceOfSelector describes the selector of the ERC20 `balanceOf(address)` method.
+var erc20BalanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+
+// DrainTestCaseProvider is a DrainTestCase provider which spawns a test case for ETH and every configured ERC20 token
+// contract. It tracks the net balance of each fuzzer sender across a call sequence, relative to the testing base
+// state, and fails a test if any sender gains more of an asset than the configured profit threshold.
+type DrainTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// testCases is a map of token contract names to drain test cases. The ETH test case uses an empty name.
+	testCases map[string]*DrainTestCase
+
+	// testCasesLock is used for thread-synchronization when updating testCases
+	testCasesLock sync.Mutex
+
+	// baseBalances is a map of token contract names to the balances of each sender in the testing base state. ETH
+	// balances use an empty name. As every worker chain shares the same testing base state, these are computed once.
+	baseBalances map[string]map[common.Address]*big.Int
+
+	// baseBalancesLock is used for thread-synchronization when updating baseBalances
+	baseBalancesLock sync.Mutex
+}
+
+// attachDrainTestCaseProvider attaches a new DrainTestCaseProvider to the Fuzzer and returns it.
+func attachDrainTestCaseProvider(fuzzer *Fuzzer) *DrainTestCaseProvider {
+	// Create a test case provider
+	t := &DrainTestCaseProvider{
+		fuzzer: fuzzer,
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.Subscribe(t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.Subscribe(t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.Subscribe(t.onWorkerCreated)
+
+	// Add the provider's call sequence test function to the fuzzer.
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.callSequencePostCallTest)
+	return t
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
+// in a "not started" state for ETH and every configured token contract.
+func (t *DrainTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make(map[string]*DrainTestCase)
+	t.baseBalances = make(map[string]map[common.Address]*big.Int)
+
+	// Create a test case for ETH and every token contract, and register them with the fuzzer.
+	tokenContracts := append([]string{""}, t.fuzzer.config.Fuzzing.Testing.DrainTesting.TokenContracts...)
+	for _, tokenContract := range tokenContracts {
+		testCase := &DrainTestCase{
+			status:        TestCaseStatusNotStarted,
+			tokenContract: tokenContract,
+			callSequence:  nil,
+		}
+		t.testCases[tokenContract] = testCase
+		t.fuzzer.RegisterTestCase(testCase)
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *DrainTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			testCase.status = TestCaseStatusPassed
+		}
+	}
+	return nil
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It subscribes to
+// relevant worker events.
+func (t *DrainTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	// Subscribe to relevant worker events.
+	event.Worker.Events.ContractAdded.Subscribe(t.onWorkerDeployedContractAdded)
+	return nil
+}
+
+// onWorkerDeployedContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment
+// on its underlying chain. The ETH test case, and any test case for the deployed token contract, which are in a
+// "not started" state are put into a "running" state, as they are now potentially reachable for testing.
+func (t *DrainTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	// If we don't have a contract definition, we can't run tests against the contract.
+	if event.ContractDefinition == nil {
+		return nil
+	}
+
+	t.testCasesLock.Lock()
+	defer t.testCasesLock.Unlock()
+	for _, tokenContract := range []string{"", event.ContractDefinition.Name()} {
+		if testCase, testCaseExists := t.testCases[tokenContract]; testCaseExists && testCase.Status() == TestCaseStatusNotStarted {
+			testCase.status = TestCaseStatusRunning
+		}
+	}
+	return nil
+}
+
+// getBalances obtains the balance of each fuzzer sender for the provided token contract, or ETH if the name is empty,
+// in the provided state. If a nil state is provided, the current chain state will be used.
+// Returns the balances, or nil if the token contract is not deployed. Senders whose balance could not be obtained are
+// omitted. Returns an error if one occurs.
+func (t *DrainTestCaseProvider) getBalances(worker *FuzzerWorker, tokenContract string, state *state.StateDB) (map[common.Address]*big.Int, error) {
+	if state == nil {
+		state = worker.chain.State()
+	}
+
+	// If we are tracking ETH, we can obtain balances directly from the state.
+	balances := make(map[common.Address]*big.Int)
+	if tokenContract == "" {
+		for _, sender := range worker.fuzzer.senders {
+			balances[sender] = state.GetBalance(sender).ToBig()
+		}
+		return balances, nil
+	}
+
+	// Otherwise we query the token contract for each sender's balance.
+	tokenAddress, _ := worker.deployedContractByName(tokenContract)
+	if tokenAddress == nil {
+		return nil, nil
+	}
+	for _, sender := range worker.fuzzer.senders {
+		data := append(append([]byte{}, erc20BalanceOfSelector...), common.LeftPadBytes(sender.Bytes(), 32)...)
+		msg := calls.NewCallMessage(worker.fuzzer.deployer, tokenAddress, 0, big.NewInt(0), worker.fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, data)
+		msg.FillFromTestChainProperties(worker.chain)
+		executionResult, err := worker.chain.CallContract(msg.ToCoreMessage(), state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call drain testing token contract '%s': %v", tokenContract, err)
+		}
+		if executionResult.Failed() || len(executionResult.ReturnData) < 32 {
+			continue
+		}
+		balances[sender] = new(big.Int).SetBytes(executionResult.ReturnData[:32])
+	}
+	return balances, nil
+}
+
+// getBaseBalances obtains the balance of each fuzzer sender for the provided token contract, or ETH if the name is
+// empty, in the testing base state.
+// Returns the balances, or nil if the token contract is not deployed. Returns an error if one occurs.
+func (t *DrainTestCaseProvider) getBaseBalances(worker *FuzzerWorker, tokenContract string) (map[common.Address]*big.Int, error) {
+	t.baseBalancesLock.Lock()
+	defer t.baseBalancesLock.Unlock()
+
+	// If we already computed the base balances, return them.
+	if baseBalances, ok := t.baseBalances[tokenContract]; ok {
+		return baseBalances, nil
+	}
+
+	// Otherwise compute them from the testing base state.
+	baseState, err := worker.chain.StateAfterBlockNumber(worker.testingBaseBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	baseBalances, err := t.getBalances(worker, tokenContract, baseState)
+	if err != nil || baseBalances == nil {
+		return nil, err
+	}
+	t.baseBalances[tokenContract] = baseBalances
+	return baseBalances, nil
+}
+
+// getProfits obtains the net change in balance of each fuzzer sender for the provided token contract, or ETH if the
+// name is empty, between the testing base state and the current chain state.
+// Returns the profit of each sender whose balances could be obtained, or an error if one occurs.
+func (t *DrainTestCaseProvider) getProfits(worker *FuzzerWorker, tokenContract string) (map[common.Address]*big.Int, error) {
+	baseBalances, err := t.getBaseBalances(worker, tokenContract)
+	if err != nil || baseBalances == nil {
+		return nil, err
+	}
+	balances, err := t.getBalances(worker, tokenContract, nil)
+	if err != nil {
+		return nil, err
+	}
+	profits := make(map[common.Address]*big.Int)
+	for sender, balance := range balances {
+		if baseBalance, ok := baseBalances[sender]; ok {
+			profits[sender] = new(big.Int).Sub(balance, baseBalance)
+		}
+	}
+	return profits, nil
+}
+
+// checkDrainTestFailed checks whether any fuzzer sender gained more of the provided token contract's asset, or ETH if
+// the name is empty, than the configured profit threshold.
+// Returns the sender with the highest profit exceeding the threshold and its profit, or a nil sender if none exceeded
+// it. Returns an error if one occurs.
+func (t *DrainTestCaseProvider) checkDrainTestFailed(worker *FuzzerWorker, tokenContract string) (*common.Address, *big.Int, error) {
+	profits, err := t.getProfits(worker, tokenContract)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Iterate senders in order, so the same sender is selected across runs if profits are equal.
+	var profitingSender *common.Address
+	maxProfit := worker.fuzzer.config.Fuzzing.Testing.DrainTesting.ProfitThreshold
+	for _, sender := range worker.fuzzer.senders {
+		if profit, ok := profits[sender]; ok && profit.Cmp(maxProfit) > 0 {
+			sender := sender
+			profitingSender = &sender
+			maxProfit = profit
+		}
+	}
+	return profitingSender, maxProfit, nil
+}
+
+// callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached
+// Fuzzer and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether
+// any fuzzer sender has profited beyond the configured threshold in any tracked asset.
+func (t *DrainTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate for each failed test we want a call sequence
+	// shrunk for.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	// Obtain a list of test cases to check.
+	t.testCasesLock.Lock()
+	testCases := make([]*DrainTestCase, 0, len(t.testCases))
+	for _, testCase := range t.testCases {
+		testCases = append(testCases, testCase)
+	}
+	t.testCasesLock.Unlock()
+
+	for _, testCase := range testCases {
+		// Create local variables to avoid pointer types in the loop being overridden.
+		testCase := testCase
+
+		// If the test case already failed, skip it
+		if testCase.Status() == TestCaseStatusFailed {
+			continue
+		}
+
+		// Check whether any sender profited beyond our threshold.
+		sender, profit, err := t.checkDrainTestFailed(worker, testCase.tokenContract)
+		if err != nil {
+			return nil, err
+		}
+		if sender == nil {
+			continue
+		}
+
+		// We provide a shrink verifier which will update the call sequence for each shrunken sequence provided that
+		// preserves at least the same profit for the same sender.
+		shrinkRequest := ShrinkCallSequenceRequest{
+			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+				shrunkenProfits, err := t.getProfits(worker, testCase.tokenContract)
+				if err != nil {
+					return false, err
+				}
+				shrunkenProfit, ok := shrunkenProfits[*sender]
+				return ok && shrunkenProfit.Cmp(profit) >= 0, nil
+			},
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error {
+				// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
+				if len(shrunkenCallSequence) > 0 {
+					_, err = calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
+					if err != nil {
+						return err
+					}
+				}
+
+				// Obtain the sender's profit from the final execution of the shrunken sequence.
+				shrunkenProfits, err := t.getProfits(worker, testCase.tokenContract)
+				if err != nil {
+					return err
+				}
+				shrunkenProfit, ok := shrunkenProfits[*sender]
+				if !ok {
+					shrunkenProfit = profit
+				}
+
+				// Update our test state and report it finalized.
+				testCase.status = TestCaseStatusFailed
+				testCase.callSequence = &shrunkenCallSequence
+				testCase.sender = *sender
+				testCase.profit = shrunkenProfit
+				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+				worker.reportTestCaseFinished(testCase, flakiness)
+				return nil
+			},
+			RecordResultInCorpus: true,
+			CheckReproducibility: true,
+		}
+
+		// Add our shrink request to our list.
+		shrinkRequests = append(shrinkRequests, shrinkRequest)
+	}
+
+	return shrinkRequests, nil
+}
@@ -0,0 +1,67 @@
+package fuzzing
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// FlakyTestCase describes a failed or errored TestCase whose result did not reproduce when its call sequence was
+// replayed on a fresh clone of the base chain. It replaces the original TestCase in the Fuzzer's results.
+type FlakyTestCase struct {
+	// testCase describes the failed or errored test case which did not reproduce.
+	testCase TestCase
+	// flakiness describes how the result did not reproduce.
+	flakiness *CallSequenceFlakiness
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *FlakyTestCase) Status() TestCaseStatus {
+	return TestCaseStatusFlaky
+}
+
+// CallSequence describes the calls.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *FlakyTestCase) CallSequence() *calls.CallSequence {
+	return t.testCase.CallSequence()
+}
+
+// Name describes the name of the test case.
+func (t *FlakyTestCase) Name() string {
+	return t.testCase.Name()
+}
+
+// LogMessage obtains a buffer that represents the result of the FlakyTestCase. This buffer can be passed to a logger for
+// console or file logging.
+func (t *FlakyTestCase) LogMessage() *logging.LogBuffer {
+	buffer := logging.NewLogBuffer()
+	buffer.Append(colors.YellowBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+	buffer.Append(fmt.Sprintf("Result did not reproduce when replaying the call sequence on a fresh chain, %s.\n", t.flakiness))
+	buffer.Append(colors.Bold, "[Original Result]", colors.Reset, "\n")
+	buffer.Append(t.testCase.LogMessage().Elements()...)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the FlakyTestCase.
+func (t *FlakyTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *FlakyTestCase) ID() string {
+	return t.testCase.ID()
+}
+
+// OriginalTestCase obtains the failed or errored TestCase whose result did not reproduce.
+func (t *FlakyTestCase) OriginalTestCase() TestCase {
+	return t.testCase
+}
+
+// DivergedCallIndex obtains the index of the first call in the call sequence whose execution differed when replayed,
+// or -1 if every call executed identically.
+func (t *FlakyTestCase) DivergedCallIndex() int {
+	return t.flakiness.divergedCallIndex
+}
@@ -0,0 +1,91 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// FuzzTestCase describes a test being run by a FuzzTestCaseProvider.
+type FuzzTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// targetContract describes the target contract where the test case was found
+	targetContract *fuzzerTypes.Contract
+	// targetMethod describes the target method for the test case
+	targetMethod abi.Method
+	// callSequence describes the call sequence (a single call) that caused the fuzz test to revert
+	callSequence *calls.CallSequence
+
+	// coverageMaps describes the coverage achieved by all inputs provided to the fuzz test so far.
+	coverageMaps *coverage.CoverageMaps
+	// interestingInputs describes the inputs which achieved new coverage for the fuzz test. These are used as a base
+	// for mutations when generating new inputs.
+	interestingInputs []*calls.CallSequenceElement
+	// callsTested describes the amount of inputs the fuzz test has been called with.
+	callsTested *big.Int
+	// inputsLock is used for thread-synchronization when updating interestingInputs and callsTested
+	inputsLock sync.Mutex
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *FuzzTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *FuzzTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// Name describes the name of the test case.
+func (t *FuzzTestCase) Name() string {
+	return fmt.Sprintf("Fuzz Test: %s.%s", t.targetContract.Name(), t.targetMethod.Sig)
+}
+
+// LogMessage obtains a buffer that represents the result of the FuzzTestCase. This buffer can be passed to a logger for
+// console or file logging.
+func (t *FuzzTestCase) LogMessage() *logging.LogBuffer {
+	// If the test failed, return a failure message.
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" reverted when called with the following input:\n", t.targetContract.Name(), t.targetMethod.Sig))
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(t.CallSequence().Log().Elements()...)
+		return buffer
+	}
+
+	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset)
+	if t.Status() != TestCaseStatusNotStarted {
+		t.inputsLock.Lock()
+		buffer.Append(fmt.Sprintf(" (runs: %v, unique PCs covered: %d)", t.callsTested, t.coverageMaps.UniquePCs()))
+		t.inputsLock.Unlock()
+	}
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the FuzzTestCase.
+func (t *FuzzTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *FuzzTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("FUZZ-%s-%s", t.targetContract.Name(), t.targetMethod.Sig), "_", "-", -1)
+}
+
+// CoverageMaps obtains the coverage achieved by all inputs provided to the fuzz test so far.
+func (t *FuzzTestCase) CoverageMaps() *coverage.CoverageMaps {
+	return t.coverageMaps
+}
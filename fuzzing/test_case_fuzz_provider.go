@@ -0,0 +1,299 @@
+package fuzzing
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"golang.org/x/exp/slices"
+)
+
+// FuzzTestCaseProvider is a provider for stateless fuzz tests. Fuzz tests are represented as publicly-accessible
+// functions which have a name prefix specified by a config.FuzzingConfig and take input arguments. They are called in
+// isolation from the post-setup state with fuzzed arguments, and are considered to pass as long as they do not revert.
+type FuzzTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// testCases is a map of contract-method IDs to fuzz test cases.
+	testCases map[contracts.ContractMethodID]*FuzzTestCase
+
+	// testCasesLock is used for thread-synchronization when updating testCases
+	testCasesLock sync.Mutex
+}
+
+// fuzzTestMutationChance describes the chance (out of 100) that a fuzz test input is created by mutating a previous
+// input that achieved new coverage for the fuzz test, rather than being generated entirely anew.
+const fuzzTestMutationChance = 80
+
+// attachFuzzTestCaseProvider attaches a new FuzzTestCaseProvider to the Fuzzer and returns it.
+func attachFuzzTestCaseProvider(fuzzer *Fuzzer) *FuzzTestCaseProvider {
+	// If there are no testing prefixes, then there is no reason to attach a test case provider and subscribe to events
+	if len(fuzzer.config.Fuzzing.Testing.FuzzTesting.TestPrefixes) == 0 {
+		return nil
+	}
+
+	// Create a test case provider
+	t := &FuzzTestCaseProvider{
+		fuzzer: fuzzer,
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.Subscribe(t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.Subscribe(t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.Subscribe(t.onWorkerCreated)
+
+	// Add the provider's stateless test function to the fuzzer.
+	fuzzer.Hooks.StatelessTestFuncs = append(fuzzer.Hooks.StatelessTestFuncs, t.statelessTest)
+	return t
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
+// in a "not started" state for every fuzz test method discovered in the contract definitions known to the Fuzzer.
+func (t *FuzzTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make(map[contracts.ContractMethodID]*FuzzTestCase)
+
+	// Create a test case for every fuzz test method.
+	for _, contract := range t.fuzzer.ContractDefinitions() {
+		// If we're not testing all contracts, verify the current contract is one we specified in our target contracts
+		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) {
+			continue
+		}
+
+		for _, method := range contract.FuzzTestMethods {
+			// Create local variables to avoid pointer types in the loop being overridden.
+			contract := contract
+			method := method
+
+			// Create our fuzz test case
+			fuzzTestCase := &FuzzTestCase{
+				status:            TestCaseStatusNotStarted,
+				targetContract:    contract,
+				targetMethod:      method,
+				callSequence:      nil,
+				coverageMaps:      coverage.NewCoverageMaps(),
+				interestingInputs: make([]*calls.CallSequenceElement, 0),
+				callsTested:       big.NewInt(0),
+			}
+
+			// Add to our test cases and register them with the fuzzer
+			methodId := contracts.GetContractMethodID(contract, &method)
+			t.testCases[methodId] = fuzzTestCase
+			t.fuzzer.RegisterTestCase(fuzzTestCase)
+		}
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *FuzzTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			testCase.status = TestCaseStatusPassed
+		}
+	}
+	return nil
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It subscribes to
+// relevant worker events.
+func (t *FuzzTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	// Subscribe to relevant worker events.
+	event.Worker.Events.ContractAdded.Subscribe(t.onWorkerDeployedContractAdded)
+	return nil
+}
+
+// onWorkerDeployedContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment
+// on its underlying chain. Any test cases previously made for the fuzz test methods of the deployed contract which are
+// in a "not started" state are put into a "running" state, as they are now reachable for testing.
+func (t *FuzzTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	// If we don't have a contract definition, we can't run fuzz tests against the contract.
+	if event.ContractDefinition == nil {
+		return nil
+	}
+
+	// Loop through all fuzz test methods and signal a running state for any tests which have not started.
+	for _, method := range event.ContractDefinition.FuzzTestMethods {
+		methodId := contracts.GetContractMethodID(event.ContractDefinition, &method)
+
+		t.testCasesLock.Lock()
+		testCase, testCaseExists := t.testCases[methodId]
+		t.testCasesLock.Unlock()
+		if testCaseExists && testCase.Status() == TestCaseStatusNotStarted {
+			testCase.status = TestCaseStatusRunning
+		}
+	}
+	return nil
+}
+
+// nextFuzzTestInput creates a new call sequence element which calls the provided fuzz test method. The element is
+// either generated entirely anew, or is a mutation of a previous input which achieved new coverage for the test.
+// Returns the call sequence element, or an error if one occurs.
+func (t *FuzzTestCaseProvider) nextFuzzTestInput(worker *FuzzerWorker, testCase *FuzzTestCase, fuzzTestMethod *contracts.DeployedContractMethod) (*calls.CallSequenceElement, error) {
+	// Obtain a previous interesting input to mutate, if we decide to do so.
+	var baseInput *calls.CallSequenceElement
+	testCase.inputsLock.Lock()
+	if len(testCase.interestingInputs) > 0 && worker.randomProvider.Intn(100) < fuzzTestMutationChance {
+		baseInput = testCase.interestingInputs[worker.randomProvider.Intn(len(testCase.interestingInputs))]
+	}
+	testCase.inputsLock.Unlock()
+
+	// If we have no input to mutate, generate an entirely new one.
+	if baseInput == nil {
+		blockNumberDelay, blockTimestampDelay := worker.sequenceGenerator.generateBlockDelays()
+		return worker.sequenceGenerator.generateNewElementForMethod(fuzzTestMethod, worker.sequenceGenerator.selectSender(), blockNumberDelay, blockTimestampDelay)
+	}
+
+	// Otherwise clone the input, so we do not modify the original, and mutate its arguments.
+	element, err := baseInput.Clone()
+	if err != nil {
+		return nil, err
+	}
+	element.Call.To = &fuzzTestMethod.Address
+	err = prefetchModifyCallFuncMutate(worker.sequenceGenerator, element)
+	if err != nil {
+		return nil, err
+	}
+	return element, nil
+}
+
+// checkFuzzTestFailure checks whether the last call in the provided call sequence called the provided fuzz test
+// method and reverted.
+// Returns a boolean indicating whether the fuzz test failed.
+func (t *FuzzTestCaseProvider) checkFuzzTestFailure(callSequence calls.CallSequence, fuzzTestMethodId contracts.ContractMethodID) (bool, error) {
+	// A fuzz test is represented by a single call, so we only consider call sequences of that length.
+	if len(callSequence) != 1 {
+		return false, nil
+	}
+
+	// Verify the call targeted the fuzz test method.
+	call := callSequence[0]
+	callMethod, err := call.Method()
+	if err != nil {
+		return false, err
+	}
+	if contracts.GetContractMethodID(call.Contract, callMethod) != fuzzTestMethodId {
+		return false, nil
+	}
+
+	// The fuzz test fails if the call reverted.
+	return call.ChainReference.MessageResults().ExecutionResult.Failed(), nil
+}
+
+// statelessTest is a StatelessTestFunc which calls a random fuzz test method known to the worker with a new input,
+// from the testing base state. Coverage achieved by the input is tracked for the individual fuzz test, and if the call
+// reverts, a request to shrink its arguments is returned.
+func (t *FuzzTestCaseProvider) statelessTest(worker *FuzzerWorker) (calls.CallSequence, []ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate if the fuzz test fails.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	// If there are no fuzz test methods for this worker, there is nothing to do.
+	if len(worker.fuzzTestMethods) == 0 {
+		return nil, shrinkRequests, nil
+	}
+
+	// Select a random fuzz test method and obtain its test case.
+	fuzzTestMethod := worker.fuzzTestMethods[worker.randomProvider.Intn(len(worker.fuzzTestMethods))]
+	fuzzTestMethodId := contracts.GetContractMethodID(fuzzTestMethod.Contract, &fuzzTestMethod.Method)
+	t.testCasesLock.Lock()
+	testCase, testCaseExists := t.testCases[fuzzTestMethodId]
+	t.testCasesLock.Unlock()
+
+	// If we aren't testing this method, or it already failed, there is nothing to do.
+	if !testCaseExists || testCase.Status() == TestCaseStatusFailed {
+		return nil, shrinkRequests, nil
+	}
+
+	// Create the input for the fuzz test.
+	element, err := t.nextFuzzTestInput(worker, testCase, &fuzzTestMethod)
+	if err != nil {
+		return nil, nil, err
+	}
+	element.Call.FillFromTestChainProperties(worker.chain)
+
+	// Execute the fuzz test call, then revert to our testing base state. The results of the call remain accessible
+	// through the element's chain reference.
+	callSequence, err := calls.ExecuteCallSequence(worker.chain, calls.CallSequence{element})
+	if err != nil {
+		return nil, nil, err
+	}
+	err = worker.chain.RevertToBlockNumber(worker.testingBaseBlockNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Add any values the harness requested be added to our value dictionary.
+	messageResults := element.ChainReference.MessageResults()
+	worker.updateValueSetFromFuzzerHints(chain.GetFuzzerHintResults(messageResults))
+
+	// Merge the coverage achieved by this input into the coverage for this fuzz test. If it achieved new coverage,
+	// we retain the input, so it may be mutated to create future inputs.
+	coverageMaps := coverage.GetCoverageTracerResults(messageResults)
+	coverage.RemoveCoverageTracerResults(messageResults)
+	coverageUpdated, revertedCoverageUpdated, err := testCase.coverageMaps.Update(coverageMaps)
+	if err != nil {
+		return nil, nil, err
+	}
+	if coverageUpdated || revertedCoverageUpdated {
+		// Clone the input and drop its chain reference, so we do not retain the block it was executed in.
+		interestingInput, err := element.Clone()
+		if err != nil {
+			return nil, nil, err
+		}
+		interestingInput.ChainReference = nil
+		testCase.inputsLock.Lock()
+		testCase.interestingInputs = append(testCase.interestingInputs, interestingInput)
+		testCase.inputsLock.Unlock()
+	}
+	testCase.inputsLock.Lock()
+	testCase.callsTested.Add(testCase.callsTested, big.NewInt(1))
+	testCase.inputsLock.Unlock()
+
+	// Update our metrics
+	worker.workerMetrics().callsTested.Add(worker.workerMetrics().callsTested, big.NewInt(1))
+	worker.workerMetrics().gasUsed.Add(worker.workerMetrics().gasUsed, new(big.Int).SetUint64(messageResults.Receipt.GasUsed))
+
+	// Check if the fuzz test failed.
+	testFailed, err := t.checkFuzzTestFailure(callSequence, fuzzTestMethodId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// If we failed the test, we provide a shrink verifier which will update the call sequence for each shrunken
+	// sequence provided that fails the test. The value shrinking pass will minimize the test's arguments.
+	if testFailed {
+		shrinkRequest := ShrinkCallSequenceRequest{
+			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+				return t.checkFuzzTestFailure(shrunkenCallSequence, fuzzTestMethodId)
+			},
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error {
+				// When we're finished shrinking, attach an execution trace to the call.
+				if len(shrunkenCallSequence) > 0 {
+					_, err := calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
+					if err != nil {
+						return err
+					}
+				}
+
+				// Update our test state and report it finalized.
+				testCase.status = TestCaseStatusFailed
+				testCase.callSequence = &shrunkenCallSequence
+				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+				worker.reportTestCaseFinished(testCase, flakiness)
+				return nil
+			},
+			RecordResultInCorpus: false,
+			ExcludeFromCorpus:    true,
+			CheckReproducibility: true,
+		}
+		shrinkRequests = append(shrinkRequests, shrinkRequest)
+	}
+
+	return callSequence, shrinkRequests, nil
+}
@@ -0,0 +1,88 @@
+package fuzzing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// GasTestCase describes a test being run by a GasTestCaseProvider.
+type GasTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// targetContract describes the target contract where the test case was found
+	targetContract *contracts.Contract
+	// targetMethod describes the target method for the test case
+	targetMethod abi.Method
+	// callSequence describes the call sequence whose last call used the maximum amount of gas for the method
+	callSequence *calls.CallSequence
+	// gasUsed describes the maximum amount of gas used by a call to the method
+	gasUsed uint64
+	// gasThreshold describes the amount of gas a call to the method may use before the test fails
+	gasThreshold uint64
+	// shrinkPending indicates whether a call sequence which used more gas than gasUsed is currently being shrunk, in
+	// which case no further requests to shrink one are made until it concludes.
+	shrinkPending bool
+	// gasUsedLock is used for thread-synchronization when updating the gasUsed, callSequence and shrinkPending
+	gasUsedLock sync.Mutex
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *GasTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the calls.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *GasTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// Name describes the name of the test case.
+func (t *GasTestCase) Name() string {
+	return fmt.Sprintf("Gas Test: %s.%s", t.targetContract.Name(), t.targetMethod.Sig)
+}
+
+// LogMessage obtains a buffer that represents the result of the GasTestCase. This buffer can be passed to a logger for
+// console or file logging.
+func (t *GasTestCase) LogMessage() *logging.LogBuffer {
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Call to method \"%s.%s\" used %d gas, exceeding the threshold of %d gas, after the following call sequence:\n", t.targetContract.Name(), t.targetMethod.Sig, t.gasUsed, t.gasThreshold))
+	} else {
+		buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		if t.callSequence == nil {
+			return buffer
+		}
+		buffer.Append(fmt.Sprintf("Call to method \"%s.%s\" used a maximum of ", t.targetContract.Name(), t.targetMethod.Sig))
+		buffer.Append(colors.Bold, t.gasUsed, colors.Reset, " gas after the following call sequence:\n")
+	}
+	buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+	buffer.Append(t.CallSequence().Log().Elements()...)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the GasTestCase.
+func (t *GasTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *GasTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("GAS-%s-%s", t.targetContract.Name(), t.targetMethod.Sig), "_", "-", -1)
+}
+
+// GasUsed obtains the maximum amount of gas used by a call to the method found till now.
+func (t *GasTestCase) GasUsed() uint64 {
+	t.gasUsedLock.Lock()
+	defer t.gasUsedLock.Unlock()
+	return t.gasUsed
+}
@@ -0,0 +1,252 @@
+package fuzzing
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"golang.org/x/exp/slices"
+)
+
+// GasTestCaseProvider is a GasTestCase provider which spawns test cases for every state-changing contract method. It
+// tracks the maximum gas used by a call to each method alongside a shrunken call sequence which produces it, and
+// fails a test if a call to the method uses more gas than the configured threshold (e.g. due to an unbounded loop).
+type GasTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// testCases is a map of contract-method IDs to gas test cases.
+	testCases map[contracts.ContractMethodID]*GasTestCase
+
+	// testCasesLock is used for thread-synchronization when updating testCases
+	testCasesLock sync.Mutex
+
+	// gasThreshold describes the amount of gas a call may use before a test fails.
+	gasThreshold uint64
+}
+
+// attachGasTestCaseProvider attaches a new GasTestCaseProvider to the Fuzzer and returns it.
+func attachGasTestCaseProvider(fuzzer *Fuzzer) (*GasTestCaseProvider, error) {
+	// Resolve our gas threshold against the block gas limit.
+	gasThreshold, err := fuzzer.config.Fuzzing.Testing.GasTesting.GasThresholdValue(fuzzer.config.Fuzzing.BlockGasLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a test case provider
+	t := &GasTestCaseProvider{
+		fuzzer:       fuzzer,
+		gasThreshold: gasThreshold,
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.Subscribe(t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.Subscribe(t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.Subscribe(t.onWorkerCreated)
+
+	// Add the provider's call sequence test function to the fuzzer.
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.callSequencePostCallTest)
+	return t, nil
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
+// in a "not started" state for every state-changing method discovered in the contract definitions known to the Fuzzer.
+func (t *GasTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make(map[contracts.ContractMethodID]*GasTestCase)
+
+	// Create a test case for every state-changing method.
+	for _, contract := range t.fuzzer.ContractDefinitions() {
+		// If we're not testing all contracts, verify the current contract is one we specified in our target contracts
+		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) {
+			continue
+		}
+
+		for _, method := range contract.AssertionTestMethods {
+			// Read-only methods are never called in a call sequence as they cannot change state.
+			if method.IsConstant() {
+				continue
+			}
+
+			// Create local variables to avoid pointer types in the loop being overridden.
+			contract := contract
+			method := method
+
+			// Create our test case
+			testCase := &GasTestCase{
+				status:         TestCaseStatusNotStarted,
+				targetContract: contract,
+				targetMethod:   method,
+				callSequence:   nil,
+				gasThreshold:   t.gasThreshold,
+			}
+
+			// Add to our test cases and register them with the fuzzer
+			methodId := contracts.GetContractMethodID(contract, &method)
+			t.testCases[methodId] = testCase
+			t.fuzzer.RegisterTestCase(testCase)
+		}
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *GasTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			testCase.status = TestCaseStatusPassed
+		}
+	}
+	return nil
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It subscribes to
+// relevant worker events.
+func (t *GasTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	// Subscribe to relevant worker events.
+	event.Worker.Events.ContractAdded.Subscribe(t.onWorkerDeployedContractAdded)
+	return nil
+}
+
+// onWorkerDeployedContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment
+// on its underlying chain. Any test cases for methods the deployed contract contains which are in a "not started"
+// state are put into a "running" state, as they are now potentially reachable for testing.
+func (t *GasTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	// If we don't have a contract definition, we can't run tests against the contract.
+	if event.ContractDefinition == nil {
+		return nil
+	}
+
+	// Loop through all methods and find ones for which we have tests
+	for _, method := range event.ContractDefinition.CompiledContract().Abi.Methods {
+		// Obtain an identifier for this pair
+		methodId := contracts.GetContractMethodID(event.ContractDefinition, &method)
+
+		// If we have any tests in a not-started state, we can signal a running state now.
+		t.testCasesLock.Lock()
+		testCase, testCaseExists := t.testCases[methodId]
+		t.testCasesLock.Unlock()
+		if testCaseExists && testCase.Status() == TestCaseStatusNotStarted {
+			testCase.status = TestCaseStatusRunning
+		}
+	}
+	return nil
+}
+
+// getLastCallGasUsed obtains the method ID targeted by the last call in the provided call sequence, and the gas it
+// used.
+// Returns the method ID and gas used, or a nil method ID if the last call did not target a known method. Returns an
+// error if one occurs.
+func (t *GasTestCaseProvider) getLastCallGasUsed(callSequence calls.CallSequence) (*contracts.ContractMethodID, uint64, error) {
+	// If we have an empty call sequence, there is no call to check.
+	if len(callSequence) == 0 {
+		return nil, 0, nil
+	}
+
+	// Obtain the contract and method from the last call made in our sequence
+	lastCall := callSequence[len(callSequence)-1]
+	if lastCall.Contract == nil || lastCall.ChainReference == nil {
+		return nil, 0, nil
+	}
+	lastCallMethod, err := lastCall.Method()
+	if err != nil || lastCallMethod == nil {
+		return nil, 0, err
+	}
+	methodId := contracts.GetContractMethodID(lastCall.Contract, lastCallMethod)
+	return &methodId, lastCall.ChainReference.MessageResults().ExecutionResult.UsedGas, nil
+}
+
+// callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached
+// Fuzzer and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether the
+// last call used more gas than any prior call to the same method.
+func (t *GasTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate for each new maximum gas usage we want a call
+	// sequence shrunk for.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	// Obtain the method ID for the last call and the gas it used.
+	methodId, gasUsed, err := t.getLastCallGasUsed(callSequence)
+	if err != nil || methodId == nil {
+		return shrinkRequests, err
+	}
+
+	// Obtain the test case for this method.
+	t.testCasesLock.Lock()
+	testCase, testCaseExists := t.testCases[*methodId]
+	t.testCasesLock.Unlock()
+
+	// If we're not testing this method, stop.
+	if !testCaseExists {
+		return shrinkRequests, nil
+	}
+
+	// If the test already failed, a call sequence for it is already being shrunk, or the gas used did not increase,
+	// stop. Otherwise, mark a shrink request as pending, so only one is made for the test at a time.
+	testCase.gasUsedLock.Lock()
+	if testCase.status == TestCaseStatusFailed || testCase.shrinkPending || gasUsed <= testCase.gasUsed {
+		testCase.gasUsedLock.Unlock()
+		return shrinkRequests, nil
+	}
+	testCase.shrinkPending = true
+	testCase.gasUsedLock.Unlock()
+
+	// We provide a shrink verifier which will update the call sequence for each shrunken sequence provided that it
+	// still uses at least as much gas in a call to the same method.
+	shrinkRequest := ShrinkCallSequenceRequest{
+		VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+			shrunkenMethodId, shrunkenGasUsed, err := t.getLastCallGasUsed(shrunkenCallSequence)
+			if err != nil || shrunkenMethodId == nil || *shrunkenMethodId != *methodId {
+				return false, err
+			}
+			return shrunkenGasUsed >= gasUsed, nil
+		},
+		FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error {
+			// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
+			if len(shrunkenCallSequence) > 0 {
+				_, err := calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Obtain the gas used by the final execution of the shrunken sequence.
+			_, shrunkenGasUsed, err := t.getLastCallGasUsed(shrunkenCallSequence)
+			if err != nil {
+				return err
+			}
+
+			// Our shrink request has concluded, so another may be made. If the test already failed, or the gas used
+			// does not exceed the maximum recorded, there is nothing to update. A new maximum which did not reproduce
+			// when replayed is only recorded if it exceeds our threshold, so the test can be reported as flaky.
+			testCase.gasUsedLock.Lock()
+			testCase.shrinkPending = false
+			exceededThreshold := shrunkenGasUsed > testCase.gasThreshold
+			if testCase.status == TestCaseStatusFailed || shrunkenGasUsed <= testCase.gasUsed || (flakiness != nil && !exceededThreshold) {
+				testCase.gasUsedLock.Unlock()
+				return nil
+			}
+			testCase.gasUsed = shrunkenGasUsed
+			testCase.callSequence = &shrunkenCallSequence
+			if exceededThreshold {
+				testCase.status = TestCaseStatusFailed
+			}
+			testCase.gasUsedLock.Unlock()
+
+			// If the gas used exceeded our threshold, report the test finalized.
+			if exceededThreshold {
+				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+				worker.reportTestCaseFinished(testCase, flakiness)
+			}
+			return nil
+		},
+		RecordResultInCorpus: true,
+		CheckReproducibility: true,
+	}
+
+	// Add our shrink request to our list.
+	shrinkRequests = append(shrinkRequests, shrinkRequest)
+	return shrinkRequests, nil
+}
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/contracts"
@@ -22,14 +23,37 @@ type OptimizationTestCase struct {
 	targetContract *contracts.Contract
 	// targetMethod describes the target method for the test case
 	targetMethod abi.Method
-	// callSequence describes the call sequence that maximized the value
-	callSequence *calls.CallSequence
-	// value is used to store the maximum value returned by the test method
-	value *big.Int
-	// valueLock is used for thread-synchronization when updating the value
+	// minimize describes whether the values returned by the test method should be minimized rather than maximized.
+	minimize bool
+	// objectives describes the state of each value returned by the test method, which is optimized independently.
+	objectives []*optimizationObjective
+	// valueLock is used for thread-synchronization when updating the objectives
 	valueLock sync.Mutex
+}
+
+// optimizationObjective describes a single value returned by an optimization test method, alongside the best value
+// found for it.
+type optimizationObjective struct {
+	// name describes the name of the return value, if one was provided.
+	name string
+	// value is used to store the best value returned by the test method for this objective
+	value *big.Int
+	// callSequence describes the call sequence that produced the best value
+	callSequence *calls.CallSequence
 	// optimizationTestTrace describes the execution trace when running the callSequence
 	optimizationTestTrace *executiontracer.ExecutionTrace
+	// timeline describes each improvement of the best value, in the order they were found.
+	timeline []OptimizationImprovement
+}
+
+// OptimizationImprovement describes an improvement of the best value found for an optimization test objective.
+type OptimizationImprovement struct {
+	// Value describes the new best value.
+	Value *big.Int
+	// Elapsed describes the time elapsed since the start of the fuzzing campaign when the value was found.
+	Elapsed time.Duration
+	// CallsTested describes the number of calls tested by the fuzzer when the value was found.
+	CallsTested *big.Int
 }
 
 // Status describes the TestCaseStatus used to define the current state of the test.
@@ -38,9 +62,10 @@ func (t *OptimizationTestCase) Status() TestCaseStatus {
 }
 
 // CallSequence describes the calls.CallSequence of calls sent to the EVM which resulted in this TestCase result.
-// This should be nil if the result is not related to the CallSequence.
+// This should be nil if the result is not related to the CallSequence. For tests with several objectives, this is the
+// call sequence which produced the best value for the first objective.
 func (t *OptimizationTestCase) CallSequence() *calls.CallSequence {
-	return t.callSequence
+	return t.objectives[0].callSequence
 }
 
 // Name describes the name of the test case.
@@ -48,6 +73,14 @@ func (t *OptimizationTestCase) Name() string {
 	return fmt.Sprintf("Optimization Test: %s.%s", t.targetContract.Name(), t.targetMethod.Sig)
 }
 
+// objectiveName describes the name of the objective at the provided index, for use in log messages.
+func (t *OptimizationTestCase) objectiveName(index int) string {
+	if t.objectives[index].name != "" {
+		return fmt.Sprintf("\"%s\"", t.objectives[index].name)
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
 // LogMessage obtains a buffer that represents the result of the OptimizationTestCase. This buffer can be passed to a logger for
 // console or file logging.
 func (t *OptimizationTestCase) LogMessage() *logging.LogBuffer {
@@ -55,16 +88,42 @@ func (t *OptimizationTestCase) LogMessage() *logging.LogBuffer {
 
 	// Note that optimization tests will always pass
 	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
-	if t.Status() != TestCaseStatusNotStarted {
-		buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" resulted in the maximum value: ", t.targetContract.Name(), t.targetMethod.Sig))
-		buffer.Append(colors.Bold, t.value, colors.Reset, "\n")
-		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
-		buffer.Append(t.CallSequence().Log().Elements()...)
+	if t.Status() == TestCaseStatusNotStarted {
+		return buffer
 	}
-	// If an execution trace is attached then add it to the message
-	if t.optimizationTestTrace != nil {
-		buffer.Append(colors.Bold, "[Optimization Test Execution Trace]", colors.Reset, "\n")
-		buffer.Append(t.optimizationTestTrace.Log().Elements()...)
+
+	goal := "maximum"
+	if t.minimize {
+		goal = "minimum"
+	}
+	for i, objective := range t.objectives {
+		// If no value was found for this objective, there is nothing to report.
+		if objective.callSequence == nil {
+			continue
+		}
+
+		// Describe the best value, only naming the objective if the test method has several of them.
+		if len(t.objectives) == 1 {
+			buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" resulted in the %s value: ", t.targetContract.Name(), t.targetMethod.Sig, goal))
+		} else {
+			buffer.Append(fmt.Sprintf("Objective %s of method \"%s.%s\" resulted in the %s value: ", t.objectiveName(i), t.targetContract.Name(), t.targetMethod.Sig, goal))
+		}
+		buffer.Append(colors.Bold, objective.value, colors.Reset, "\n")
+
+		// Describe when the value improved over the course of the campaign.
+		buffer.Append(colors.Bold, "[Improvement Timeline]", colors.Reset, "\n")
+		for _, improvement := range objective.timeline {
+			buffer.Append(fmt.Sprintf("%s (%v calls): %v\n", improvement.Elapsed.Round(time.Second), improvement.CallsTested, improvement.Value))
+		}
+
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(objective.callSequence.Log().Elements()...)
+
+		// If an execution trace is attached then add it to the message
+		if objective.optimizationTestTrace != nil {
+			buffer.Append(colors.Bold, "[Optimization Test Execution Trace]", colors.Reset, "\n")
+			buffer.Append(objective.optimizationTestTrace.Log().Elements()...)
+		}
 	}
 	return buffer
 }
@@ -80,7 +139,36 @@ func (t *OptimizationTestCase) ID() string {
 	return strings.Replace(fmt.Sprintf("OPTIMIZATION-%s-%s", t.targetContract.Name(), t.targetMethod.Sig), "_", "-", -1)
 }
 
-// Value obtains the maximum value returned by the test method found till now
+// Minimize indicates whether the values returned by the test method are minimized rather than maximized.
+func (t *OptimizationTestCase) Minimize() bool {
+	return t.minimize
+}
+
+// Value obtains the best value returned by the test method found till now. For tests with several objectives, this is
+// the best value for the first objective.
 func (t *OptimizationTestCase) Value() *big.Int {
-	return t.value
+	return t.objectives[0].value
+}
+
+// Values obtains the best value found till now for each value returned by the test method.
+func (t *OptimizationTestCase) Values() []*big.Int {
+	values := make([]*big.Int, len(t.objectives))
+	for i, objective := range t.objectives {
+		values[i] = objective.value
+	}
+	return values
+}
+
+// Timeline obtains each improvement of the best value found for the objective at the provided index, in the order
+// they were found.
+func (t *OptimizationTestCase) Timeline(objectiveIndex int) []OptimizationImprovement {
+	return t.objectives[objectiveIndex].timeline
+}
+
+// improves indicates whether the provided value improves upon the provided best value, given the test's goal.
+func (t *OptimizationTestCase) improves(value *big.Int, bestValue *big.Int) bool {
+	if t.minimize {
+		return value.Cmp(bestValue) < 0
+	}
+	return value.Cmp(bestValue) > 0
 }
@@ -3,7 +3,9 @@ package fuzzing
 import (
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/contracts"
@@ -13,14 +15,19 @@ import (
 )
 
 const MIN_INT = "-8000000000000000000000000000000000000000000000000000000000000000"
+const MAX_INT = "7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
 
 // OptimizationTestCaseProvider is a provider for on-chain optimization tests.
 // Optimization tests are represented as publicly-accessible functions which have a name prefix specified by a
-// config.FuzzingConfig. They take no input arguments and return an integer value that needs to be maximized.
+// config.FuzzingConfig. They take no input arguments and return one or more integer values that need to be maximized
+// or minimized. Each returned value is optimized independently, with its own best call sequence.
 type OptimizationTestCaseProvider struct {
 	// fuzzer describes the Fuzzer which this provider is attached to.
 	fuzzer *Fuzzer
 
+	// startTime describes the time at which the fuzzing campaign started, used to record when values improved.
+	startTime time.Time
+
 	// testCases is a map of contract-method IDs to optimization test cases.GetContractMethodID
 	testCases map[contracts.ContractMethodID]*OptimizationTestCase
 
@@ -46,7 +53,7 @@ type optimizationTestCaseProviderWorkerState struct {
 // attachOptimizationTestCaseProvider attaches a new OptimizationTestCaseProvider to the Fuzzer and returns it.
 func attachOptimizationTestCaseProvider(fuzzer *Fuzzer) *OptimizationTestCaseProvider {
 	// If there are no testing prefixes, then there is no reason to attach a test case provider and subscribe to events
-	if len(fuzzer.config.Fuzzing.Testing.OptimizationTesting.TestPrefixes) == 0 {
+	if len(fuzzer.config.Fuzzing.Testing.OptimizationTesting.AllTestPrefixes()) == 0 {
 		return nil
 	}
 
@@ -65,9 +72,10 @@ func attachOptimizationTestCaseProvider(fuzzer *Fuzzer) *OptimizationTestCasePro
 	return t
 }
 
-// runOptimizationTest executes a given optimization test method (w/ an optional execution trace) and returns the return value
+// runOptimizationTest executes a given optimization test method (w/ an optional execution trace) and returns the return values
 // from the optimization test method. This is called after every call the Fuzzer makes when testing call sequences for each test case.
-func (t *OptimizationTestCaseProvider) runOptimizationTest(worker *FuzzerWorker, optimizationTestMethod *contracts.DeployedContractMethod, trace bool) (*big.Int, *executiontracer.ExecutionTrace, error) {
+// Returns nil values if the optimization test method reverted, as it produced no values to optimize.
+func (t *OptimizationTestCaseProvider) runOptimizationTest(worker *FuzzerWorker, optimizationTestMethod *contracts.DeployedContractMethod, trace bool) ([]*big.Int, *executiontracer.ExecutionTrace, error) {
 	// Generate our ABI input data for the call. In this case, optimization test methods take no arguments, so the
 	// variadic argument list here is empty.
 	data, err := optimizationTestMethod.Contract.CompiledContract().Abi.Pack(optimizationTestMethod.Method.Name)
@@ -93,11 +101,9 @@ func (t *OptimizationTestCaseProvider) runOptimizationTest(worker *FuzzerWorker,
 		return nil, nil, fmt.Errorf("failed to call optimization test method: %v", err)
 	}
 
-	// If the execution reverted, then we know that we do not have any valuable return data, so we return the smallest
-	// integer value
+	// If the execution reverted, then we know that we do not have any valuable return data, so we return no values.
 	if executionResult.Failed() {
-		minInt256, _ := new(big.Int).SetString(MIN_INT, 16)
-		return minInt256, nil, nil
+		return nil, nil, nil
 	}
 
 	// Decode our ABI outputs
@@ -106,18 +112,22 @@ func (t *OptimizationTestCaseProvider) runOptimizationTest(worker *FuzzerWorker,
 		return nil, nil, fmt.Errorf("failed to decode optimization test method return value: %v", err)
 	}
 
-	// We should have one return value.
-	if len(retVals) != 1 {
+	// We should have one return value per output.
+	if len(retVals) != len(optimizationTestMethod.Method.Outputs) {
 		return nil, nil, fmt.Errorf("detected an unexpected number of return values from optimization test '%s'", optimizationTestMethod.Method.Name)
 	}
 
-	// Parse the return value and it should be an int256
-	newValue, ok := retVals[0].(*big.Int)
-	if !ok {
-		return nil, nil, fmt.Errorf("failed to parse optimization test's: %s return value: %v", optimizationTestMethod.Method.Name, retVals[0])
+	// Parse the return values and they should each be an int256
+	newValues := make([]*big.Int, len(retVals))
+	for i, retVal := range retVals {
+		newValue, ok := retVal.(*big.Int)
+		if !ok {
+			return nil, nil, fmt.Errorf("failed to parse optimization test's: %s return value: %v", optimizationTestMethod.Method.Name, retVal)
+		}
+		newValues[i] = newValue
 	}
 
-	return newValue, executionTrace, nil
+	return newValues, executionTrace, nil
 }
 
 // onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
@@ -126,6 +136,7 @@ func (t *OptimizationTestCaseProvider) onFuzzerStarting(event FuzzerStartingEven
 	// Reset our state
 	t.testCases = make(map[contracts.ContractMethodID]*OptimizationTestCase)
 	t.workerStates = make([]optimizationTestCaseProviderWorkerState, t.fuzzer.Config().Fuzzing.Workers)
+	t.startTime = time.Now()
 
 	// Create a test case for every optimization test method.
 	for _, contract := range t.fuzzer.ContractDefinitions() {
@@ -138,15 +149,34 @@ func (t *OptimizationTestCaseProvider) onFuzzerStarting(event FuzzerStartingEven
 			// Create local variables to avoid pointer types in the loop being overridden.
 			contract := contract
 			method := method
-			minInt256, _ := new(big.Int).SetString(MIN_INT, 16)
+
+			// Determine whether the method's values are minimized, and start each objective from the worst value.
+			minimize := false
+			for _, prefix := range t.fuzzer.config.Fuzzing.Testing.OptimizationTesting.MinimizeTestPrefixes {
+				if strings.HasPrefix(method.Name, prefix) {
+					minimize = true
+					break
+				}
+			}
+			objectives := make([]*optimizationObjective, len(method.Outputs))
+			for i, output := range method.Outputs {
+				worstValue, _ := new(big.Int).SetString(MIN_INT, 16)
+				if minimize {
+					worstValue, _ = new(big.Int).SetString(MAX_INT, 16)
+				}
+				objectives[i] = &optimizationObjective{
+					name:  output.Name,
+					value: worstValue,
+				}
+			}
 
 			// Create our optimization test case
 			optimizationTestCase := &OptimizationTestCase{
 				status:         TestCaseStatusNotStarted,
 				targetContract: contract,
 				targetMethod:   method,
-				callSequence:   nil,
-				value:          minInt256,
+				minimize:       minimize,
+				objectives:     objectives,
 			}
 
 			// Add to our test cases and register them with the fuzzer
@@ -263,10 +293,10 @@ func (t *OptimizationTestCaseProvider) onWorkerDeployedContractDeleted(event Fuz
 
 // callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached Fuzzer
 // and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether any
-// optimization test's value has increased.
+// optimization test's objective has improved.
 func (t *OptimizationTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
-	// Create a list of shrink call sequence verifiers, which we populate for each maximized optimization test we want a call
-	// sequence shrunk for.
+	// Create a list of shrink call sequence verifiers, which we populate for each improved optimization test objective
+	// we want a call sequence shrunk for.
 	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
 
 	// Obtain the test provider state for this worker
@@ -279,19 +309,36 @@ func (t *OptimizationTestCaseProvider) callSequencePostCallTest(worker *FuzzerWo
 		testCase := t.testCases[optimizationTestMethodId]
 		t.testCasesLock.Unlock()
 
+		// If the call sequence does not apply to this test case, skip it
+		if !t.fuzzer.callSequenceAllowed(testCase.targetContract, &testCase.targetMethod, callSequence) {
+			continue
+		}
+
 		// Run our optimization test (create a local copy to avoid loop overwriting the method)
 		workerOptimizationTestMethod := workerOptimizationTestMethod
-		newValue, _, err := t.runOptimizationTest(worker, &workerOptimizationTestMethod, false)
+		newValues, _, err := t.runOptimizationTest(worker, &workerOptimizationTestMethod, false)
 		if err != nil {
 			return nil, err
 		}
 
-		// If we updated the test case's maximum value, we update our state immediately. We provide a shrink verifier which will update
-		// the call sequence for each shrunken sequence provided that still it maintains the maximum value.
-		// TODO: This is very inefficient since this runs every time a new max value is found. It would be ideal if we
-		//  could perform a one-time shrink request. This code should be refactored when we introduce the high-level
-		//  testing API.
-		if newValue.Cmp(testCase.value) == 1 {
+		// If the optimization test reverted, there are no values to improve upon.
+		if newValues == nil {
+			continue
+		}
+
+		// Each objective is optimized independently, so we check each for improvements.
+		for objectiveIndex, objective := range testCase.objectives {
+			// If we improved the objective's best value, we provide a shrink verifier which will update the call
+			// sequence for each shrunken sequence provided that it still maintains the improved value.
+			// TODO: This is very inefficient since this runs every time a new best value is found. It would be ideal if we
+			//  could perform a one-time shrink request. This code should be refactored when we introduce the high-level
+			//  testing API.
+			objectiveIndex, objective := objectiveIndex, objective
+			newValue := newValues[objectiveIndex]
+			if !testCase.improves(newValue, objective.value) {
+				continue
+			}
+
 			// Create a request to shrink this call sequence.
 			shrinkRequest := ShrinkCallSequenceRequest{
 				VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
@@ -303,46 +350,57 @@ func (t *OptimizationTestCaseProvider) callSequencePostCallTest(worker *FuzzerWo
 						return false, nil
 					}
 
-					// Then the shrink verifier ensures that the maximum value has either stayed the same or, hopefully,
-					// increased.
-					shrunkenSequenceNewValue, _, err := t.runOptimizationTest(worker, &workerOptimizationTestMethod, false)
+					// Then the shrink verifier ensures that the objective's value has either stayed the same or,
+					// hopefully, improved.
+					shrunkenSequenceNewValues, _, err := t.runOptimizationTest(worker, &workerOptimizationTestMethod, false)
+					if err != nil || shrunkenSequenceNewValues == nil {
+						return false, err
+					}
+					shrunkenSequenceNewValue := shrunkenSequenceNewValues[objectiveIndex]
 
-					// If the shrunken value is greater than new value, then set new value to the shrunken one so that it
-					// can be tracked correctly in the finished callback
-					if err == nil && shrunkenSequenceNewValue.Cmp(newValue) == 1 {
+					// If the shrunken value improves upon the new value, then set new value to the shrunken one so that
+					// it can be tracked correctly in the finished callback
+					if testCase.improves(shrunkenSequenceNewValue, newValue) {
 						newValue = new(big.Int).Set(shrunkenSequenceNewValue)
 					}
 
-					return shrunkenSequenceNewValue.Cmp(newValue) >= 0, err
+					return !testCase.improves(newValue, shrunkenSequenceNewValue), nil
 				},
-				FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+				FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error {
 					// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
 					if len(shrunkenCallSequence) > 0 {
-						_, err = calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
+						_, err := calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
 						if err != nil {
 							return err
 						}
 					}
 
-					// Execute the property test a final time, this time obtaining an execution trace
-					shrunkenSequenceNewValue, executionTrace, err := t.runOptimizationTest(worker, &workerOptimizationTestMethod, true)
+					// Execute the optimization test a final time, this time obtaining an execution trace
+					shrunkenSequenceNewValues, executionTrace, err := t.runOptimizationTest(worker, &workerOptimizationTestMethod, true)
 					if err != nil {
 						return err
 					}
 
-					// If, for some reason, the shrunken sequence lowers the new max value, do not save anything and exit
-					if shrunkenSequenceNewValue.Cmp(newValue) < 0 {
-						return fmt.Errorf("optimized call sequence failed to maximize value")
+					// If, for some reason, the shrunken sequence worsens the new best value, do not save anything and exit
+					if shrunkenSequenceNewValues == nil || testCase.improves(newValue, shrunkenSequenceNewValues[objectiveIndex]) {
+						return fmt.Errorf("optimized call sequence failed to maintain the best value")
 					}
+					shrunkenSequenceNewValue := shrunkenSequenceNewValues[objectiveIndex]
 
-					// Update our value with lock
+					// Update our objective with lock, provided another worker did not find a better value meanwhile.
 					testCase.valueLock.Lock()
-					testCase.value = new(big.Int).Set(shrunkenSequenceNewValue)
-					testCase.valueLock.Unlock()
-
-					// Update call sequence and trace
-					testCase.callSequence = &shrunkenCallSequence
-					testCase.optimizationTestTrace = executionTrace
+					defer testCase.valueLock.Unlock()
+					if !testCase.improves(shrunkenSequenceNewValue, objective.value) {
+						return nil
+					}
+					objective.value = new(big.Int).Set(shrunkenSequenceNewValue)
+					objective.callSequence = &shrunkenCallSequence
+					objective.optimizationTestTrace = executionTrace
+					objective.timeline = append(objective.timeline, OptimizationImprovement{
+						Value:       objective.value,
+						Elapsed:     time.Since(t.startTime),
+						CallsTested: t.fuzzer.metrics.CallsTested(),
+					})
 					return nil
 				},
 				RecordResultInCorpus: true,
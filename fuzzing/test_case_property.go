@@ -19,6 +19,8 @@ type PropertyTestCase struct {
 	targetContract *fuzzerTypes.Contract
 	// targetMethod describes the target method for the test case
 	targetMethod abi.Method
+	// expectRevert describes whether the target method is expected to revert for the test to pass
+	expectRevert bool
 	// callSequence describes the call sequence that broke the property
 	callSequence *calls.CallSequence
 	// propertyTestTrace describes the execution trace when running the callSequence
@@ -44,11 +46,19 @@ func (t *PropertyTestCase) Name() string {
 // LogMessage obtains a buffer that represents the result of the PropertyTestCase. This buffer can be passed to a logger for
 // console or file logging.
 func (t *PropertyTestCase) LogMessage() *logging.LogBuffer {
-	// If the test failed, return a failure message.
+	// If the test failed or errored, return a failure message.
 	buffer := logging.NewLogBuffer()
-	if t.Status() == TestCaseStatusFailed {
-		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
-		buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" failed after the following call sequence:\n", t.targetContract.Name(), t.targetMethod.Sig))
+	if t.Status() == TestCaseStatusFailed || t.Status() == TestCaseStatusErrored {
+		if t.Status() == TestCaseStatusErrored {
+			buffer.Append(colors.YellowBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+			buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" reverted after the following call sequence:\n", t.targetContract.Name(), t.targetMethod.Sig))
+		} else if t.expectRevert {
+			buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+			buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" did not revert after the following call sequence:\n", t.targetContract.Name(), t.targetMethod.Sig))
+		} else {
+			buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+			buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" failed after the following call sequence:\n", t.targetContract.Name(), t.targetMethod.Sig))
+		}
 		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
 		buffer.Append(t.CallSequence().Log().Elements()...)
 
@@ -8,6 +8,7 @@ import (
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/contracts"
 	"github.com/crytic/medusa/fuzzing/executiontracer"
+	fuzzingutils "github.com/crytic/medusa/fuzzing/utils"
 	"github.com/ethereum/go-ethereum/core"
 	"golang.org/x/exp/slices"
 )
@@ -17,6 +18,8 @@ import (
 // config.FuzzingConfig. They take no input arguments and return a boolean indicating whether the test passed.
 // If a call to any on-chain property test returns false, the test signals a failed status. If no failure is found
 // before the fuzzing campaign ends, the test signals a passed status.
+// Property tests with a name prefix specified as a revert test prefix are instead expected to revert, and signal a
+// failed status if a call to them does not.
 type PropertyTestCaseProvider struct {
 	// fuzzer describes the Fuzzer which this provider is attached to.
 	fuzzer *Fuzzer
@@ -47,7 +50,7 @@ type propertyTestCaseProviderWorkerState struct {
 // attachPropertyTestCaseProvider attaches a new PropertyTestCaseProvider to the Fuzzer and returns it.
 func attachPropertyTestCaseProvider(fuzzer *Fuzzer) *PropertyTestCaseProvider {
 	// If there are no testing prefixes, then there is no reason to attach a test case provider and subscribe to events
-	if len(fuzzer.config.Fuzzing.Testing.PropertyTesting.TestPrefixes) == 0 {
+	if len(fuzzer.config.Fuzzing.Testing.PropertyTesting.TestPrefixes) == 0 && len(fuzzer.config.Fuzzing.Testing.PropertyTesting.RevertTestPrefixes) == 0 {
 		return nil
 	}
 
@@ -63,20 +66,22 @@ func attachPropertyTestCaseProvider(fuzzer *Fuzzer) *PropertyTestCaseProvider {
 
 	// Add the provider's call sequence test function to the fuzzer.
 	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.callSequencePostCallTest)
+	fuzzer.Hooks.CallSequenceEndTestFuncs = append(fuzzer.Hooks.CallSequenceEndTestFuncs, t.callSequenceEndTest)
 	return t
 }
 
 // checkPropertyTestFailed executes a given property test method to see if it returns a failed status. This is used to
 // facilitate testing of property test methods after every call the Fuzzer makes when testing call sequences.
-// A boolean indicating whether an execution trace should be captured and returned is provided to the method.
-// Returns a boolean indicating if the property test failed, an optional execution trace for the property test call,
-// or an error if one occurred.
-func (t *PropertyTestCaseProvider) checkPropertyTestFailed(worker *FuzzerWorker, propertyTestMethod *contracts.DeployedContractMethod, trace bool) (bool, *executiontracer.ExecutionTrace, error) {
+// A boolean indicating whether the property test is expected to revert, and a boolean indicating whether an execution
+// trace should be captured and returned are provided to the method.
+// Returns a boolean indicating if the property test failed, a boolean indicating if the property test errored, an
+// optional execution trace for the property test call, or an error if one occurred.
+func (t *PropertyTestCaseProvider) checkPropertyTestFailed(worker *FuzzerWorker, propertyTestMethod *contracts.DeployedContractMethod, expectRevert bool, trace bool) (bool, bool, *executiontracer.ExecutionTrace, error) {
 	// Generate our ABI input data for the call. In this case, property test methods take no arguments, so the
 	// variadic argument list here is empty.
 	data, err := propertyTestMethod.Contract.CompiledContract().Abi.Pack(propertyTestMethod.Method.Name)
 	if err != nil {
-		return false, nil, err
+		return false, false, nil, err
 	}
 
 	// Create a call targeting our property test method
@@ -93,33 +98,41 @@ func (t *PropertyTestCaseProvider) checkPropertyTestFailed(worker *FuzzerWorker,
 		executionResult, err = worker.Chain().CallContract(msg.ToCoreMessage(), nil)
 	}
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to call property test method: %v", err)
+		return false, false, nil, fmt.Errorf("failed to call property test method: %v", err)
 	}
 
-	// If our property test method call failed, we flag a failed test.
+	// If our property test method is expected to revert, it fails only if it did not.
+	if expectRevert {
+		return !executionResult.Failed(), false, executionTrace, nil
+	}
+
+	// If our property test method call failed, we flag a failed test, or an errored one if configured to do so.
 	if executionResult.Failed() {
-		return true, executionTrace, nil
+		if t.fuzzer.config.Fuzzing.Testing.PropertyTesting.ErrorOnRevert {
+			return false, true, executionTrace, nil
+		}
+		return true, false, executionTrace, nil
 	}
 
 	// Decode our ABI outputs
 	retVals, err := propertyTestMethod.Method.Outputs.Unpack(executionResult.Return())
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to decode property test method return value: %v", err)
+		return false, false, nil, fmt.Errorf("failed to decode property test method return value: %v", err)
 	}
 
 	// We should have one return value.
 	if len(retVals) != 1 {
-		return false, nil, fmt.Errorf("detected an unexpected number of return values from property test '%s'", propertyTestMethod.Method.Name)
+		return false, false, nil, fmt.Errorf("detected an unexpected number of return values from property test '%s'", propertyTestMethod.Method.Name)
 	}
 
 	// The one return value should be a bool
 	propertyTestMethodPassed, ok := retVals[0].(bool)
 	if !ok {
-		return false, nil, fmt.Errorf("failed to parse property test method success status from return value '%s'", propertyTestMethod.Method.Name)
+		return false, false, nil, fmt.Errorf("failed to parse property test method success status from return value '%s'", propertyTestMethod.Method.Name)
 	}
 
 	// Return our property test results
-	return !propertyTestMethodPassed, executionTrace, nil
+	return !propertyTestMethodPassed, false, executionTrace, nil
 }
 
 // onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
@@ -146,6 +159,7 @@ func (t *PropertyTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) e
 				status:         TestCaseStatusNotStarted,
 				targetContract: contract,
 				targetMethod:   method,
+				expectRevert:   fuzzingutils.IsRevertPropertyTest(method, t.fuzzer.config.Fuzzing.Testing.PropertyTesting.RevertTestPrefixes),
 				callSequence:   nil,
 			}
 
@@ -261,10 +275,40 @@ func (t *PropertyTestCaseProvider) onWorkerDeployedContractDeleted(event FuzzerW
 	return nil
 }
 
+// shouldCheckPropertyAfterCall indicates whether the provided property test should be checked after the last call in
+// the provided call sequence, given the configured check frequency. Properties are additionally checked once execution
+// of a call sequence ends, by callSequenceEndTest.
+func (t *PropertyTestCaseProvider) shouldCheckPropertyAfterCall(testCase *PropertyTestCase, callSequence calls.CallSequence) bool {
+	// Properties are always checked once the sequence reaches the maximum length for the test.
+	if len(callSequence) == t.fuzzer.testCallSequenceLength(testCase.targetContract, &testCase.targetMethod) {
+		return true
+	}
+
+	// Otherwise, they are checked every N calls, unless we only check at the end of a sequence.
+	checkFrequency := t.fuzzer.config.Fuzzing.Testing.PropertyTesting.CheckFrequency
+	return checkFrequency > 0 && len(callSequence)%checkFrequency == 0
+}
+
 // callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached Fuzzer
 // and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether property
-// test invariants are upheld after each call the Fuzzer makes when testing a call sequence.
+// test invariants are upheld after each call the Fuzzer makes when testing a call sequence, or less often if
+// configured to do so.
 func (t *PropertyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	return t.checkPropertyTests(worker, callSequence, false)
+}
+
+// callSequenceEndTest is a CallSequenceTestFunc called once execution of a call sequence ends, whether every call in it
+// was executed or another test requested it be shrunk. It checks any property test invariants which were not already
+// checked after the last call, so that properties are always checked at the end of a sequence.
+func (t *PropertyTestCaseProvider) callSequenceEndTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	return t.checkPropertyTests(worker, callSequence, true)
+}
+
+// checkPropertyTests checks property test invariants after the last call in the provided call sequence. If
+// sequenceEnded is false, only properties which should be checked after this call are checked. Otherwise, only those
+// which were not are checked, as execution of the call sequence has ended.
+// Returns requests to shrink the call sequence for each failed property test, or an error if one occurred.
+func (t *PropertyTestCaseProvider) checkPropertyTests(worker *FuzzerWorker, callSequence calls.CallSequence, sequenceEnded bool) ([]ShrinkCallSequenceRequest, error) {
 	// Create a list of shrink call sequence verifiers, which we populate for each failed property test we want a call
 	// sequence shrunk for.
 	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
@@ -279,21 +323,27 @@ func (t *PropertyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker
 		testCase := t.testCases[propertyTestMethodId]
 		t.testCasesLock.Unlock()
 
-		// If the test case already failed, skip it
-		if testCase.Status() == TestCaseStatusFailed {
+		// If the test case already failed or errored, or it does not apply to this call sequence, skip it
+		if testCase.Status() == TestCaseStatusFailed || testCase.Status() == TestCaseStatusErrored || !t.fuzzer.callSequenceAllowed(testCase.targetContract, &testCase.targetMethod, callSequence) {
+			continue
+		}
+
+		// Properties checked after this call need not be checked again when the sequence ends, and vice versa.
+		if t.shouldCheckPropertyAfterCall(testCase, callSequence) == sequenceEnded {
 			continue
 		}
 
 		// Test our property test method (create a local copy to avoid loop overwriting the method)
 		workerPropertyTestMethod := workerPropertyTestMethod
-		failedPropertyTest, _, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, false)
+		failedPropertyTest, erroredPropertyTest, _, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, testCase.expectRevert, false)
 		if err != nil {
 			return nil, err
 		}
 
 		// If we failed a test, we update our state immediately. We provide a shrink verifier which will update
-		// the call sequence for each shrunken sequence provided that fails the property test.
-		if failedPropertyTest {
+		// the call sequence for each shrunken sequence provided that fails (or errors) the property test in the same
+		// way.
+		if failedPropertyTest || erroredPropertyTest {
 			// Create a request to shrink this call sequence.
 			shrinkRequest := ShrinkCallSequenceRequest{
 				VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
@@ -307,10 +357,10 @@ func (t *PropertyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker
 
 					// Then the shrink verifier simply ensures the previously failed property test fails
 					// for the shrunk sequence as well.
-					shrunkenSequenceFailedTest, _, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, false)
-					return shrunkenSequenceFailedTest, err
+					shrunkenSequenceFailedTest, shrunkenSequenceErroredTest, _, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, testCase.expectRevert, false)
+					return shrunkenSequenceFailedTest == failedPropertyTest && shrunkenSequenceErroredTest == erroredPropertyTest, err
 				},
-				FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+				FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool, flakiness *CallSequenceFlakiness) error {
 					// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
 					if len(shrunkenCallSequence) > 0 {
 						_, err = calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
@@ -320,23 +370,31 @@ func (t *PropertyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker
 					}
 
 					// Execute the property test a final time, this time obtaining an execution trace
-					shrunkenSequenceFailedTest, executionTrace, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, true)
+					shrunkenSequenceFailedTest, shrunkenSequenceErroredTest, executionTrace, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, testCase.expectRevert, true)
 					if err != nil {
 						return err
 					}
-					if !shrunkenSequenceFailedTest {
+					if !shrunkenSequenceFailedTest && !shrunkenSequenceErroredTest {
 						return fmt.Errorf("property test provider did not fail property test on final shrunken sequence")
 					}
 
 					// Update our test state and report it finalized.
-					testCase.status = TestCaseStatusFailed
+					if shrunkenSequenceErroredTest {
+						testCase.status = TestCaseStatusErrored
+					} else {
+						testCase.status = TestCaseStatusFailed
+					}
 					testCase.callSequence = &shrunkenCallSequence
 					testCase.propertyTestTrace = executionTrace
 					worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
-					worker.Fuzzer().ReportTestCaseFinished(testCase)
+					worker.reportTestCaseFinished(testCase, flakiness)
 					return nil
 				},
 				RecordResultInCorpus: true,
+				CheckReproducibility: true,
+				// If properties are not checked after every call, the call which failed the test may be well before
+				// the one after which the failure was detected.
+				BisectBeforeShrinking: t.fuzzer.config.Fuzzing.Testing.PropertyTesting.CheckFrequency != 1,
 			}
 
 			// Add our shrink request to our list.
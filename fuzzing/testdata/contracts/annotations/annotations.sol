@@ -0,0 +1,33 @@
+// This test ensures per-test configuration provided through NatSpec annotations is applied.
+contract TestContract {
+    uint x;
+    bool unlocked;
+
+    function setX(uint value) public {
+        x = value;
+    }
+
+    /// @custom:medusa-skip
+    function unlock() public {
+        unlocked = true;
+    }
+
+    /// @custom:medusa-skip
+    function property_skipped() public view returns (bool) {
+        return false;
+    }
+
+    /// @custom:medusa-expect-fail
+    function property_expectedToFail() public view returns (bool) {
+        return x != 10;
+    }
+
+    /// @custom:medusa-exclude TestContract.setX
+    function property_excludesSetX() public view returns (bool) {
+        return x != 10;
+    }
+
+    function property_neverUnlocked() public view returns (bool) {
+        return !unlocked;
+    }
+}
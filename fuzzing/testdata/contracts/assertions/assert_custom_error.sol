@@ -0,0 +1,18 @@
+// This contract ensures the fuzzer treats a revert with a configured custom error as an assertion failure, while other
+// reverts are not.
+contract TestContract {
+    error InvariantViolated(uint256 value);
+    error Unrelated(uint256 value);
+
+    function checkValue(uint value) public {
+        // ASSERTION: We fail if the provided value is odd.
+        if (value % 2 == 1) {
+            revert InvariantViolated(value);
+        }
+    }
+
+    function unrelatedRevert(uint value) public {
+        // This is not an assertion failure, as the custom error is not configured.
+        revert Unrelated(value);
+    }
+}
@@ -0,0 +1,24 @@
+// This contract ensures the fuzzer treats the emission of a configured event, even from a nested call, as an
+// assertion failure.
+contract Helper {
+    event AssertionFailed(string reason, uint value);
+
+    function check(uint value) public {
+        if (value % 2 == 1) {
+            emit AssertionFailed("odd value", value);
+        }
+    }
+}
+
+contract TestContract {
+    Helper helper;
+
+    constructor() {
+        helper = new Helper();
+    }
+
+    function checkValue(uint value) public {
+        // ASSERTION: We fail (via the helper's event) if the provided value is odd.
+        helper.check(value);
+    }
+}
@@ -0,0 +1,32 @@
+// This test ensures that values added to the fuzzer's dictionary through cheat codes are used in value generation.
+interface MedusaCheats {
+    function addToDictionary(uint256) external;
+    function addToDictionary(address) external;
+    function addToDictionary(bytes calldata) external;
+    function addToDictionary(string calldata) external;
+    function interesting(uint256) external;
+}
+
+contract TestContract {
+    // Obtain our cheat code contract reference.
+    MedusaCheats medusa = MedusaCheats(0x000000000000006d65647573612e66757a7a6572);
+
+    function magicValue() internal view returns (uint256) {
+        // Derive a value which cannot be seeded from constants in the source.
+        return uint256(keccak256(abi.encodePacked(address(this), block.chainid)));
+    }
+
+    function hint() public {
+        // Provide our derived value to the fuzzer, along with some values of other types.
+        medusa.addToDictionary(magicValue());
+        medusa.addToDictionary(address(this));
+        medusa.addToDictionary(abi.encodePacked(magicValue()));
+        medusa.addToDictionary("medusa");
+        medusa.interesting(1);
+    }
+
+    function test(uint256 x) public {
+        // This should only be reachable if the derived value was added to the dictionary.
+        assert(x != magicValue());
+    }
+}
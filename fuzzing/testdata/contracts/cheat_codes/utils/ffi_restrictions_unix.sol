@@ -0,0 +1,81 @@
+// This test ensures the ffi cheat code enforces its configured restrictions, and provides input to commands through
+// stdin. It expects "sh" to be the only allowed command, a timeout of one second, and only PATH and
+// MEDUSA_FFI_TEST_VISIBLE to be passed to commands.
+interface CheatCodes {
+    function ffi(string[] calldata) external returns (bytes memory);
+    function ffi(string[] calldata, bytes calldata) external returns (bytes memory);
+    function tryFfi(string[] calldata, bytes calldata) external returns (int256, bytes memory, bytes memory);
+}
+
+contract TestContract {
+    CheatCodes cheats;
+    bool timeoutChecked;
+
+    constructor() {
+        cheats = CheatCodes(0x7109709ECfa91a80626fF3989D68f67F5b1DD12D);
+    }
+
+    function testDeniedCommand() public {
+        // Create a command which is not in the list of allowed commands
+        string[] memory inputs = new string[](2);
+        inputs[0] = "echo";
+        inputs[1] = "hello";
+
+        // Call cheats.ffi, which should revert
+        try cheats.ffi(inputs) returns (bytes memory) {
+            assert(false);
+        } catch {}
+    }
+
+    function testTimeout() public {
+        // Only check the timeout once per call sequence, as every check takes a second.
+        if (timeoutChecked) {
+            return;
+        }
+        timeoutChecked = true;
+
+        // Create a command which runs for longer than the timeout
+        string[] memory inputs = new string[](3);
+        inputs[0] = "sh";
+        inputs[1] = "-c";
+        inputs[2] = "sleep 5";
+
+        // Call cheats.ffi, which should revert once the command times out
+        try cheats.ffi(inputs) returns (bytes memory) {
+            assert(false);
+        } catch {}
+    }
+
+    function testStrippedEnvironment() public {
+        // Create a command which prints a whitelisted and a non-whitelisted environment variable
+        string[] memory inputs = new string[](3);
+        inputs[0] = "sh";
+        inputs[1] = "-c";
+        inputs[2] = "printf \"$MEDUSA_FFI_TEST_VISIBLE:$MEDUSA_FFI_TEST_STRIPPED\"";
+
+        // Call cheats.ffi, and verify only the whitelisted variable was set
+        bytes memory res = cheats.ffi(inputs);
+        assert(keccak256(res) == keccak256(abi.encodePacked("visible:")));
+    }
+
+    function testStdin() public {
+        // Create a command which echoes its stdin
+        string[] memory inputs = new string[](3);
+        inputs[0] = "sh";
+        inputs[1] = "-c";
+        inputs[2] = "cat";
+
+        // Call cheats.ffi with input, and verify the command received it
+        bytes memory res = cheats.ffi(inputs, "hello");
+        assert(keccak256(res) == keccak256(abi.encodePacked("hello")));
+
+        // Create a command which echoes its stdin to stderr and fails
+        inputs[2] = "cat >&2; exit 3";
+
+        // Call cheats.tryFfi with input, and verify the exit code and output
+        (int256 exitCode, bytes memory stdout, bytes memory stderr) = cheats.tryFfi(inputs, "hello");
+        assert(exitCode == 3);
+        assert(stdout.length == 0);
+        assert(keccak256(stderr) == keccak256(abi.encodePacked("hello")));
+    }
+}
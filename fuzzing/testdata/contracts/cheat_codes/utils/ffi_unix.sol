@@ -1,5 +1,6 @@
 interface CheatCodes {
     function ffi(string[] calldata) external returns (bytes memory);
+    function tryFfi(string[] calldata) external returns (int256, bytes memory, bytes memory);
 }
 
 contract TestContract {
@@ -39,4 +40,18 @@ contract TestContract {
         string memory output = string(res);
         assert(keccak256(abi.encodePacked(output)) == keccak256(abi.encodePacked("hello")));
     }
+
+    function testTryFfi() public {
+        // Create a command which writes to stderr and exits with a non-zero exit code
+        string[] memory inputs = new string[](3);
+        inputs[0] = "sh";
+        inputs[1] = "-c";
+        inputs[2] = "printf hello; printf oops >&2; exit 3";
+
+        // Call cheats.tryFfi, which should not revert on a non-zero exit code
+        (int256 exitCode, bytes memory stdout, bytes memory stderr) = cheats.tryFfi(inputs);
+        assert(exitCode == 3);
+        assert(keccak256(stdout) == keccak256(abi.encodePacked("hello")));
+        assert(keccak256(stderr) == keccak256(abi.encodePacked("oops")));
+    }
 }
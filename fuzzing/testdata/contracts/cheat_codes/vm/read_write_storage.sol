@@ -0,0 +1,75 @@
+// This test ensures that named storage variables can be read and written with cheat codes
+interface CheatCodes {
+    function readStorage(address, string calldata) external returns (bytes32);
+    function writeStorage(address, string calldata, bytes32) external;
+}
+
+contract TestContract {
+    struct Config {
+        uint64 fee;
+        address owner;
+        bool paused;
+    }
+
+    uint128 small = 7;
+    int8 negative = -3;
+    mapping(address => uint256) balances;
+    mapping(string => uint256) named;
+    Config config;
+    uint64[] values;
+
+    constructor() {
+        balances[address(0xabc)] = 100;
+        named["medusa"] = 200;
+        config = Config(5, address(this), false);
+        values.push(1);
+        values.push(2);
+        values.push(3);
+        values.push(4);
+        values.push(5);
+    }
+
+    function test() public {
+        // Obtain our cheat code contract reference.
+        CheatCodes cheats = CheatCodes(0x7109709ECfa91a80626fF3989D68f67F5b1DD12D);
+
+        // Read packed variables and verify them.
+        assert(uint256(cheats.readStorage(address(this), "small")) == 7);
+        assert(int256(uint256(cheats.readStorage(address(this), "negative"))) == -3);
+
+        // Read mapping values and verify them.
+        assert(uint256(cheats.readStorage(address(this), "balances[0x0000000000000000000000000000000000000abc]")) == 100);
+        assert(uint256(cheats.readStorage(address(this), "named[\"medusa\"]")) == 200);
+
+        // Read struct members and verify them.
+        assert(uint256(cheats.readStorage(address(this), "config.fee")) == 5);
+        assert(address(uint160(uint256(cheats.readStorage(address(this), "config.owner")))) == address(this));
+
+        // Read array elements and length and verify them.
+        assert(uint256(cheats.readStorage(address(this), "values")) == 5);
+        assert(uint256(cheats.readStorage(address(this), "values[4]")) == 5);
+
+        // Write packed variables and ensure neighbouring variables are untouched.
+        cheats.writeStorage(address(this), "negative", bytes32(uint256(9)));
+        assert(negative == 9);
+        assert(small == 7);
+        cheats.writeStorage(address(this), "config.paused", bytes32(uint256(1)));
+        assert(config.paused);
+        assert(config.owner == address(this));
+
+        // Write mapping values and array elements and verify them.
+        cheats.writeStorage(address(this), "balances[0x0000000000000000000000000000000000000abc]", bytes32(uint256(555)));
+        assert(balances[address(0xabc)] == 555);
+        cheats.writeStorage(address(this), "values[2]", bytes32(uint256(42)));
+        assert(values[2] == 42);
+        assert(values[1] == 2 && values[3] == 4);
+
+        // Ensure unknown variables and non-value types cause a revert.
+        try cheats.readStorage(address(this), "unknown") {
+            assert(false);
+        } catch {}
+        try cheats.readStorage(address(this), "config") {
+            assert(false);
+        } catch {}
+    }
+}
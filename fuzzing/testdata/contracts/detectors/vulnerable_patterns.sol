@@ -0,0 +1,34 @@
+// This test ensures the fuzzer's trace-based detectors report each of the vulnerable patterns below.
+contract Recipient {
+    uint public received;
+
+    receive() external payable {
+        received += msg.value;
+    }
+}
+
+contract TestContract {
+    mapping(address => uint) public balances;
+
+    function deposit(address account) public payable {
+        balances[account] += msg.value;
+    }
+
+    function withdraw(address recipient) public {
+        // BUG: The balance is updated after an external call into a contract provided in calldata (reentrancy).
+        uint amount = balances[recipient];
+        (bool success, ) = recipient.call{value: amount}("");
+        require(success);
+        balances[recipient] = 0;
+    }
+
+    function execute(address target, bytes memory data) public {
+        // BUG: The call target is taken from calldata (arbitrary call).
+        target.call(data);
+    }
+
+    function destroy() public {
+        // BUG: Anyone can destroy the contract (reachable selfdestruct).
+        selfdestruct(payable(msg.sender));
+    }
+}
@@ -0,0 +1,26 @@
+// This test ensures the fuzzer detects a divergence in behavior between a reference and candidate implementation.
+// Both contracts compute the average of two values and record it, but the candidate overflows on large inputs.
+contract ReferenceAverage {
+    uint public lastAverage;
+
+    event AverageComputed(uint a, uint b, uint average);
+
+    function average(uint a, uint b) public returns (uint) {
+        lastAverage = (a & b) + (a ^ b) / 2;
+        emit AverageComputed(a, b, lastAverage);
+        return lastAverage;
+    }
+}
+
+contract CandidateAverage {
+    uint public lastAverage;
+
+    event AverageComputed(uint a, uint b, uint average);
+
+    function average(uint a, uint b) public returns (uint) {
+        // The sum may overflow, causing this implementation to revert where the reference does not.
+        lastAverage = (a + b) / 2;
+        emit AverageComputed(a, b, lastAverage);
+        return lastAverage;
+    }
+}
@@ -0,0 +1,38 @@
+// This test ensures the fuzzer does not report a divergence between equivalent stateful methods of contracts which are
+// both fuzzing targets. The candidate must only be changed through calls mirrored from the reference: calling its
+// bump() method directly, or failing to mirror the reference's reset() method, would make their totals diverge.
+contract ReferenceLedger {
+    uint public total;
+
+    function add(uint amount) public returns (uint) {
+        total += amount % 1000;
+        return total;
+    }
+
+    function reset() public {
+        total = 0;
+    }
+
+    function record(uint amount) public {
+        // This method has no counterpart on the candidate, so the pair cannot be compared once it changes state.
+        total += amount % 7;
+    }
+}
+
+contract CandidateLedger {
+    uint public total;
+
+    function addBounded(uint amount) public returns (uint) {
+        uint bounded = amount - (amount / 1000) * 1000;
+        total = total + bounded;
+        return total;
+    }
+
+    function reset() public {
+        total = 0;
+    }
+
+    function bump() public {
+        total += 1;
+    }
+}
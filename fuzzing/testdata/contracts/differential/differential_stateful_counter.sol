@@ -0,0 +1,21 @@
+// This test ensures the fuzzer does not report a divergence between equivalent stateful implementations. Both contracts
+// accumulate a bounded total across calls, so the return data and storage of both only match if every call made to
+// the reference was also made to the candidate, and both sides were executed from the state prior to the call.
+contract ReferenceCounter {
+    uint public total;
+
+    function add(uint amount) public returns (uint) {
+        total += amount % 1000;
+        return total;
+    }
+}
+
+contract CandidateCounter {
+    uint public total;
+
+    function add(uint amount) public returns (uint) {
+        uint bounded = amount - (amount / 1000) * 1000;
+        total = total + bounded;
+        return total;
+    }
+}
@@ -0,0 +1,22 @@
+// This test ensures the fuzzer detects when a sender gains more ETH or tokens than they started with.
+contract TestVault {
+    constructor() payable {}
+
+    function withdraw(uint amount) public {
+        // BUG: Anyone can withdraw ETH they never deposited.
+        if (amount > 1000) {
+            payable(msg.sender).transfer(1 ether);
+        }
+    }
+}
+
+contract TestToken {
+    mapping(address => uint) public balanceOf;
+
+    function claim(uint amount) public {
+        // BUG: Anyone can claim tokens for free.
+        if (amount % 2 == 1) {
+            balanceOf[msg.sender] += 1;
+        }
+    }
+}
@@ -0,0 +1,12 @@
+// This contract ensures the fuzzer can run stateless fuzz tests with arguments, and reports a failure when one reverts.
+contract TestContract {
+    function testFuzz_revertsOnLargeInput(uint256 x, uint256 y) public pure {
+        // FUZZ TEST: We fail if x is large and y is non-zero.
+        require(x < 1000 || y == 0);
+    }
+
+    function testFuzz_neverReverts(uint256 x) public pure {
+        // FUZZ TEST: We never fail.
+        uint256 half = x / 2;
+    }
+}
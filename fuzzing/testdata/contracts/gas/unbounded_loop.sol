@@ -0,0 +1,15 @@
+// This test ensures the fuzzer detects when a call to a method uses more gas than the configured threshold.
+contract TestContract {
+    uint[] public items;
+
+    function addItem(uint value) public {
+        items.push(value);
+    }
+
+    function incrementAll() public {
+        // BUG: The cost of this loop grows with every item added, until the method can no longer be called.
+        for (uint i = 0; i < items.length; i++) {
+            items[i] += 1;
+        }
+    }
+}
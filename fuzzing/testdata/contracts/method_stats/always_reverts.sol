@@ -0,0 +1,18 @@
+// This test ensures the outcomes of calls made to each method are recorded, including decoded revert reasons.
+contract TestContract {
+    uint x;
+
+    error Unauthorized(address caller);
+
+    function setX(uint value) public {
+        x = value;
+    }
+
+    function alwaysReverts() public {
+        revert("always reverts");
+    }
+
+    function alwaysRevertsWithCustomError() public {
+        revert Unauthorized(msg.sender);
+    }
+}
@@ -0,0 +1,26 @@
+// This test ensures minimization and multi-objective optimization tests track the best value for each objective.
+contract TestContract {
+  int256 x;
+  int256 y;
+
+  function setX(int256 _x) public {
+    x = _x;
+  }
+
+  function setY(int256 _y) public {
+    y = _y;
+  }
+
+  function optimize_both() public view returns (int256 first, int256 second) {
+    int256 a = x > 0 && x < 100 ? x : int256(0);
+    int256 b = y > 0 && y < 50 ? y : int256(0);
+    return (a, b);
+  }
+
+  function minimize_x() public view returns (int256) {
+    if (x > -1000 && x < 1000)
+      return x;
+    else
+      return 0;
+  }
+}
@@ -0,0 +1,31 @@
+// This test ensures handler methods are only called when their preconditions are met, and preconditions themselves
+// are never called as part of a call sequence.
+contract TestContract {
+    bool opened;
+    uint deposits;
+
+    function open() public {
+        opened = true;
+    }
+
+    function deposit(uint value) public {
+        // This handler should never be called while closed, since its precondition would not be met.
+        assert(opened);
+        deposits += value % 100;
+    }
+
+    function deposit_precondition() public returns (bool) {
+        // This precondition is not a view method, so it would be fuzzed if it were not paired with its handler.
+        return opened;
+    }
+
+    function withdraw() public {
+        // This handler should only ever be called by the first sender, since its precondition is evaluated for the
+        // sender of the call.
+        assert(msg.sender == address(0x10000));
+    }
+
+    function withdraw_precondition() public view returns (bool) {
+        return msg.sender == address(0x10000);
+    }
+}
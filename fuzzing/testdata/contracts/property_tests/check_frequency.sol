@@ -0,0 +1,17 @@
+// This test ensures property tests which are checked less often than after every call still detect failures.
+contract TestContract {
+    uint x;
+    uint y;
+
+    function setX(uint value) public {
+        x = value;
+    }
+
+    function setY(uint value) public {
+        y = value;
+    }
+
+    function property_xIsNeverTen() public view returns (bool) {
+        return x != 10;
+    }
+}
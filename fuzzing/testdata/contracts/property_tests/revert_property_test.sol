@@ -0,0 +1,26 @@
+// This contract ensures the fuzzer can test properties which are expected to revert, and can report a property which
+// reverts unexpectedly with an errored status.
+contract TestContract {
+    uint x;
+
+    function setX(uint value) public {
+        x = value;
+    }
+
+    function revert_xIsNeverTen() public view returns (bool) {
+        // REVERT PROPERTY: We fail (do not revert) only once x is set to 10.
+        require(x == 10);
+        return true;
+    }
+
+    function revert_alwaysReverts() public view returns (bool) {
+        // REVERT PROPERTY: We never fail, as we always revert.
+        revert();
+    }
+
+    function property_revertsOnTwenty() public view returns (bool) {
+        // PROPERTY: We error (rather than fail) once x is set to 20.
+        require(x != 20);
+        return true;
+    }
+}
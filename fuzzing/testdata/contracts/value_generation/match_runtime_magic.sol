@@ -0,0 +1,21 @@
+// This contract verifies the fuzzer can provide a specific uint function argument which is only computed at runtime,
+// and therefore cannot be mined from the source code, by observing the operands of comparisons.
+contract TestContract {
+    uint magic;
+    bool solved;
+
+    constructor() {
+        magic = uint(keccak256(abi.encodePacked(address(this), block.number)));
+    }
+
+    function setX(uint value) public {
+        if (value == magic) {
+            solved = true;
+        }
+    }
+
+    function property_never_magic_value() public view returns (bool) {
+        // ASSERTION: the runtime magic value should never be provided
+        return !solved;
+    }
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"sort"
 	"strings"
 
 	compilationTypes "github.com/crytic/medusa/compilation/types"
@@ -13,10 +14,17 @@ func IsOptimizationTest(method abi.Method, prefixes []string) bool {
 	// Loop through all enabled prefixes to find a match
 	for _, prefix := range prefixes {
 		if strings.HasPrefix(method.Name, prefix) {
-			// An optimization test must take no inputs and return an int256
-			if len(method.Inputs) == 0 && len(method.Outputs) == 1 && method.Outputs[0].Type.T == abi.IntTy && method.Outputs[0].Type.Size == 256 {
-				return true
+			// An optimization test must take no inputs and return one or more int256 values, each of which is
+			// optimized as its own objective.
+			if len(method.Inputs) != 0 || len(method.Outputs) == 0 {
+				continue
+			}
+			for _, output := range method.Outputs {
+				if output.Type.T != abi.IntTy || output.Type.Size != 256 {
+					return false
+				}
 			}
+			return true
 		}
 	}
 	return false
@@ -37,16 +45,76 @@ func IsPropertyTest(method abi.Method, prefixes []string) bool {
 	return false
 }
 
-// BinTestByType sorts a contract's methods by whether they are assertion, property, or optimization tests.
-func BinTestByType(contract *compilationTypes.CompiledContract, propertyTestPrefixes, optimizationTestPrefixes []string, testViewMethods bool) (assertionTests, propertyTests, optimizationTests []abi.Method) {
-	for _, method := range contract.Abi.Methods {
-		if IsPropertyTest(method, propertyTestPrefixes) {
+// IsRevertPropertyTest checks whether the method is a property test which is expected to revert, given potential naming
+// prefixes it must conform to and its underlying input arguments.
+func IsRevertPropertyTest(method abi.Method, prefixes []string) bool {
+	// Loop through all enabled prefixes to find a match
+	for _, prefix := range prefixes {
+		// The revert property test must simply have the right prefix and take no inputs
+		if strings.HasPrefix(method.Name, prefix) {
+			if len(method.Inputs) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsFuzzTest checks whether the method is a stateless fuzz test given potential naming prefixes it must conform to
+// and its underlying input/output arguments.
+func IsFuzzTest(method abi.Method, prefixes []string) bool {
+	// Loop through all enabled prefixes to find a match
+	for _, prefix := range prefixes {
+		// A fuzz test must simply have the right prefix and take inputs to fuzz
+		if strings.HasPrefix(method.Name, prefix) {
+			if len(method.Inputs) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PreconditionMethodSuffix is the suffix of a precondition method's name, which pairs it with the handler method named
+// without the suffix (e.g. `foo_precondition` is the precondition for `foo`).
+const PreconditionMethodSuffix = "_precondition"
+
+// IsPreconditionMethod checks whether the method is a precondition for a handler method given the naming convention
+// it must conform to and its underlying input/output arguments.
+// Returns the name of the handler method the precondition pairs with, and a boolean indicating whether the method is
+// a precondition.
+func IsPreconditionMethod(method abi.Method) (string, bool) {
+	// A precondition must have the right suffix, take no inputs, and return a boolean
+	handlerName, ok := strings.CutSuffix(method.Name, PreconditionMethodSuffix)
+	if !ok || handlerName == "" {
+		return "", false
+	}
+	if len(method.Inputs) != 0 || len(method.Outputs) != 1 || method.Outputs[0].Type.T != abi.BoolTy {
+		return "", false
+	}
+	return handlerName, true
+}
+
+// BinTestByType sorts a contract's methods by whether they are assertion, property, optimization, or fuzz tests.
+// Property tests which are expected to revert are sorted alongside other property tests. Methods are binned in order of
+// their names, so that the order of each bin is deterministic.
+func BinTestByType(contract *compilationTypes.CompiledContract, propertyTestPrefixes, revertPropertyTestPrefixes, optimizationTestPrefixes, fuzzTestPrefixes []string, testViewMethods bool) (assertionTests, propertyTests, optimizationTests, fuzzTests []abi.Method) {
+	methodNames := make([]string, 0, len(contract.Abi.Methods))
+	for methodName := range contract.Abi.Methods {
+		methodNames = append(methodNames, methodName)
+	}
+	sort.Strings(methodNames)
+	for _, methodName := range methodNames {
+		method := contract.Abi.Methods[methodName]
+		if IsRevertPropertyTest(method, revertPropertyTestPrefixes) || IsPropertyTest(method, propertyTestPrefixes) {
 			propertyTests = append(propertyTests, method)
 		} else if IsOptimizationTest(method, optimizationTestPrefixes) {
 			optimizationTests = append(optimizationTests, method)
+		} else if IsFuzzTest(method, fuzzTestPrefixes) {
+			fuzzTests = append(fuzzTests, method)
 		} else if !method.IsConstant() || testViewMethods {
 			assertionTests = append(assertionTests, method)
 		}
 	}
-	return assertionTests, propertyTests, optimizationTests
+	return assertionTests, propertyTests, optimizationTests, fuzzTests
 }
@@ -0,0 +1,226 @@
+package valuegeneration
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/chain/types"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	coretypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/holiman/uint256"
+)
+
+// comparisonTracerResultsKey describes the key to use when storing tracer results in call message results, or when
+// querying them.
+const comparisonTracerResultsKey = "ComparisonTracerResults"
+
+const (
+	// maxComparisonOperands describes the maximum amount of unique comparison operands recorded per transaction.
+	maxComparisonOperands = 256
+
+	// maxHashInputs describes the maximum amount of unique hash inputs recorded per transaction.
+	maxHashInputs = 64
+
+	// maxHashInputSize describes the maximum size of a hash input recorded, in bytes. Larger inputs are not recorded.
+	maxHashInputSize = 256
+
+	// maxNearMissDistance describes the maximum distance between the operands of a comparison for it to be
+	// considered close to being equal.
+	maxNearMissDistance = 8
+)
+
+// ComparisonSite describes the location of a comparison instruction: a program counter within the code with a given
+// code hash.
+type ComparisonSite struct {
+	// CodeHash describes the hash of the code the comparison instruction belongs to.
+	CodeHash common.Hash
+	// PC describes the program counter of the comparison instruction.
+	PC uint64
+}
+
+// ComparisonResults describes the values a ComparisonTracer recorded during a transaction's execution.
+type ComparisonResults struct {
+	// Operands describes the unique operands of EQ, LT, GT, SLT and SGT instructions executed. Operands of signed
+	// comparisons are recorded with their signed interpretation.
+	Operands []*big.Int
+
+	// HashInputs describes the unique inputs of SHA3 instructions executed.
+	HashInputs [][]byte
+
+	// NearMisses describes the smallest distance between unequal operands of each comparison site whose operands were
+	// close to being equal, as measured by comparisonDistance.
+	NearMisses map[ComparisonSite]uint
+}
+
+// GetComparisonTracerResults obtains ComparisonResults stored by a ComparisonTracer from message results. This is nil
+// if no ComparisonResults were recorded by a tracer (e.g. ComparisonTracer was not attached during this message
+// execution).
+func GetComparisonTracerResults(messageResults *types.MessageResults) *ComparisonResults {
+	// Try to obtain the results the tracer should've stored.
+	if genericResult, ok := messageResults.AdditionalResults[comparisonTracerResultsKey]; ok {
+		if castedResult, ok := genericResult.(*ComparisonResults); ok {
+			return castedResult
+		}
+	}
+
+	// If we could not obtain them, return nil.
+	return nil
+}
+
+// RemoveComparisonTracerResults removes ComparisonResults stored by a ComparisonTracer from message results.
+func RemoveComparisonTracerResults(messageResults *types.MessageResults) {
+	delete(messageResults.AdditionalResults, comparisonTracerResultsKey)
+}
+
+// ComparisonTracer implements tracers.Tracer to collect the operands of comparisons and the inputs of hashes from EVM
+// execution traces, so they may be used in value generation (input-to-state feedback).
+type ComparisonTracer struct {
+	// results describes the values recorded for the current transaction.
+	results *ComparisonResults
+
+	// operandKeys describes the operands recorded in results, used to avoid duplicates.
+	operandKeys map[[32]byte]struct{}
+
+	// hashInputKeys describes the hash inputs recorded in results, used to avoid duplicates.
+	hashInputKeys map[string]struct{}
+
+	// nativeTracer is the underlying tracer used to capture EVM execution.
+	nativeTracer *chain.TestChainTracer
+}
+
+// NewComparisonTracer returns a new ComparisonTracer.
+func NewComparisonTracer() *ComparisonTracer {
+	tracer := &ComparisonTracer{}
+	tracer.reset()
+	nativeTracer := &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: tracer.OnTxStart,
+			OnOpcode:  tracer.OnOpcode,
+		},
+	}
+	tracer.nativeTracer = &chain.TestChainTracer{Tracer: nativeTracer, CaptureTxEndSetAdditionalResults: tracer.CaptureTxEndSetAdditionalResults}
+
+	return tracer
+}
+
+// NativeTracer returns the underlying TestChainTracer.
+func (t *ComparisonTracer) NativeTracer() *chain.TestChainTracer {
+	return t.nativeTracer
+}
+
+// reset clears the values recorded by the tracer.
+func (t *ComparisonTracer) reset() {
+	t.results = &ComparisonResults{
+		Operands:   make([]*big.Int, 0),
+		HashInputs: make([][]byte, 0),
+		NearMisses: make(map[ComparisonSite]uint),
+	}
+	t.operandKeys = make(map[[32]byte]struct{})
+	t.hashInputKeys = make(map[string]struct{})
+}
+
+// OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
+func (t *ComparisonTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
+	t.reset()
+}
+
+// OnOpcode records data from an EVM state update, as defined by tracers.Tracer.
+func (t *ComparisonTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	opCode := vm.OpCode(op)
+	switch opCode {
+	case vm.EQ, vm.LT, vm.GT, vm.SLT, vm.SGT:
+		// Obtain our operands from the top of the stack.
+		stack := scope.StackData()
+		if len(stack) < 2 {
+			return
+		}
+		x, y := &stack[len(stack)-1], &stack[len(stack)-2]
+		signed := opCode == vm.SLT || opCode == vm.SGT
+		t.addOperand(x, signed)
+		t.addOperand(y, signed)
+
+		// If the operands were unequal but close, record the comparison site as a near miss.
+		if distance := comparisonDistance(x, y, signed); distance > 0 && distance <= maxNearMissDistance {
+			// We can cast OpContext to ScopeContext because that is the type passed to OnOpcode.
+			site := ComparisonSite{CodeHash: scope.(*vm.ScopeContext).Contract.CodeHash, PC: pc}
+			if existingDistance, ok := t.results.NearMisses[site]; !ok || distance < existingDistance {
+				t.results.NearMisses[site] = distance
+			}
+		}
+	case vm.KECCAK256:
+		// Obtain the memory region to be hashed from the top of the stack.
+		stack := scope.StackData()
+		if len(stack) < 2 || len(t.results.HashInputs) >= maxHashInputs {
+			return
+		}
+		offset, size := &stack[len(stack)-1], &stack[len(stack)-2]
+		if !offset.IsUint64() || !size.IsUint64() || size.Uint64() > maxHashInputSize {
+			return
+		}
+
+		// Memory may not yet be expanded to cover the region, in which case the region is not recorded.
+		memory := scope.MemoryData()
+		start := offset.Uint64()
+		if start > uint64(len(memory)) || size.Uint64() > uint64(len(memory))-start {
+			return
+		}
+		key := string(memory[start : start+size.Uint64()])
+		if _, exists := t.hashInputKeys[key]; !exists {
+			t.hashInputKeys[key] = struct{}{}
+			t.results.HashInputs = append(t.results.HashInputs, []byte(key))
+		}
+	}
+}
+
+// addOperand records the provided comparison operand, if it was not already recorded. If signed is true, the operand
+// is recorded with its signed interpretation.
+func (t *ComparisonTracer) addOperand(operand *uint256.Int, signed bool) {
+	if len(t.results.Operands) >= maxComparisonOperands {
+		return
+	}
+	key := operand.Bytes32()
+	if _, exists := t.operandKeys[key]; exists {
+		return
+	}
+	t.operandKeys[key] = struct{}{}
+
+	value := operand.ToBig()
+	if signed && operand.Sign() < 0 {
+		value = new(big.Int).Neg(new(uint256.Int).Neg(operand).ToBig())
+	}
+	t.results.Operands = append(t.results.Operands, value)
+}
+
+// comparisonDistance measures how close the provided comparison operands are to being equal, as the smaller of the
+// amount of bits which differ between them, and the bit length of their difference. If signed is true, the difference
+// is computed with the operands' signed interpretation. Returns zero if the operands are equal.
+func comparisonDistance(x *uint256.Int, y *uint256.Int, signed bool) uint {
+	// Count the bits which differ between our operands.
+	differingBits := 0
+	xor := new(uint256.Int).Xor(x, y)
+	for _, word := range xor {
+		differingBits += bits.OnesCount64(word)
+	}
+
+	// Determine the bit length of the absolute difference between our operands.
+	var difference *uint256.Int
+	if (signed && x.Slt(y)) || (!signed && x.Lt(y)) {
+		difference = new(uint256.Int).Sub(y, x)
+	} else {
+		difference = new(uint256.Int).Sub(x, y)
+	}
+	return uint(utils.Min(differingBits, difference.BitLen()))
+}
+
+// CaptureTxEndSetAdditionalResults can be used to set additional results captured from execution tracing. If this
+// tracer is used during transaction execution (block creation), the results can later be queried from the block.
+// This method will only be called on the added tracer if it implements the extended TestChainTracer interface.
+func (t *ComparisonTracer) CaptureTxEndSetAdditionalResults(results *types.MessageResults) {
+	// Store our tracer results.
+	results.AdditionalResults[comparisonTracerResultsKey] = t.results
+}
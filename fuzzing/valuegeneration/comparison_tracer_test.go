@@ -0,0 +1,40 @@
+package valuegeneration
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestComparisonDistance ensures the distance between comparison operands is measured as the smaller of the amount of
+// differing bits and the bit length of their difference, with respect to their signedness.
+func TestComparisonDistance(t *testing.T) {
+	minusOne := new(uint256.Int).SetAllOne()
+	minusTwo := new(uint256.Int).Sub(minusOne, uint256.NewInt(1))
+	highBit := new(uint256.Int).Lsh(uint256.NewInt(1), 255)
+
+	testCases := []struct {
+		name     string
+		x        *uint256.Int
+		y        *uint256.Int
+		signed   bool
+		expected uint
+	}{
+		{name: "equal", x: uint256.NewInt(42), y: uint256.NewInt(42), expected: 0},
+		{name: "single differing bit", x: uint256.NewInt(0), y: uint256.NewInt(1), expected: 1},
+		{name: "small difference with many differing bits", x: uint256.NewInt(0x100), y: uint256.NewInt(0xff), expected: 1},
+		{name: "few differing bits with a large difference", x: highBit, y: uint256.NewInt(0), expected: 1},
+		{name: "differing bits equal difference length", x: uint256.NewInt(0xff), y: uint256.NewInt(0), expected: 8},
+		{name: "unsigned wraparound", x: minusOne, y: uint256.NewInt(0), expected: 256},
+		{name: "signed wraparound", x: minusOne, y: uint256.NewInt(0), signed: true, expected: 1},
+		{name: "unsigned negative and positive", x: minusTwo, y: uint256.NewInt(1), expected: 256},
+		{name: "signed negative and positive", x: minusTwo, y: uint256.NewInt(1), signed: true, expected: 2},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.EqualValues(t, testCase.expected, comparisonDistance(testCase.x, testCase.y, testCase.signed))
+			assert.EqualValues(t, testCase.expected, comparisonDistance(testCase.y, testCase.x, testCase.signed))
+		})
+	}
+}
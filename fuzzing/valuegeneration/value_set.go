@@ -8,18 +8,20 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/crypto/sha3"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
-// ValueSet represents potential values of significance within the source code to be used in fuzz tests.
+// ValueSet represents potential values of significance within the source code to be used in fuzz tests. Values are
+// enumerated in the order they were added, so that value generation is deterministic for a given random seed.
 type ValueSet struct {
-	// addresses represents a set of common.Address to use in fuzz tests. A mapping is used to avoid duplicates.
-	addresses map[common.Address]any
-	// integers represents a set of integers to use in fuzz tests. A mapping is used to avoid duplicates.
-	integers map[string]*big.Int
-	// strings represents a set of strings to use in fuzz tests. A mapping is used to avoid duplicates.
-	strings map[string]any
-	// bytes represents a set of bytes to use in fuzz tests. A mapping is used to avoid duplicates.
-	bytes map[string][]byte
+	// addresses represents a set of common.Address to use in fuzz tests. An ordered set is used to avoid duplicates.
+	addresses *orderedSet[common.Address, common.Address]
+	// integers represents a set of integers to use in fuzz tests. An ordered set is used to avoid duplicates.
+	integers *orderedSet[string, *big.Int]
+	// strings represents a set of strings to use in fuzz tests. An ordered set is used to avoid duplicates.
+	strings *orderedSet[string, string]
+	// bytes represents a set of bytes to use in fuzz tests. An ordered set is used to avoid duplicates.
+	bytes *orderedSet[string, []byte]
 	// hashProvider represents a hash provider used to create keys for some data.
 	hashProvider hash.Hash
 }
@@ -27,10 +29,10 @@ type ValueSet struct {
 // NewValueSet initializes a new ValueSet object for use with a Fuzzer.
 func NewValueSet() *ValueSet {
 	baseValueSet := &ValueSet{
-		addresses:    make(map[common.Address]any, 0),
-		integers:     make(map[string]*big.Int, 0),
-		strings:      make(map[string]any, 0),
-		bytes:        make(map[string][]byte, 0),
+		addresses:    newOrderedSet[common.Address, common.Address](),
+		integers:     newOrderedSet[string, *big.Int](),
+		strings:      newOrderedSet[string, string](),
+		bytes:        newOrderedSet[string, []byte](),
 		hashProvider: sha3.NewLegacyKeccak256(),
 	}
 	return baseValueSet
@@ -39,10 +41,10 @@ func NewValueSet() *ValueSet {
 // Clone creates a copy of the current ValueSet.
 func (vs *ValueSet) Clone() *ValueSet {
 	baseValueSet := &ValueSet{
-		addresses:    maps.Clone(vs.addresses),
-		integers:     maps.Clone(vs.integers),
-		strings:      maps.Clone(vs.strings),
-		bytes:        maps.Clone(vs.bytes),
+		addresses:    vs.addresses.clone(),
+		integers:     vs.integers.clone(),
+		strings:      vs.strings.clone(),
+		bytes:        vs.bytes.clone(),
 		hashProvider: sha3.NewLegacyKeccak256(),
 	}
 	return baseValueSet
@@ -50,94 +52,67 @@ func (vs *ValueSet) Clone() *ValueSet {
 
 // Addresses returns a list of addresses contained within the set.
 func (vs *ValueSet) Addresses() []common.Address {
-	res := make([]common.Address, len(vs.addresses))
-	count := 0
-	for k := range vs.addresses {
-		res[count] = k
-		count++
-	}
-	return res
+	return vs.addresses.list()
 }
 
 // AddAddress adds an address item to the ValueSet.
 func (vs *ValueSet) AddAddress(a common.Address) {
-	vs.addresses[a] = nil
+	vs.addresses.add(a, a)
 }
 
 // ContainsAddress checks if an address is contained in the ValueSet.
 func (vs *ValueSet) ContainsAddress(a common.Address) bool {
-	_, contains := vs.addresses[a]
-	return contains
+	return vs.addresses.contains(a)
 }
 
 // RemoveAddress removes an address item from the ValueSet.
 func (vs *ValueSet) RemoveAddress(a common.Address) {
-	delete(vs.addresses, a)
+	vs.addresses.remove(a)
 }
 
 // Integers returns a list of integers contained within the set.
 func (vs *ValueSet) Integers() []*big.Int {
-	res := make([]*big.Int, len(vs.integers))
-	count := 0
-	for _, v := range vs.integers {
-		res[count] = v
-		count++
-	}
-	return res
+	return vs.integers.list()
 }
 
 // AddInteger adds an integer item to the ValueSet.
 func (vs *ValueSet) AddInteger(b *big.Int) {
-	vs.integers[b.String()] = b
+	vs.integers.add(b.String(), b)
 }
 
 // ContainsInteger checks if an integer is contained in the ValueSet.
 func (vs *ValueSet) ContainsInteger(b *big.Int) bool {
-	_, contains := vs.integers[b.String()]
-	return contains
+	return vs.integers.contains(b.String())
 }
 
 // RemoveInteger removes an integer item from the ValueSet.
 func (vs *ValueSet) RemoveInteger(b *big.Int) {
-	delete(vs.integers, b.String())
+	vs.integers.remove(b.String())
 }
 
 // Strings returns a list of strings contained within the set.
 func (vs *ValueSet) Strings() []string {
-	res := make([]string, len(vs.strings))
-	count := 0
-	for k := range vs.strings {
-		res[count] = k
-		count++
-	}
-	return res
+	return vs.strings.list()
 }
 
 // AddString adds a string item to the ValueSet.
 func (vs *ValueSet) AddString(s string) {
-	vs.strings[s] = nil
+	vs.strings.add(s, s)
 }
 
 // ContainsString checks if a string is contained in the ValueSet.
 func (vs *ValueSet) ContainsString(s string) bool {
-	_, contains := vs.strings[s]
-	return contains
+	return vs.strings.contains(s)
 }
 
 // RemoveString removes a string item from the ValueSet.
 func (vs *ValueSet) RemoveString(s string) {
-	delete(vs.strings, s)
+	vs.strings.remove(s)
 }
 
 // Bytes returns a list of bytes contained within the set.
 func (vs *ValueSet) Bytes() [][]byte {
-	res := make([][]byte, len(vs.bytes))
-	count := 0
-	for _, v := range vs.bytes {
-		res[count] = v
-		count++
-	}
-	return res
+	return vs.bytes.list()
 }
 
 // AddBytes adds a byte sequence to the ValueSet.
@@ -147,8 +122,8 @@ func (vs *ValueSet) AddBytes(b []byte) {
 	hashStr := hex.EncodeToString(vs.hashProvider.Sum(nil))
 	vs.hashProvider.Reset()
 
-	// Add our hash to our "set" (map)
-	vs.bytes[hashStr] = b
+	// Add our hash to our set
+	vs.bytes.add(hashStr, b)
 }
 
 // ContainsBytes checks if a byte sequence is contained in the ValueSet.
@@ -159,8 +134,7 @@ func (vs *ValueSet) ContainsBytes(b []byte) bool {
 	vs.hashProvider.Reset()
 
 	// Check if the key exists in our lookup
-	_, contains := vs.bytes[hashStr]
-	return contains
+	return vs.bytes.contains(hashStr)
 }
 
 // RemoveBytes removes a byte sequence item from the ValueSet.
@@ -170,5 +144,69 @@ func (vs *ValueSet) RemoveBytes(b []byte) {
 	hashStr := hex.EncodeToString(vs.hashProvider.Sum(nil))
 	vs.hashProvider.Reset()
 
-	delete(vs.bytes, hashStr)
+	vs.bytes.remove(hashStr)
+}
+
+// orderedSet describes a set of values indexed by a unique key, which enumerates its values in the order they were
+// added.
+type orderedSet[K comparable, V any] struct {
+	// indexes maps each key to the index of its value in values.
+	indexes map[K]int
+	// keys describes the key of each value in values.
+	keys []K
+	// values describes the values in the set, in the order they were added.
+	values []V
+}
+
+// newOrderedSet returns a new, empty orderedSet.
+func newOrderedSet[K comparable, V any]() *orderedSet[K, V] {
+	return &orderedSet[K, V]{
+		indexes: make(map[K]int),
+		keys:    make([]K, 0),
+		values:  make([]V, 0),
+	}
+}
+
+// clone creates a copy of the orderedSet.
+func (s *orderedSet[K, V]) clone() *orderedSet[K, V] {
+	return &orderedSet[K, V]{
+		indexes: maps.Clone(s.indexes),
+		keys:    slices.Clone(s.keys),
+		values:  slices.Clone(s.values),
+	}
+}
+
+// add adds the value with the provided key to the set, if a value with that key is not already in it.
+func (s *orderedSet[K, V]) add(key K, value V) {
+	if _, exists := s.indexes[key]; exists {
+		return
+	}
+	s.indexes[key] = len(s.values)
+	s.keys = append(s.keys, key)
+	s.values = append(s.values, value)
+}
+
+// contains checks if a value with the provided key is in the set.
+func (s *orderedSet[K, V]) contains(key K) bool {
+	_, exists := s.indexes[key]
+	return exists
+}
+
+// remove removes the value with the provided key from the set, preserving the order of the remaining values.
+func (s *orderedSet[K, V]) remove(key K) {
+	index, exists := s.indexes[key]
+	if !exists {
+		return
+	}
+	delete(s.indexes, key)
+	s.keys = slices.Delete(s.keys, index, index+1)
+	s.values = slices.Delete(s.values, index, index+1)
+	for i := index; i < len(s.keys); i++ {
+		s.indexes[s.keys[i]] = i
+	}
+}
+
+// list returns a list of the values in the set, in the order they were added.
+func (s *orderedSet[K, V]) list() []V {
+	return slices.Clone(s.values)
 }
@@ -3,7 +3,9 @@ package valuegeneration
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/shopspring/decimal"
+	"golang.org/x/exp/maps"
 	"math/big"
+	"sort"
 	"strings"
 )
 
@@ -108,9 +110,11 @@ func walkAstNodes(ast any, walkFunc func(node map[string]any)) {
 			walkFunc(d)
 		}
 
-		// Walk all keys of the dictionary.
-		for _, v := range d {
-			walkAstNodes(v, walkFunc)
+		// Walk all keys of the dictionary, in sorted order so that values are seeded deterministically.
+		keys := maps.Keys(d)
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkAstNodes(d[key], walkFunc)
 		}
 	} else if slice, ok := ast.([]any); ok {
 		// Walk all elements of a slice.
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"syscall"
 )
 
 // CreateFile will create a file at the given path and file name combination. If the path is the empty string, the
@@ -79,7 +80,9 @@ func CopyFile(sourcePath string, targetPath string) error {
 	return os.Chmod(targetPath, sourceInfo.Mode())
 }
 
-// MoveFile will move a given file from the source path to the target path. Returns an error if one occured.
+// MoveFile will move a given file from the source path to the target path. If the target path is on a different
+// filesystem than the source path, the file is copied and the source file deleted instead. Returns an error if one
+// occured, or an error wrapping os.ErrExist if a file already exists at the target path.
 func MoveFile(sourcePath string, targetPath string) error {
 	// Obtain file info for the source file
 	sourceInfo, err := os.Stat(sourcePath)
@@ -92,6 +95,14 @@ func MoveFile(sourcePath string, targetPath string) error {
 		return fmt.Errorf("could not copy file from '%s' to '%s' because the source path refers to a directory", sourcePath, targetPath)
 	}
 
+	// If a file already exists at the target path, return an error rather than overwriting it
+	_, err = os.Lstat(targetPath)
+	if err == nil {
+		return fmt.Errorf("could not move file from '%s' to '%s' because the target path already exists: %w", sourcePath, targetPath, os.ErrExist)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
 	// Ensure the existence of the directory we wish to copy to.
 	targetDirectory := filepath.Dir(targetPath)
 	err = os.MkdirAll(targetDirectory, 0777)
@@ -99,13 +110,46 @@ func MoveFile(sourcePath string, targetPath string) error {
 		return err
 	}
 
-	// Move the file from the source path to the target path
+	// Move the file from the source path to the target path. Files cannot be renamed across filesystems, so in that
+	// case we copy the file and delete the source file instead.
 	err = os.Rename(sourcePath, targetPath)
+	if errors.Is(err, syscall.EXDEV) {
+		return moveFileAcrossFilesystems(sourcePath, targetPath, sourceInfo.Mode())
+	}
+	return err
+}
+
+// moveFileAcrossFilesystems moves a given file from the source path to a target path on a different filesystem, by
+// copying it to the target path and deleting the source file. The target file is created with the provided
+// permissions. Returns an error if one occurred, or an error wrapping os.ErrExist if a file already exists at the
+// target path.
+func moveFileAcrossFilesystems(sourcePath string, targetPath string, mode os.FileMode) error {
+	// Open a handle to the source file
+	sourceFile, err := os.Open(sourcePath)
 	if err != nil {
 		return err
 	}
+	defer sourceFile.Close()
 
-	return nil
+	// Create the target file, ensuring we do not overwrite an existing one
+	targetFile, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+
+	// Copy contents from one file handle to the other, removing the partially copied file if we fail to
+	_, err = io.Copy(targetFile, sourceFile)
+	if closeErr := targetFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(targetPath)
+		return err
+	}
+
+	// Delete the source file now that it was copied, closing it first as open files cannot be deleted on all platforms
+	sourceFile.Close()
+	return os.Remove(sourcePath)
 }
 
 // GetFileNameWithoutExtension obtains a filename without the extension. This does not contain any preceding directory
@@ -2,11 +2,16 @@ package utils
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"os/exec"
 	"runtime"
+	"time"
 )
 
+// ErrCommandTimedOut is returned by RunCommandWithTimeout when a command did not complete before its timeout elapsed.
+var ErrCommandTimedOut = errors.New("command timed out")
+
 // RunCommandWithOutputAndError runs a given exec.Cmd and returns the stdout, stderr, and
 // combined output as bytes, or an error if one occurred.
 func RunCommandWithOutputAndError(command *exec.Cmd) ([]byte, []byte, []byte, error) {
@@ -28,6 +33,74 @@ func RunCommandWithOutputAndError(command *exec.Cmd) ([]byte, []byte, []byte, er
 	return bStdout.Bytes(), bStderr.Bytes(), bCombined.Bytes(), err
 }
 
+// RunCommandWithTimeout runs a given exec.Cmd and returns the stdout, stderr, and combined output as bytes, or an
+// error if one occurred. If the command does not complete within the provided timeout, its process is killed and
+// ErrCommandTimedOut is returned. A non-positive timeout indicates the command should not be timed out.
+func RunCommandWithTimeout(command *exec.Cmd, timeout time.Duration) ([]byte, []byte, []byte, error) {
+	// If no timeout was provided, simply run the command.
+	if timeout <= 0 {
+		return RunCommandWithOutputAndError(command)
+	}
+
+	// Create our buffers to capture output and errors.
+	var bStdout, bStderr, bCombined bytes.Buffer
+
+	// Create multi writers to capture output into individual and combined buffers
+	stdoutMulti := io.MultiWriter(&bStdout, &bCombined)
+	stderrMulti := io.MultiWriter(&bStderr, &bCombined)
+
+	// Set our writers
+	command.Stdout = stdoutMulti
+	command.Stderr = stderrMulti
+
+	// Ensure that if the process is killed, any children still holding our output pipes do not block us indefinitely.
+	if command.WaitDelay == 0 {
+		command.WaitDelay = time.Second
+	}
+
+	// Start the command
+	err := command.Start()
+	if err != nil {
+		return bStdout.Bytes(), bStderr.Bytes(), bCombined.Bytes(), err
+	}
+
+	// Wait for the command to complete in the background, so we can enforce our timeout.
+	done := make(chan error, 1)
+	go func() {
+		done <- command.Wait()
+	}()
+
+	// Wait for the command to exit or the timeout to elapse, killing the process in the latter case.
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err = <-done:
+	case <-timer.C:
+		_ = command.Process.Kill()
+		<-done
+		err = ErrCommandTimedOut
+	}
+
+	// Return our results
+	return bStdout.Bytes(), bStderr.Bytes(), bCombined.Bytes(), err
+}
+
+// GetCommandExitCode obtains the exit code of a command from the error returned when running it. If the error is
+// nil, zero is returned. If the error does not describe a process exit status, -1 is returned.
+func GetCommandExitCode(err error) int {
+	// A nil error indicates the command exited successfully.
+	if err == nil {
+		return 0
+	}
+
+	// If the process exited with a non-zero status, return it.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
 // IsWindowsEnvironment returns a boolean indicating whether the current execution environment is a Windows platform.
 func IsWindowsEnvironment() bool {
 	return runtime.GOOS == "windows"
@@ -78,6 +78,21 @@ func (c *WeightedRandomChooser[T]) AddChoices(choices ...*WeightedRandomChoice[T
 	c.choices = append(c.choices, choices...)
 }
 
+// UpdateWeights sets the weight of every choice in the WeightedRandomChooser to the weight returned by the provided
+// function for its underlying data.
+func (c *WeightedRandomChooser[T]) UpdateWeights(weightFunc func(data T) *big.Int) {
+	// Acquire our lock during the duration of this method.
+	c.randomProviderLock.Lock()
+	defer c.randomProviderLock.Unlock()
+
+	// Update each choice's weight and recompute our total weight.
+	c.totalWeight = big.NewInt(0)
+	for _, choice := range c.choices {
+		choice.weight = new(big.Int).Set(weightFunc(choice.Data))
+		c.totalWeight = new(big.Int).Add(c.totalWeight, choice.weight)
+	}
+}
+
 // Choose selects a random weighted item from the WeightedRandomChooser, or returns an error if one occurs.
 func (c *WeightedRandomChooser[T]) Choose() (*T, error) {
 	// If we have no choices or 0 total weight, return nil.